@@ -0,0 +1,142 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/bodylimit"
+    "github.com/gmstcl/eCommerce-System/internal/chaos"
+    "github.com/gmstcl/eCommerce-System/internal/dbreconnect"
+    "github.com/gmstcl/eCommerce-System/internal/ids"
+    "github.com/gmstcl/eCommerce-System/internal/metrics"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+    "github.com/gmstcl/eCommerce-System/internal/sqlq"
+    "github.com/jmoiron/sqlx"
+)
+
+// 다중 행 INSERT의 placeholder 수가 MySQL 한도를 넘지 않도록 제한함
+const maxCustomerBatch = 500
+
+type batchItemResult struct {
+    Index  int    `json:"index"`
+    ID     string `json:"id"`
+    Status int    `json:"status"`
+    Error  string `json:"error,omitempty"`
+}
+
+func createCustomersBatch(c *gin.Context) {
+    ctx := c.Request.Context()
+    var customers []Customer
+    if err := c.ShouldBindJSON(&customers); err != nil {
+        if bodylimit.TooLarge(c, err) {
+            return
+        }
+        metrics.RecordValidationFailure(c, err)
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, err.Error())
+        return
+    }
+
+    if len(customers) == 0 || len(customers) > maxCustomerBatch {
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, fmt.Sprintf("batch must contain between 1 and %d customers", maxCustomerBatch))
+        return
+    }
+
+    results := make([]batchItemResult, len(customers))
+    var validIdx []int
+    seen := make(map[string]bool)
+    for i := range customers {
+        customer := &customers[i]
+        results[i] = batchItemResult{Index: i, ID: customer.ID}
+        idErr := ids.Check("id", customer.ID)
+        switch {
+        case idErr != nil:
+            metrics.RecordValidationField(c, "id")
+            results[i].Status = http.StatusBadRequest
+            results[i].Error = idErr.Error()
+        case !normalizeGender(customer):
+            metrics.RecordValidationField(c, "gender")
+            results[i].Status = http.StatusBadRequest
+            results[i].Error = genderError()
+        case seen[customer.ID]:
+            results[i].Status = http.StatusBadRequest
+            results[i].Error = "duplicate id in batch"
+        default:
+            seen[customer.ID] = true
+            validIdx = append(validIdx, i)
+        }
+    }
+
+    // 행마다 따로 INSERT해 한 행의 중복 키가 나머지 행을 되돌리지 않도록 함. 이미 있는 id는 409
+    var created []Customer
+    for _, i := range validIdx {
+        err := saveToDB(ctx, &customers[i])
+        switch {
+        case dbreconnect.IsDuplicateKey(err):
+            results[i].Status = http.StatusConflict
+            results[i].Error = "customer already exists"
+        case err != nil:
+            results[i].Status = backend.Status(err)
+            results[i].Error = "failed to save to DB"
+        default:
+            results[i].Status = http.StatusCreated
+            created = append(created, customers[i])
+        }
+    }
+    if len(created) > 0 {
+        saveBatchToCache(ctx, created)
+    }
+
+    respond.JSON(c, http.StatusMultiStatus, gin.H{"results": results})
+}
+
+// 캐시에도 같은 값이 들어가도록 INSERT 전에 호출하는 쪽에서 시각을 채움
+func stampNewCustomers(customers []Customer) {
+    now := recordTimestamp()
+    for i := range customers {
+        customers[i].CreatedAt = now
+        customers[i].UpdatedAt = now
+    }
+}
+
+// 다중 행 INSERT 한 번. 호출하는 쪽에서 maxCustomerBatch 이하로 나눠서 넘김
+func insertCustomersTx(ctx context.Context, tx *sqlx.Tx, customers []Customer) error {
+    query := sqlq.New("INSERT INTO customers (id, name, gender, created_at, updated_at) VALUES ")
+    for i, customer := range customers {
+        if i > 0 {
+            query.Add(", ")
+        }
+        query.Add("(?, ?, ?, ?, ?)", customer.ID, customer.Name, customer.Gender, customer.CreatedAt, customer.UpdatedAt)
+    }
+    _, err := query.ExecContext(ctx, tx)
+    return err
+}
+
+func saveBatchToCache(ctx context.Context, customers []Customer) {
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosCacheFailRate); err != nil {
+        logger.ErrorContext(ctx, "Failed to save batch of customers to cache", "count", len(customers), "error", err)
+        return
+    }
+
+    pipe := redisConn.Client().Pipeline()
+    for _, customer := range customers {
+        data, err := json.Marshal(customer)
+        if err != nil {
+            logger.ErrorContext(ctx, "Failed to marshal customer", "error", err)
+            continue
+        }
+        pipe.Set(ctx, customer.ID, data, cacheTTL)
+    }
+
+    if _, err := pipe.Exec(ctx); err != nil {
+        logger.ErrorContext(ctx, "Failed to save batch of customers to cache", "count", len(customers), "error", err)
+    } else {
+        logger.InfoContext(ctx, "Successfully saved batch of customers to cache", "count", len(customers))
+    }
+}
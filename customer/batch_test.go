@@ -0,0 +1,47 @@
+package main
+
+import (
+    "encoding/json"
+    "net/http"
+    "testing"
+)
+
+// 이미 있는 id는 그 행만 409이고 나머지 행은 저장되어 201
+func TestBatchCreateReportsConflictPerRow(t *testing.T) {
+    conn := useTestDB(t)
+    useMiniredis(t)
+    if _, err := conn.Exec("INSERT INTO customers (id, name, gender, created_at, updated_at) VALUES ('c2', 'bob', 'male', NOW(), NOW())"); err != nil {
+        t.Fatal(err)
+    }
+
+    batch := []map[string]string{
+        {"id": "c1", "name": "alice", "gender": "female"},
+        {"id": "c2", "name": "bobby", "gender": "male"},
+        {"id": "c3", "name": "carol", "gender": "female"},
+    }
+    w := doRequest(newRouter(), http.MethodPost, "/v1/customers/batch", batch, nil)
+    if w.Code != http.StatusMultiStatus {
+        t.Fatalf("status %d, want 207 (%s)", w.Code, w.Body)
+    }
+
+    var resp struct {
+        Results []batchItemResult `json:"results"`
+    }
+    if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+        t.Fatal(err)
+    }
+    want := []int{http.StatusCreated, http.StatusConflict, http.StatusCreated}
+    for i, result := range resp.Results {
+        if result.Status != want[i] {
+            t.Errorf("item %d: status %d, want %d (%s)", i, result.Status, want[i], result.Error)
+        }
+    }
+
+    var names []string
+    if err := conn.Select(&names, "SELECT name FROM customers ORDER BY id"); err != nil {
+        t.Fatal(err)
+    }
+    if len(names) != 3 || names[0] != "alice" || names[1] != "bob" || names[2] != "carol" {
+        t.Errorf("stored names %v, want [alice bob carol]", names)
+    }
+}
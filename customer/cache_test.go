@@ -0,0 +1,81 @@
+package main
+
+import (
+    "context"
+    "net/http"
+    "strings"
+    "testing"
+    "time"
+)
+
+// 캐시에 쓸 때 CACHE_TTL_SECONDS로 정한 만료 시간을 함께 넘김
+func TestSaveToCacheAppliesTTL(t *testing.T) {
+    mr := useMiniredis(t)
+    prev := cacheTTL
+    cacheTTL = 42 * time.Second
+    t.Cleanup(func() { cacheTTL = prev })
+
+    saveToCache(context.Background(), &Customer{ID: "c1", Name: "alice", Gender: "female"})
+
+    if !mr.Exists("c1") {
+        t.Fatal("entry was not cached")
+    }
+    if ttl := mr.TTL("c1"); ttl != 42*time.Second {
+        t.Errorf("TTL %v, want 42s", ttl)
+    }
+}
+
+// 수정하면 캐시 항목을 지워 다음 조회가 DB의 새 값을 읽음
+func TestUpdateCustomerInvalidatesCache(t *testing.T) {
+    mr := useMiniredis(t)
+    useTestDB(t)
+    router := newRouter()
+
+    body := map[string]interface{}{"id": "c1", "name": "alice", "gender": "female"}
+    if w := doRequest(router, http.MethodPost, "/v1/customer", body, nil); w.Code != http.StatusCreated {
+        t.Fatalf("create: status %d, want 201 (%s)", w.Code, w.Body)
+    }
+    if w := doRequest(router, http.MethodGet, "/v1/customer?id=c1", nil, nil); w.Code != http.StatusOK || !mr.Exists("c1") {
+        t.Fatalf("first get: status %d, cached %v, want 200 and cached", w.Code, mr.Exists("c1"))
+    }
+
+    body["name"] = "alicia"
+    if w := doRequest(router, http.MethodPut, "/v1/customer", body, nil); w.Code != http.StatusOK {
+        t.Fatalf("update: status %d, want 200 (%s)", w.Code, w.Body)
+    }
+    if mr.Exists("c1") {
+        t.Error("cache entry survived update")
+    }
+    w := doRequest(router, http.MethodGet, "/v1/customer?id=c1", nil, nil)
+    if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"alicia"`) {
+        t.Errorf("get after update: status %d body %s, want alicia", w.Code, w.Body)
+    }
+}
+
+// 삭제하면 캐시 항목도 지워 삭제된 customer를 캐시에서 돌려주지 않음
+func TestDeleteCustomerInvalidatesCache(t *testing.T) {
+    mr := useMiniredis(t)
+    useTestDB(t)
+    router := newRouter()
+
+    body := map[string]interface{}{"id": "c1", "name": "alice", "gender": "female"}
+    if w := doRequest(router, http.MethodPost, "/v1/customer", body, nil); w.Code != http.StatusCreated {
+        t.Fatalf("create: status %d, want 201 (%s)", w.Code, w.Body)
+    }
+    if w := doRequest(router, http.MethodGet, "/v1/customer?id=c1", nil, nil); w.Code != http.StatusOK || !mr.Exists("c1") {
+        t.Fatalf("first get: status %d, cached %v, want 200 and cached", w.Code, mr.Exists("c1"))
+    }
+
+    if w := doRequest(router, http.MethodDelete, "/v1/customer?id=c1", nil, nil); w.Code != http.StatusNoContent {
+        t.Fatalf("delete: status %d, want 204 (%s)", w.Code, w.Body)
+    }
+    if mr.Exists("c1") {
+        t.Error("cache entry survived delete")
+    }
+    if w := doRequest(router, http.MethodGet, "/v1/customer?id=c1", nil, nil); w.Code != http.StatusNotFound {
+        t.Errorf("get after delete: status %d, want 404 (%s)", w.Code, w.Body)
+    }
+    if w := doRequest(router, http.MethodDelete, "/v1/customer?id=c1", nil, nil); w.Code != http.StatusNotFound {
+        t.Errorf("second delete: status %d, want 404 (%s)", w.Code, w.Body)
+    }
+}
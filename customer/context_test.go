@@ -0,0 +1,125 @@
+package main
+
+import (
+    "context"
+    "database/sql"
+    "database/sql/driver"
+    "errors"
+    "testing"
+    "time"
+
+    "github.com/jmoiron/sqlx"
+)
+
+// Connector로만 여는 테스트 드라이버가 Driver()로 돌려주는 값
+type connectorDriver struct{}
+
+func (connectorDriver) Open(string) (driver.Conn, error) {
+    return nil, errors.New("use the connector")
+}
+
+// 준비된 문장의 쿼리가 ctx가 끝나거나 release가 닫힐 때까지 멈춰 있는 드라이버. 느린 MySQL을 흉내 냄
+type blockingConnector struct {
+    started chan struct{}
+    release chan struct{}
+}
+
+func (b *blockingConnector) Connect(context.Context) (driver.Conn, error) {
+    return &blockingConn{b}, nil
+}
+
+func (b *blockingConnector) Driver() driver.Driver {
+    return connectorDriver{}
+}
+
+type blockingConn struct {
+    b *blockingConnector
+}
+
+func (c *blockingConn) Prepare(string) (driver.Stmt, error) {
+    return &blockingStmt{c.b}, nil
+}
+
+func (c *blockingConn) Close() error {
+    return nil
+}
+
+func (c *blockingConn) Begin() (driver.Tx, error) {
+    return nil, errors.New("transactions not supported")
+}
+
+type blockingStmt struct {
+    b *blockingConnector
+}
+
+func (s *blockingStmt) Close() error {
+    return nil
+}
+
+func (s *blockingStmt) NumInput() int {
+    return -1
+}
+
+func (s *blockingStmt) Exec([]driver.Value) (driver.Result, error) {
+    return nil, errors.New("exec not supported")
+}
+
+func (s *blockingStmt) Query([]driver.Value) (driver.Rows, error) {
+    return nil, errors.New("use QueryContext")
+}
+
+func (s *blockingStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+    select {
+    case s.b.started <- struct{}{}:
+    default:
+    }
+    select {
+    case <-ctx.Done():
+        return nil, ctx.Err()
+    case <-s.b.release:
+        return nil, errors.New("released")
+    }
+}
+
+// 조회 문장을 멈춰 있는 드라이버로 준비해 끼움. 테스트가 끝나면 멈춘 쿼리를 풀어 줌
+func useBlockingDB(t *testing.T) *blockingConnector {
+    t.Helper()
+    b := &blockingConnector{started: make(chan struct{}, 1), release: make(chan struct{})}
+    conn := sqlx.NewDb(sql.OpenDB(b), "mysql")
+    stmt, err := conn.Preparex("SELECT id, name, gender, created_at, updated_at FROM customers WHERE id = ?")
+    if err != nil {
+        t.Fatal(err)
+    }
+    prevDB, prevStmt := db, selectCustomerStmt
+    db, selectCustomerStmt = conn, stmt
+    t.Cleanup(func() {
+        close(b.release)
+        db, selectCustomerStmt = prevDB, prevStmt
+        conn.Close()
+    })
+    return b
+}
+
+// 요청이 취소되면 DB 응답을 기다리지 않고 바로 context.Canceled를 반환함
+func TestCancelledContextAbortsGetFromDB(t *testing.T) {
+    blocking := useBlockingDB(t)
+    ctx, cancel := context.WithCancel(context.Background())
+
+    done := make(chan error, 1)
+    go func() {
+        _, err := getFromDB(ctx, "c1")
+        done <- err
+    }()
+
+    <-blocking.started
+    cancel()
+
+    select {
+    case err := <-done:
+        if !errors.Is(err, context.Canceled) {
+            t.Errorf("getFromDB error %v, want context.Canceled", err)
+        }
+    case <-time.After(time.Second):
+        t.Fatal("getFromDB did not return after the context was cancelled")
+    }
+}
@@ -3,25 +3,94 @@ package main
 
 import (
     "context"
-    "crypto/tls"
-    "encoding/json"
+    "embed"
+    "database/sql"
+    "errors"
+    "flag"
     "fmt"
     "log"
     "net/http"
     "os"
+    "strconv"
+    "time"
 
+    "github.com/aws/aws-sdk-go-v2/aws"
     "github.com/aws/aws-sdk-go-v2/config"
     "github.com/aws/aws-sdk-go-v2/service/rdsdata"
     "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/apikey"
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/bodylimit"
+    "github.com/gmstcl/eCommerce-System/internal/cacheaside"
+    "github.com/gmstcl/eCommerce-System/internal/cachestats"
+    "github.com/gmstcl/eCommerce-System/internal/chaos"
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+    "github.com/gmstcl/eCommerce-System/internal/cors"
+    "github.com/gmstcl/eCommerce-System/internal/dbreconnect"
+    "github.com/gmstcl/eCommerce-System/internal/env"
+    "github.com/gmstcl/eCommerce-System/internal/healthz"
+    "github.com/gmstcl/eCommerce-System/internal/ids"
+    "github.com/gmstcl/eCommerce-System/internal/jwtauth"
+    "github.com/gmstcl/eCommerce-System/internal/logging"
+    "github.com/gmstcl/eCommerce-System/internal/metrics"
+    "github.com/gmstcl/eCommerce-System/internal/migrate"
+    "github.com/gmstcl/eCommerce-System/internal/redisconn"
+    "github.com/gmstcl/eCommerce-System/internal/requestid"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+    "github.com/gmstcl/eCommerce-System/internal/server"
+    "github.com/gmstcl/eCommerce-System/internal/sqlq"
+    "github.com/gmstcl/eCommerce-System/internal/tracing"
+    "go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+    "github.com/prometheus/client_golang/prometheus/promhttp"
     "github.com/go-redis/redis/v8"
     "github.com/jmoiron/sqlx"
     _ "github.com/go-sql-driver/mysql"
+    "golang.org/x/sync/singleflight"
 )
 
 var db *sqlx.DB
-var redisClient *redis.Client
 var rdsClient *rdsdata.Client
-var ctx = context.Background()
+var cacheTTL = 300 * time.Second
+var dbReads singleflight.Group
+var redisConn redisconn.Conn
+
+// 스키마 변경은 migrations/에 새 번호의 파일로 추가함. 실행 규칙은 internal/migrate에 있음
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// 요청마다 SQL을 다시 파싱하지 않도록 시작 시 한 번 준비해 재사용함
+var (
+    selectCustomerStmt *sqlx.Stmt
+    insertCustomerStmt *sqlx.Stmt
+    updateCustomerStmt *sqlx.Stmt
+    deleteCustomerStmt *sqlx.Stmt
+)
+
+const serviceName = "customer"
+
+var logger = logging.New(serviceName)
+
+// 게임데이용 장애 주입. 환경 변수가 없거나 0이면 아무 동작도 하지 않음
+var (
+    chaosDBFailRate    = chaos.Rate(logger, "CHAOS_DB_FAIL_RATE")
+    chaosCacheFailRate = chaos.Rate(logger, "CHAOS_CACHE_FAIL_RATE")
+)
+
+// 이 중 하나라도 비어 있으면 시작하지 않음
+var requiredEnv = []string{"MYSQL_USER", "MYSQL_HOST", "MYSQL_PORT", "MYSQL_DBNAME", "REDIS_HOST", "REDIS_PORT"}
+
+// API_KEYS가 비어 있으면 인증을 하지 않음 (로컬 개발용)
+var apiKeys = apikey.Parse(os.Getenv("API_KEYS"))
+
+// /healthz가 확인하는 의존성. Critical이 아닌 의존성은 실패해도 degraded로만 보고함
+var healthChecks = map[string]healthz.Check{
+    "mysql": {Run: func(ctx context.Context) error {
+        return db.PingContext(ctx)
+    }, Critical: true},
+    "redis": {Run: func(ctx context.Context) error {
+        return redisConn.Check(ctx)
+    }},
+}
 
 var (
     mysqlUser     = os.Getenv("MYSQL_USER")
@@ -31,155 +100,517 @@ var (
     mysqlDbName   = os.Getenv("MYSQL_DBNAME")
     redisAddr     = os.Getenv("REDIS_HOST")
     redisPort     = os.Getenv("REDIS_PORT")
-    region        = os.Getenv("AWS_REGION")
+    region        string
 )
 
+// created_at/updated_at은 서버가 쓰기 시점에 채우며 요청 본문의 값은 무시함.
+// 컬럼은 migrations/에서 추가함
 type Customer struct {
-    ID     string `json:"id"`
-    Name   string `json:"name"`
-    Gender string `json:"gender"`
+    ID        string    `json:"id"`
+    Name      string    `json:"name"`
+    Gender    string    `json:"gender"`
+    CreatedAt time.Time `json:"createdat" db:"created_at"`
+    UpdatedAt time.Time `json:"updatedat" db:"updated_at"`
+}
+
+// MySQL DATETIME(6)에 저장되는 정밀도에 맞춰 응답과 DB 값이 같도록 함
+func recordTimestamp() time.Time {
+    return clock.Now().UTC().Truncate(time.Microsecond)
 }
 
-func init() {
+// 테스트가 환경 변수와 AWS 자격 증명 없이 패키지를 불러올 수 있도록 init 대신 main에서 호출함
+func initService() {
+    env.Require(requiredEnv)
+    region = env.Region(logger)
+
     cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
     if err != nil {
         log.Fatalf("unable to load SDK config, %v", err)
     }
+    checkAWSCredentials(cfg)
     rdsClient = rdsdata.NewFromConfig(cfg)
 
-    redisClient = redis.NewClient(&redis.Options{
+    // 0이나 잘못된 값이면 만료 없는 캐시 대신 기본 TTL을 사용함
+    if v := os.Getenv("CACHE_TTL_SECONDS"); v != "" {
+        if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+            cacheTTL = time.Duration(seconds) * time.Second
+        } else {
+            logger.Warn("Ignoring invalid CACHE_TTL_SECONDS", "value", v, "cache_ttl", cacheTTL.String())
+        }
+    }
+
+    redisDB := 0
+    if v := os.Getenv("REDIS_DB"); v != "" {
+        redisDB, err = strconv.Atoi(v)
+        if err != nil || redisDB < 0 {
+            log.Fatalf("invalid REDIS_DB %q (want a non-negative integer)", v)
+        }
+    }
+
+    redisOptions := &redis.Options{
         Addr:     fmt.Sprintf("%s:%s", redisAddr, redisPort),
-        TLSConfig: &tls.Config{},  
-    })
+        DB:       redisDB,
+        TLSConfig: redisconn.TLSConfig(),
+    }
+    redisconn.ApplyPoolOptions(redisOptions)
 
-    checkRedisConnection() 
+    respond.InitKeyStyle()
+    redisConn.Connect(redisOptions)
+    logEffectiveConfig()
 }
 
-func checkRedisConnection() {
-    _, err := redisClient.Ping(ctx).Result()
-    if err != nil {
-        log.Printf("Redis connection error: %v", err)
-    } else {
-        log.Println("Connected to Redis successfully")
+func logEffectiveConfig() {
+    logger.Info("effective config",
+        "mysql", fmt.Sprintf("%s@%s:%s/%s", mysqlUser, mysqlHost, mysqlPort, mysqlDbName),
+        "mysql_password", maskSecret(mysqlPassword),
+        "redis", fmt.Sprintf("%s:%s/%d", redisAddr, redisPort, redisConn.Options().DB),
+        "redis_tls", redisConn.Options().TLSConfig != nil,
+        "redis_pool_size", redisConn.Options().PoolSize,
+        "redis_dial_timeout", redisConn.Options().DialTimeout.String(),
+        "redis_read_timeout", redisConn.Options().ReadTimeout.String(),
+        "cache_ttl", cacheTTL.String(),
+        "backend_timeout", backend.Timeout.String(),
+        "json_key_style", respond.KeyStyle,
+        "db_reconnect_retries", dbreconnect.Retries,
+        "api_key_auth", len(apiKeys) > 0,
+        "cors_origins", cors.AllowedOrigins,
+        "max_body_bytes", bodylimit.Max,
+        "max_batch_body_bytes", bodylimit.MaxBatch,
+        "jwt_auth", jwtauth.Enabled(),
+        "aws_region", region,
+        "order_service", orderServiceURL,
+    )
+}
+
+func maskSecret(v string) string {
+    if v == "" {
+        return "(unset)"
+    }
+    return "****"
+}
+
+// LoadDefaultConfig는 자격 증명이 없어도 성공하므로 시작 시점에 한 번 확인함.
+// AWS_CREDENTIALS_REQUIRED=true이면 경고 대신 종료함
+func checkAWSCredentials(cfg aws.Config) {
+    credCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+
+    if _, err := cfg.Credentials.Retrieve(credCtx); err != nil {
+        if os.Getenv("AWS_CREDENTIALS_REQUIRED") == "true" {
+            log.Fatalf("no AWS credentials could be resolved: %v", err)
+        }
+        logger.Warn("no AWS credentials could be resolved, AWS calls will fail", "error", err)
     }
 }
 
 func main() {
+    initService()
+
     var err error
-    dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", mysqlUser, mysqlPassword, mysqlHost, mysqlPort, mysqlDbName)
+    // clientFoundRows: 값이 같아 바뀌지 않은 행도 UPDATE 결과에 포함시켜 404 판단에 씀
+    // parseTime: created_at/updated_at을 time.Time으로 읽음
+    dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?clientFoundRows=true&parseTime=true", mysqlUser, mysqlPassword, mysqlHost, mysqlPort, mysqlDbName)
 
     db, err = sqlx.Connect("mysql", dsn)
     if err != nil {
         log.Fatalf("failed to connect to RDS: %v", err)
     }
+    // 준비된 문장이 참조하는 테이블이 있어야 하므로 prepareStatements보다 먼저 실행함
+    if migrate.OnStart() {
+        if err := migrate.Run(context.Background(), db, migrationFiles); err != nil {
+            log.Fatalf("failed to run migrations: %v", err)
+        }
+    }
+    prepareStatements()
+
+    flag.Parse()
+    if selfTestRequested() {
+        os.Exit(runSelfTest())
+    }
+
+    stopRedisMonitor := redisConn.StartMonitor()
+
+    stopTracing := tracing.Init(serviceName, logger)
+
+    server.Run(newRouter(), func() {
+        selectCustomerStmt.Close()
+        insertCustomerStmt.Close()
+        updateCustomerStmt.Close()
+        deleteCustomerStmt.Close()
+        if err := db.Close(); err != nil {
+            logger.Error("Failed to close DB", "error", err)
+        }
+        stopRedisMonitor()
+        if err := redisConn.Client().Close(); err != nil {
+            logger.Error("Failed to close Redis client", "error", err)
+        }
+        stopTracing()
+    })
+}
 
+func newRouter() *gin.Engine {
     router := gin.Default()
+    router.Use(otelgin.Middleware(serviceName))
+    router.Use(requestid.Middleware())
+    router.Use(cors.Middleware())
+    router.Use(bodylimit.Middleware())
+    // gin은 등록 시점까지의 미들웨어만 붙이므로 스크레이퍼가 키나 토큰 없이 읽도록 인증보다 먼저 등록함
+    router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+    router.Use(metrics.Middleware())
+    router.Use(jwtauth.Middleware(len(apiKeys) > 0))
+    router.Use(apikey.Middleware(apiKeys, func(c *gin.Context) bool { return jwtauth.Subject(c) != "" }))
 
     router.GET("/v1/customer", getCustomer)
     router.POST("/v1/customer", createCustomer)
+    router.PUT("/v1/customer", updateCustomer)
+    router.DELETE("/v1/customer", deleteCustomer)
+    router.GET("/v1/customer/export", exportCustomer)
+    router.GET("/v1/customers", listCustomers)
+    router.POST("/v1/customers/batch", createCustomersBatch)
+    router.POST("/v1/customers/import", importCustomers)
+    router.GET("/healthz", healthz.Handler(healthChecks))
+    router.GET("/v1/cache/report", cachestats.Report)
+    return router
+}
+
+func prepareStatements() {
+    selectCustomerStmt = mustPrepare(sqlq.Statement("SELECT id, name, gender, created_at, updated_at FROM customers WHERE id = ?"))
+    insertCustomerStmt = mustPrepare(sqlq.Statement("INSERT INTO customers (id, name, gender, created_at, updated_at) VALUES (?, ?, ?, ?, ?)"))
+    updateCustomerStmt = mustPrepare(sqlq.Statement("UPDATE customers SET name = ?, gender = ?, updated_at = ? WHERE id = ?"))
+    deleteCustomerStmt = mustPrepare(sqlq.Statement("DELETE FROM customers WHERE id = ?"))
+}
 
-    router.Run(":8080")
+// sqlq.Statement의 결과를 그대로 받아 준비함. 시작할 때만 호출하므로 실패하면 종료함
+func mustPrepare(query string, err error) *sqlx.Stmt {
+    if err == nil {
+        var stmt *sqlx.Stmt
+        if stmt, err = db.Preparex(query); err == nil {
+            return stmt
+        }
+    }
+    log.Fatalf("failed to prepare statement %q: %v", query, err)
+    return nil
 }
 
 func getCustomer(c *gin.Context) {
+    ctx := c.Request.Context()
     customerID := c.DefaultQuery("id", "")
 
-    customerData, err := getFromCache(customerID)
+    customerData, err := getFromCache(ctx, customerID)
     if err != nil {
-        log.Printf("Failed to fetch from cache for customerID %s: %v", customerID, err)
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch from cache"})
+        logger.ErrorContext(ctx, "Failed to fetch from cache", "customer_id", customerID, "error", err)
+        respond.BackendError(c, err, "failed to fetch from cache")
         return
     }
 
     if customerData != nil {
-        c.JSON(http.StatusOK, customerData)
+        respond.JSON(c, http.StatusOK, customerData)
         return
     }
 
-    customerData, err = getFromDB(customerID)
+    customerData, err = getFromDB(ctx, customerID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respond.Error(c, http.StatusNotFound, respond.CodeNotFound, "customer not found")
+        return
+    }
     if err != nil {
-        log.Printf("Failed to fetch from DB for customerID %s: %v", customerID, err)
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch from DB"})
+        logger.ErrorContext(ctx, "Failed to fetch from DB", "customer_id", customerID, "error", err)
+        respond.BackendError(c, err, "failed to fetch from DB")
         return
     }
 
-    saveToCache(customerData)
+    saveToCache(ctx, customerData)
 
-    c.JSON(http.StatusOK, customerData)
+    respond.JSON(c, http.StatusOK, customerData)
 }
 
 func createCustomer(c *gin.Context) {
+    ctx := c.Request.Context()
     var customer Customer
     if err := c.ShouldBindJSON(&customer); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        if bodylimit.TooLarge(c, err) {
+            return
+        }
+        metrics.RecordValidationFailure(c, err)
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, err.Error())
+        return
+    }
+    if err := ids.Check("id", customer.ID); err != nil {
+        metrics.RecordValidationField(c, "id")
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, err.Error())
+        return
+    }
+    if !validateCustomer(c, &customer) {
         return
     }
 
-    if err := saveToDB(&customer); err != nil {
-        log.Printf("Failed to save to DB for customerID %s: %v", customer.ID, err)
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save to DB"})
+    err := saveToDB(ctx, &customer)
+    if dbreconnect.IsDuplicateKey(err) {
+        respond.Error(c, http.StatusConflict, respond.CodeConflict, "customer already exists")
+        return
+    }
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to save to DB", "customer_id", customer.ID, "error", err)
+        respond.BackendError(c, err, "failed to save to DB")
         return
     }
 
-    saveToCache(&customer)
+    saveToCache(ctx, &customer)
 
-    c.JSON(http.StatusCreated, gin.H{"message": "Customer created successfully"})
+    respond.JSON(c, http.StatusCreated, gin.H{"message": "Customer created successfully"})
 }
 
-func getFromCache(customerID string) (*Customer, error) {
-    val, err := redisClient.Get(ctx, customerID).Result()
-    if err == redis.Nil {
-        log.Printf("No cache found for customerID: %s", customerID)
-        return nil, nil
-    } else if err != nil {
-        log.Printf("Error fetching from Redis for customerID %s: %v", customerID, err)
-        return nil, err
+func updateCustomer(c *gin.Context) {
+    ctx := c.Request.Context()
+    var customer Customer
+    if err := c.ShouldBindJSON(&customer); err != nil {
+        if bodylimit.TooLarge(c, err) {
+            return
+        }
+        metrics.RecordValidationFailure(c, err)
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, err.Error())
+        return
+    }
+    if customer.ID == "" {
+        metrics.RecordValidationField(c, "id")
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "id is required")
+        return
+    }
+    if !validateCustomer(c, &customer) {
+        return
     }
 
-    var customer Customer
-    err = json.Unmarshal([]byte(val), &customer)
+    err := updateInDB(ctx, &customer)
+    if errors.Is(err, sql.ErrNoRows) {
+        respond.Error(c, http.StatusNotFound, respond.CodeNotFound, "customer not found")
+        return
+    }
     if err != nil {
-        log.Printf("Error unmarshalling data for customerID %s: %v", customerID, err)
-        return nil, err
+        logger.ErrorContext(ctx, "Failed to update DB", "customer_id", customer.ID, "error", err)
+        respond.BackendError(c, err, "failed to update DB")
+        return
     }
 
-    return &customer, nil
+    // 갱신 대신 삭제하여 다음 getCustomer가 DB에서 다시 읽어 캐시를 채우게 함
+    deleteFromCache(ctx, customer.ID)
+
+    respond.JSON(c, http.StatusOK, customer)
 }
 
-func saveToCache(customer *Customer) {
-    data, err := json.Marshal(customer)
-    if err != nil {
-        log.Printf("Failed to marshal customer: %v", err)
+func deleteCustomer(c *gin.Context) {
+    ctx := c.Request.Context()
+    customerID := c.Query("id")
+    if customerID == "" {
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "id is required")
         return
     }
 
-    err = redisClient.Set(ctx, customer.ID, data, 0).Err()
+    if orderServiceURL != "" {
+        hasOrders, err := customerHasOrders(ctx, customerID)
+        if err != nil {
+            logger.ErrorContext(ctx, "Failed to check orders", "customer_id", customerID, "error", err)
+            respond.Error(c, http.StatusServiceUnavailable, respond.CodeUnavailable, "failed to check orders")
+            return
+        }
+        if hasOrders {
+            respond.Error(c, http.StatusConflict, respond.CodeConflict, "customer has orders; delete them first")
+            return
+        }
+    }
+
+    err := deleteFromDB(ctx, customerID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respond.Error(c, http.StatusNotFound, respond.CodeNotFound, "customer not found")
+        return
+    }
     if err != nil {
-        log.Printf("Failed to save to cache for customerID %s: %v", customer.ID, err)
-    } else {
-        log.Printf("Successfully saved to cache for customerID %s", customer.ID)
+        logger.ErrorContext(ctx, "Failed to delete from DB", "customer_id", customerID, "error", err)
+        respond.BackendError(c, err, "failed to delete from DB")
+        return
     }
+
+    deleteFromCache(ctx, customerID)
+
+    c.Status(http.StatusNoContent)
+}
+
+// 호출할 때마다 만들어 재연결로 바뀐 Redis 클라이언트와 CACHE_TTL_SECONDS 값을 그대로 씀
+func customerCache() *cacheaside.Cache {
+    return &cacheaside.Cache{
+        Store:  cacheaside.RedisStore(redisConn.Client),
+        TTL:    cacheTTL,
+        IDKey:  "customer_id",
+        Logger: logger,
+        Begin: func(ctx context.Context, op, key string) (context.Context, func()) {
+            ctx, span := tracing.Start(ctx, op, "customer_id", key)
+            ctx, cancel := backend.WithTimeout(ctx)
+            return ctx, func() {
+                cancel()
+                span.End()
+            }
+        },
+        Fail:   func() error { return chaos.Inject(chaosCacheFailRate) },
+        Record: cachestats.Record,
+    }
+}
+
+func getFromCache(ctx context.Context, customerID string) (*Customer, error) {
+    return cacheaside.Get[Customer](ctx, customerCache(), customerID)
 }
 
-func getFromDB(customerID string) (*Customer, error) {
-    sqlQuery := "SELECT id, name, gender FROM customers WHERE id = ?"
+func saveToCache(ctx context.Context, customer *Customer) {
+    customerCache().Set(ctx, customer.ID, customer)
+}
+
+func deleteFromCache(ctx context.Context, customerID string) {
+    customerCache().Delete(ctx, customerID)
+}
+
+// 같은 id에 대한 동시 조회는 쿼리 한 번의 결과를 나눠 씀. 먼저 온 요청이 취소돼도
+// 나머지가 실패하지 않도록 공유 쿼리는 취소를 상속하지 않고 백엔드 타임아웃만 적용받음
+func getFromDB(ctx context.Context, customerID string) (*Customer, error) {
+    ctx, span := tracing.Start(ctx, "getFromDB", "customer_id", customerID)
+    defer span.End()
+    ch := dbReads.DoChan(customerID, func() (interface{}, error) {
+        return queryCustomer(context.WithoutCancel(ctx), customerID)
+    })
+
+    select {
+    case <-ctx.Done():
+        return nil, ctx.Err()
+    case res := <-ch:
+        if res.Err != nil {
+            return nil, res.Err
+        }
+        // 호출자끼리 같은 값을 공유하지 않도록 복사해서 돌려줌
+        customer := *res.Val.(*Customer)
+        return &customer, nil
+    }
+}
+
+func queryCustomer(ctx context.Context, customerID string) (*Customer, error) {
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosDBFailRate); err != nil {
+        return nil, err
+    }
+
     var customer Customer
-    err := db.Get(&customer, sqlQuery, customerID)
+    err := dbreconnect.Do(ctx, db, func() error {
+        return selectCustomerStmt.GetContext(ctx, &customer, customerID)
+    })
     if err != nil {
-        log.Printf("Error fetching from DB for customerID %s: %v", customerID, err)
+        logger.ErrorContext(ctx, "Error fetching from DB", "customer_id", customerID, "error", err)
         return nil, err
     }
     return &customer, nil
 }
 
-func saveToDB(customer *Customer) error {
-    sqlQuery := `INSERT INTO customers (id, name, gender) VALUES (?, ?, ?)`
-    _, err := db.Exec(sqlQuery, customer.ID, customer.Name, customer.Gender)
+func saveToDB(ctx context.Context, customer *Customer) error {
+    ctx, span := tracing.Start(ctx, "saveToDB", "customer_id", customer.ID)
+    defer span.End()
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosDBFailRate); err != nil {
+        return err
+    }
+
+    customer.CreatedAt = recordTimestamp()
+    customer.UpdatedAt = customer.CreatedAt
+    err := dbreconnect.DoInsert(ctx, db, func() error {
+        return withTx(ctx, func(tx *sqlx.Tx) error {
+            _, err := tx.StmtxContext(ctx, insertCustomerStmt).ExecContext(ctx, customer.ID, customer.Name, customer.Gender, customer.CreatedAt, customer.UpdatedAt)
+            return err
+        })
+    }, func() (bool, error) {
+        return customerStored(ctx, customer)
+    })
     if err != nil {
-        log.Printf("Error saving to DB for customerID %s: %v", customer.ID, err)
+        logger.ErrorContext(ctx, "Error saving to DB", "customer_id", customer.ID, "error", err)
         return err
     }
-    log.Printf("Successfully saved to DB for customerID %s", customer.ID)
+    logger.InfoContext(ctx, "Successfully saved to DB", "customer_id", customer.ID)
     return nil
 }
 
+// 저장된 행이 이 고객과 같은지 확인함. created_at은 요청마다 달라 다른 요청이 넣은 행과 구분됨
+func customerStored(ctx context.Context, customer *Customer) (bool, error) {
+    var stored Customer
+    if err := selectCustomerStmt.GetContext(ctx, &stored, customer.ID); err != nil {
+        return false, err
+    }
+    return stored.Name == customer.Name && stored.Gender == customer.Gender && stored.CreatedAt.Equal(customer.CreatedAt), nil
+}
+
+// 대상 행이 없으면 sql.ErrNoRows를 반환함. 같은 값으로 다시 실행해도 결과가 같으므로 연결이 끊기면
+// 재시도하며, 첫 시도가 이미 적용돼 재시도가 바꾼 행이 없으면 아래에서 다시 읽어 확인함
+func updateInDB(ctx context.Context, customer *Customer) error {
+    ctx, span := tracing.Start(ctx, "updateInDB", "customer_id", customer.ID)
+    defer span.End()
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosDBFailRate); err != nil {
+        return err
+    }
+
+    customer.UpdatedAt = recordTimestamp()
+    var rows int64
+    attempts := 0
+    err := dbreconnect.Do(ctx, db, func() error {
+        attempts++
+        result, err := updateCustomerStmt.ExecContext(ctx, customer.Name, customer.Gender, customer.UpdatedAt, customer.ID)
+        if err != nil {
+            return err
+        }
+        rows, err = result.RowsAffected()
+        return err
+    })
+    if err != nil {
+        logger.ErrorContext(ctx, "Error updating DB", "customer_id", customer.ID, "error", err)
+        return err
+    }
+    if rows == 0 && attempts == 1 {
+        return sql.ErrNoRows
+    }
+    logger.InfoContext(ctx, "Successfully updated DB", "customer_id", customer.ID)
+
+    // 응답에 created_at을 채우기 위해 갱신된 행을 다시 읽음
+    return selectCustomerStmt.GetContext(ctx, customer, customer.ID)
+}
+
+// 대상 행이 없으면 sql.ErrNoRows를 반환함. 연결이 끊기면 updateInDB처럼 재시도함
+func deleteFromDB(ctx context.Context, customerID string) error {
+    ctx, span := tracing.Start(ctx, "deleteFromDB", "customer_id", customerID)
+    defer span.End()
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosDBFailRate); err != nil {
+        return err
+    }
+
+    var rows int64
+    attempts := 0
+    err := dbreconnect.Do(ctx, db, func() error {
+        attempts++
+        result, err := deleteCustomerStmt.ExecContext(ctx, customerID)
+        if err != nil {
+            return err
+        }
+        rows, err = result.RowsAffected()
+        return err
+    })
+    if err != nil {
+        logger.ErrorContext(ctx, "Error deleting from DB", "customer_id", customerID, "error", err)
+        return err
+    }
+    // 재시도에서 지운 행이 없으면 끊기기 전의 첫 시도가 지운 것으로 봄
+    if rows == 0 && attempts == 1 {
+        return sql.ErrNoRows
+    }
+    logger.InfoContext(ctx, "Successfully deleted from DB", "customer_id", customerID)
+    return nil
+}
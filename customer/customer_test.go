@@ -0,0 +1,81 @@
+package main
+
+import (
+    "context"
+    "net/http"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+)
+
+// 없는 customer는 404, DB에 닿지 못한 경우는 500으로 구분함
+func TestGetCustomerNotFoundVsDBError(t *testing.T) {
+    useMiniredis(t)
+    conn := useTestDB(t)
+    router := newRouter()
+
+    w := doRequest(router, http.MethodGet, "/v1/customer?id=missing", nil, nil)
+    if w.Code != http.StatusNotFound {
+        t.Errorf("no rows: status %d, want 404 (%s)", w.Code, w.Body)
+    }
+
+    conn.Close()
+    w = doRequest(router, http.MethodGet, "/v1/customer?id=other", nil, nil)
+    if w.Code != http.StatusInternalServerError {
+        t.Errorf("connection error: status %d, want 500 (%s)", w.Code, w.Body)
+    }
+}
+
+// 이미 있는 id로 만들면 500이 아니라 409이고 기존 행은 그대로임
+func TestCreateCustomerDuplicateID(t *testing.T) {
+    useMiniredis(t)
+    useTestDB(t)
+    router := newRouter()
+    body := map[string]interface{}{"id": "c1", "name": "alice", "gender": "female"}
+
+    if w := doRequest(router, http.MethodPost, "/v1/customer", body, nil); w.Code != http.StatusCreated {
+        t.Fatalf("first create: status %d, want 201 (%s)", w.Code, w.Body)
+    }
+    body["name"] = "bob"
+    w := doRequest(router, http.MethodPost, "/v1/customer", body, nil)
+    if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), respond.CodeConflict) {
+        t.Fatalf("duplicate create: status %d, want 409 %s (%s)", w.Code, respond.CodeConflict, w.Body)
+    }
+
+    stored, err := queryCustomer(context.Background(), "c1")
+    if err != nil || stored.Name != "alice" {
+        t.Errorf("stored = %+v, %v, want alice", stored, err)
+    }
+}
+
+// 생성 시 두 시각을 같게 채우고, 수정은 updatedat만 바꿈. 요청 본문의 시각은 무시함
+func TestCustomerTimestamps(t *testing.T) {
+    useMiniredis(t)
+    useTestDB(t)
+    created := time.Date(2024, 3, 5, 14, 7, 9, 123456789, time.UTC)
+    fake := clock.NewFake(created)
+    t.Cleanup(clock.Set(fake))
+    router := newRouter()
+
+    body := map[string]interface{}{"id": "c1", "name": "alice", "gender": "female", "createdat": "2000-01-01T00:00:00Z"}
+    if w := doRequest(router, http.MethodPost, "/v1/customer", body, nil); w.Code != http.StatusCreated {
+        t.Fatalf("create: status %d, want 201 (%s)", w.Code, w.Body)
+    }
+    fake.Advance(time.Hour)
+    body["name"] = "alicia"
+    if w := doRequest(router, http.MethodPut, "/v1/customer", body, nil); w.Code != http.StatusOK {
+        t.Fatalf("update: status %d, want 200 (%s)", w.Code, w.Body)
+    }
+
+    stored, err := queryCustomer(context.Background(), "c1")
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := created.Truncate(time.Microsecond)
+    if !stored.CreatedAt.Equal(want) || !stored.UpdatedAt.Equal(want.Add(time.Hour)) {
+        t.Errorf("stored createdat %v updatedat %v, want %v and %v", stored.CreatedAt, stored.UpdatedAt, want, want.Add(time.Hour))
+    }
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+    "database/sql"
+    "encoding/json"
+    "errors"
+    "mime"
+    "net/http"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+)
+
+// 정보 주체 열람 요청에 내려주는 고객 데이터 전체. 주문은 주문 서비스의 응답을 그대로 담음.
+// 주문을 가져오지 못해도 고객 정보는 내려주고, 빠진 부분은 warnings에 적음. 이때 orders는 null
+type customerExport struct {
+    Customer   *Customer         `json:"customer"`
+    Orders     []json.RawMessage `json:"orders"`
+    ExportedAt time.Time         `json:"exported_at"`
+    Warnings   []string          `json:"warnings,omitempty"`
+}
+
+func exportCustomer(c *gin.Context) {
+    ctx := c.Request.Context()
+    customerID := c.Query("id")
+    if customerID == "" {
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "id is required")
+        return
+    }
+
+    // 내보내기는 캐시가 아니라 DB의 현재 값을 씀
+    customerData, err := getFromDB(ctx, customerID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respond.Error(c, http.StatusNotFound, respond.CodeNotFound, "customer not found")
+        return
+    }
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to fetch from DB", "customer_id", customerID, "error", err)
+        respond.BackendError(c, err, "failed to fetch from DB")
+        return
+    }
+
+    export := customerExport{Customer: customerData, ExportedAt: clock.Now().UTC()}
+    if orderServiceURL == "" {
+        export.Warnings = append(export.Warnings, "orders not included: order service is not configured")
+    } else if orders, err := fetchCustomerOrders(ctx, customerID, c.GetHeader("Authorization")); err != nil {
+        logger.ErrorContext(ctx, "Failed to fetch orders for export", "customer_id", customerID, "error", err)
+        export.Warnings = append(export.Warnings, "orders not included: order service is unavailable")
+    } else {
+        export.Orders = orders
+    }
+
+    c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+        "filename": "customer-" + customerID + ".json",
+    }))
+    respond.JSON(c, http.StatusOK, export)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+func useOrderService(t *testing.T, url string) {
+    t.Helper()
+    prev := orderServiceURL
+    orderServiceURL = url
+    t.Cleanup(func() { orderServiceURL = prev })
+}
+
+func createTestCustomer(t *testing.T, router http.Handler, id string) {
+    t.Helper()
+    body := map[string]interface{}{"id": id, "name": "alice", "gender": "female"}
+    if w := doRequest(router, http.MethodPost, "/v1/customer", body, nil); w.Code != http.StatusCreated {
+        t.Fatalf("create: status %d, want 201 (%s)", w.Code, w.Body)
+    }
+}
+
+func decodeExport(t *testing.T, w *httptest.ResponseRecorder) customerExport {
+    t.Helper()
+    if w.Code != http.StatusOK {
+        t.Fatalf("status %d, want 200 (%s)", w.Code, w.Body)
+    }
+    if cd := w.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "attachment;") {
+        t.Errorf("Content-Disposition %q, want attachment", cd)
+    }
+    var export customerExport
+    if err := json.Unmarshal(w.Body.Bytes(), &export); err != nil {
+        t.Fatalf("body %s: %v", w.Body, err)
+    }
+    return export
+}
+
+// 고객 정보와 주문 서비스의 주문을 함께 내려주고, 호출한 사용자의 토큰을 주문 서비스에 전달함
+func TestExportCustomer(t *testing.T) {
+    useMiniredis(t)
+    useTestDB(t)
+    var gotQuery, gotAuth string
+    orders := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        gotQuery, gotAuth = r.URL.RawQuery, r.Header.Get("Authorization")
+        w.Write([]byte(`[{"id":"o1","customerid":"c1"},{"id":"o2","customerid":"c1"}]`))
+    }))
+    t.Cleanup(orders.Close)
+    useOrderService(t, orders.URL)
+    router := newRouter()
+    createTestCustomer(t, router, "c1")
+
+    w := doRequest(router, http.MethodGet, "/v1/customer/export?id=c1", nil, map[string]string{"Authorization": "Bearer t1"})
+    export := decodeExport(t, w)
+    if export.Customer == nil || export.Customer.ID != "c1" || export.Customer.Name != "alice" {
+        t.Errorf("customer %+v, want c1", export.Customer)
+    }
+    if len(export.Orders) != 2 || len(export.Warnings) != 0 {
+        t.Errorf("orders %s, warnings %v, want 2 orders and no warnings", export.Orders, export.Warnings)
+    }
+    if gotQuery != "customerid=c1" || gotAuth != "Bearer t1" {
+        t.Errorf("order service got query %q, Authorization %q", gotQuery, gotAuth)
+    }
+    if cd := w.Header().Get("Content-Disposition"); !strings.Contains(cd, `filename=customer-c1.json`) {
+        t.Errorf("Content-Disposition %q, want customer-c1.json", cd)
+    }
+}
+
+// 주문 서비스가 실패하거나 설정되지 않아도 고객 정보는 내려주고 warnings에 적음
+func TestExportCustomerWithoutOrders(t *testing.T) {
+    useMiniredis(t)
+    useTestDB(t)
+    failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusServiceUnavailable)
+    }))
+    t.Cleanup(failing.Close)
+    router := newRouter()
+    createTestCustomer(t, router, "c1")
+
+    for name, url := range map[string]string{"unavailable": failing.URL, "not configured": ""} {
+        useOrderService(t, url)
+        export := decodeExport(t, doRequest(router, http.MethodGet, "/v1/customer/export?id=c1", nil, nil))
+        if export.Customer == nil || export.Customer.ID != "c1" {
+            t.Errorf("%s: customer %+v, want c1", name, export.Customer)
+        }
+        if export.Orders != nil || len(export.Warnings) != 1 {
+            t.Errorf("%s: orders %s, warnings %v, want no orders and one warning", name, export.Orders, export.Warnings)
+        }
+    }
+}
+
+func TestExportCustomerNotFound(t *testing.T) {
+    useMiniredis(t)
+    useTestDB(t)
+    router := newRouter()
+
+    if w := doRequest(router, http.MethodGet, "/v1/customer/export?id=missing", nil, nil); w.Code != http.StatusNotFound {
+        t.Errorf("missing: status %d, want 404 (%s)", w.Code, w.Body)
+    }
+    if w := doRequest(router, http.MethodGet, "/v1/customer/export", nil, nil); w.Code != http.StatusBadRequest {
+        t.Errorf("no id: status %d, want 400 (%s)", w.Code, w.Body)
+    }
+}
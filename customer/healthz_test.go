@@ -0,0 +1,47 @@
+package main
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+    "testing"
+)
+
+func TestHealthzRedisDownIsDegraded(t *testing.T) {
+    useTestDB(t)
+    mr := useMiniredis(t)
+    redisAddr := mr.Addr()
+    mr.Close()
+
+    w := doRequest(newRouter(), http.MethodGet, "/healthz", nil, nil)
+    if w.Code != http.StatusOK {
+        t.Fatalf("status %d, want 200 (%s)", w.Code, w.Body)
+    }
+    var resp struct {
+        Status       string            `json:"status"`
+        Dependencies map[string]string `json:"dependencies"`
+    }
+    if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+        t.Fatal(err)
+    }
+    if resp.Status != "degraded" || resp.Dependencies["redis"] != "error" || resp.Dependencies["mysql"] != "ok" {
+        t.Errorf("got %+v, want degraded with redis error and mysql ok", resp)
+    }
+    if strings.Contains(w.Body.String(), redisAddr) {
+        t.Errorf("response exposes the Redis address: %s", w.Body)
+    }
+}
+
+func TestHealthzMySQLDownIsUnavailable(t *testing.T) {
+    conn := useTestDB(t)
+    useMiniredis(t)
+    conn.Close()
+
+    w := doRequest(newRouter(), http.MethodGet, "/healthz", nil, nil)
+    if w.Code != http.StatusServiceUnavailable {
+        t.Fatalf("status %d, want 503 (%s)", w.Code, w.Body)
+    }
+    if !strings.Contains(w.Body.String(), `"status":"unavailable"`) {
+        t.Errorf("body %s, want status unavailable", w.Body)
+    }
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+    "context"
+    "encoding/csv"
+    "errors"
+    "fmt"
+    "io"
+    "net/http"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/bodylimit"
+    "github.com/gmstcl/eCommerce-System/internal/chaos"
+    "github.com/gmstcl/eCommerce-System/internal/ids"
+    "github.com/gmstcl/eCommerce-System/internal/metrics"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+    "github.com/gmstcl/eCommerce-System/internal/sqlq"
+    "github.com/jmoiron/sqlx"
+)
+
+const maxCustomerImport = 10000
+
+type importRowError struct {
+    Line  int    `json:"line"`
+    Error string `json:"error"`
+}
+
+// multipart의 file 필드로 id,name,gender CSV를 받음. 형식 오류가 하나라도 있으면
+// 아무것도 넣지 않고 줄 번호와 함께 돌려줌. 이미 있는 id는 건너뜀
+func importCustomers(c *gin.Context) {
+    ctx := c.Request.Context()
+    header, err := c.FormFile("file")
+    if err != nil {
+        if bodylimit.TooLarge(c, err) {
+            return
+        }
+        metrics.RecordValidationField(c, "file")
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "file is required")
+        return
+    }
+    file, err := header.Open()
+    if err != nil {
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "failed to read file")
+        return
+    }
+    defer file.Close()
+
+    customers, rowErrors, err := parseCustomerCSV(file)
+    if err != nil {
+        metrics.RecordValidationField(c, "file")
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, err.Error())
+        return
+    }
+    if len(rowErrors) > 0 {
+        metrics.RecordValidationField(c, "file")
+        respond.ErrorDetails(c, http.StatusBadRequest, respond.CodeInvalidRequest, "malformed rows", gin.H{"rows": rowErrors})
+        return
+    }
+
+    skipped, err := importCustomersToDB(ctx, customers)
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to import customers", "count", len(customers), "error", err)
+        respond.BackendError(c, err, "failed to import customers")
+        return
+    }
+
+    inserted := make([]Customer, 0, len(customers)-len(skipped))
+    for _, customer := range customers {
+        if !skipped[customer.ID] {
+            inserted = append(inserted, customer)
+        }
+    }
+    if len(inserted) > 0 {
+        saveBatchToCache(ctx, inserted)
+    }
+
+    skippedIDs := make([]string, 0, len(skipped))
+    for _, customer := range customers {
+        if skipped[customer.ID] {
+            skippedIDs = append(skippedIDs, customer.ID)
+        }
+    }
+    respond.JSON(c, http.StatusOK, gin.H{
+        "inserted":    len(inserted),
+        "skipped":     len(skippedIDs),
+        "skipped_ids": skippedIDs,
+    })
+}
+
+// 헤더나 CSV 자체가 잘못되면 error, 행 단위 문제는 importRowError로 모아서 반환함
+func parseCustomerCSV(r io.Reader) ([]Customer, []importRowError, error) {
+    reader := csv.NewReader(r)
+    reader.FieldsPerRecord = -1
+    reader.TrimLeadingSpace = true
+
+    head, err := reader.Read()
+    if err == io.EOF {
+        return nil, nil, errors.New("file is empty")
+    }
+    if err != nil {
+        return nil, nil, fmt.Errorf("invalid CSV: %w", err)
+    }
+    if len(head) != 3 || !strings.EqualFold(strings.TrimSpace(head[0]), "id") ||
+        !strings.EqualFold(strings.TrimSpace(head[1]), "name") ||
+        !strings.EqualFold(strings.TrimSpace(head[2]), "gender") {
+        return nil, nil, errors.New("header must be id,name,gender")
+    }
+
+    var customers []Customer
+    var rowErrors []importRowError
+    seen := make(map[string]int)
+    for {
+        record, err := reader.Read()
+        if err == io.EOF {
+            break
+        }
+        var parseErr *csv.ParseError
+        if errors.As(err, &parseErr) {
+            rowErrors = append(rowErrors, importRowError{parseErr.Line, parseErr.Err.Error()})
+            continue
+        }
+        if err != nil {
+            return nil, nil, fmt.Errorf("invalid CSV: %w", err)
+        }
+
+        line, _ := reader.FieldPos(0)
+        if len(record) != 3 {
+            rowErrors = append(rowErrors, importRowError{line, fmt.Sprintf("expected 3 columns, got %d", len(record))})
+            continue
+        }
+        customer := Customer{
+            ID:     strings.TrimSpace(record[0]),
+            Name:   strings.TrimSpace(record[1]),
+            Gender: strings.TrimSpace(record[2]),
+        }
+        if err := ids.Check("id", customer.ID); err != nil {
+            rowErrors = append(rowErrors, importRowError{line, err.Error()})
+            continue
+        }
+        if !normalizeGender(&customer) {
+            rowErrors = append(rowErrors, importRowError{line, genderError()})
+            continue
+        }
+        if first, ok := seen[customer.ID]; ok {
+            rowErrors = append(rowErrors, importRowError{line, fmt.Sprintf("duplicate id, first seen on line %d", first)})
+            continue
+        }
+        seen[customer.ID] = line
+        customers = append(customers, customer)
+    }
+
+    if len(customers) == 0 && len(rowErrors) == 0 {
+        return nil, nil, errors.New("file has no rows")
+    }
+    if len(customers) > maxCustomerImport {
+        return nil, nil, fmt.Errorf("file must contain at most %d rows", maxCustomerImport)
+    }
+    return customers, rowErrors, nil
+}
+
+// 한 트랜잭션에서 기존 id를 확인한 뒤 나머지를 넣음. 오류가 나면 전체를 되돌림
+func importCustomersToDB(ctx context.Context, customers []Customer) (map[string]bool, error) {
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosDBFailRate); err != nil {
+        return nil, err
+    }
+
+    stampNewCustomers(customers)
+    skipped := make(map[string]bool)
+    err := withTx(ctx, func(tx *sqlx.Tx) error {
+        return importCustomersTx(ctx, tx, customers, skipped)
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    logger.InfoContext(ctx, "Successfully imported customers", "inserted", len(customers)-len(skipped), "skipped", len(skipped))
+    return skipped, nil
+}
+
+func importCustomersTx(ctx context.Context, tx *sqlx.Tx, customers []Customer, skipped map[string]bool) error {
+    var pending []Customer
+    for start := 0; start < len(customers); start += maxCustomerBatch {
+        end := start + maxCustomerBatch
+        if end > len(customers) {
+            end = len(customers)
+        }
+        chunk := customers[start:end]
+
+        ids := make([]string, 0, len(chunk))
+        for _, customer := range chunk {
+            ids = append(ids, customer.ID)
+        }
+        var existing []string
+        if err := sqlq.New("SELECT id FROM customers WHERE ").AddIn("id", ids).SelectContext(ctx, tx, &existing); err != nil {
+            return err
+        }
+        for _, id := range existing {
+            skipped[id] = true
+        }
+
+        pending = pending[:0]
+        for _, customer := range chunk {
+            if !skipped[customer.ID] {
+                pending = append(pending, customer)
+            }
+        }
+        if len(pending) > 0 {
+            if err := insertCustomersTx(ctx, tx, pending); err != nil {
+                return err
+            }
+        }
+    }
+    return nil
+}
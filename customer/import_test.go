@@ -0,0 +1,91 @@
+package main
+
+import (
+    "bytes"
+    "encoding/json"
+    "mime/multipart"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+func uploadCSV(t *testing.T, csv string) *httptest.ResponseRecorder {
+    t.Helper()
+    var body bytes.Buffer
+    form := multipart.NewWriter(&body)
+    part, err := form.CreateFormFile("file", "customers.csv")
+    if err != nil {
+        t.Fatal(err)
+    }
+    part.Write([]byte(csv))
+    form.Close()
+
+    req := httptest.NewRequest(http.MethodPost, "/v1/customers/import", &body)
+    req.Header.Set("Content-Type", form.FormDataContentType())
+    w := httptest.NewRecorder()
+    newRouter().ServeHTTP(w, req)
+    return w
+}
+
+func countCustomers(t *testing.T) int {
+    t.Helper()
+    var n int
+    if err := db.Get(&n, "SELECT COUNT(*) FROM customers"); err != nil {
+        t.Fatal(err)
+    }
+    return n
+}
+
+// 새 id는 넣고 이미 있는 id는 건너뛰며 요약을 돌려줌
+func TestImportValidFile(t *testing.T) {
+    conn := useTestDB(t)
+    useMiniredis(t)
+    if _, err := conn.Exec("INSERT INTO customers (id, name, gender, created_at, updated_at) VALUES ('c2', 'bob', 'male', NOW(), NOW())"); err != nil {
+        t.Fatal(err)
+    }
+
+    w := uploadCSV(t, "id,name,gender\nc1,alice,female\nc2,bobby,male\nc3,carol,Female\n")
+    if w.Code != http.StatusOK {
+        t.Fatalf("status %d, want 200 (%s)", w.Code, w.Body)
+    }
+    var resp struct {
+        Inserted   int      `json:"inserted"`
+        Skipped    int      `json:"skipped"`
+        SkippedIDs []string `json:"skipped_ids"`
+    }
+    if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+        t.Fatal(err)
+    }
+    if resp.Inserted != 2 || resp.Skipped != 1 || len(resp.SkippedIDs) != 1 || resp.SkippedIDs[0] != "c2" {
+        t.Errorf("summary %+v, want 2 inserted and c2 skipped", resp)
+    }
+    if n := countCustomers(t); n != 3 {
+        t.Errorf("%d customers stored, want 3", n)
+    }
+}
+
+// 잘못된 행이 있으면 아무것도 넣지 않고 줄 번호와 함께 400
+func TestImportReportsBadRowsWithLineNumbers(t *testing.T) {
+    useTestDB(t)
+    useMiniredis(t)
+
+    w := uploadCSV(t, "id,name,gender\nc1,alice,female\nc2,bob,robot\nc3,carol\n")
+    if w.Code != http.StatusBadRequest {
+        t.Fatalf("status %d, want 400 (%s)", w.Code, w.Body)
+    }
+    var resp struct {
+        Details struct {
+            Rows []importRowError `json:"rows"`
+        } `json:"details"`
+    }
+    if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+        t.Fatal(err)
+    }
+    rows := resp.Details.Rows
+    if len(rows) != 2 || rows[0].Line != 3 || rows[1].Line != 4 {
+        t.Errorf("row errors %+v, want lines 3 and 4", rows)
+    }
+    if n := countCustomers(t); n != 0 {
+        t.Errorf("%d customers stored, want 0", n)
+    }
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+    "net/http"
+    "strings"
+    "testing"
+
+    "github.com/gmstcl/eCommerce-System/internal/keystyle"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+)
+
+// customer 서비스도 order와 같은 JSON_KEY_STYLE 변환을 거쳐 응답함
+func TestCustomerResponseFollowsKeyStyle(t *testing.T) {
+    conn := useTestDB(t)
+    useMiniredis(t)
+    prev := respond.KeyStyle
+    respond.KeyStyle = keystyle.Snake
+    t.Cleanup(func() { respond.KeyStyle = prev })
+    if _, err := conn.Exec("INSERT INTO customers (id, name, gender, created_at, updated_at) VALUES ('c1', 'alice', 'female', NOW(), NOW())"); err != nil {
+        t.Fatal(err)
+    }
+
+    w := doRequest(newRouter(), http.MethodGet, "/v1/customer?id=c1", nil, nil)
+    body := w.Body.String()
+    if w.Code != http.StatusOK || !strings.Contains(body, `"created_at"`) || strings.Contains(body, `"createdat"`) {
+        t.Errorf("status %d %s, want snake_case keys", w.Code, body)
+    }
+}
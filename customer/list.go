@@ -0,0 +1,54 @@
+package main
+
+import (
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+    "github.com/gmstcl/eCommerce-System/internal/sqlq"
+)
+
+const (
+    listDefaultLimit = 100
+    listMaxLimit     = 1000
+)
+
+// id 순서의 keyset 페이지네이션. 다음 페이지는 응답의 next_cursor를 cursor로 넘겨 요청함.
+// 마지막 페이지이면 next_cursor가 빈 문자열임
+func listCustomers(c *gin.Context) {
+    limit := listDefaultLimit
+    if v := c.Query("limit"); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil || n <= 0 || n > listMaxLimit {
+            respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "limit must be between 1 and 1000")
+            return
+        }
+        limit = n
+    }
+
+    ctx, cancel := backend.WithTimeout(c.Request.Context())
+    defer cancel()
+
+    // 한 건 더 읽어 다음 페이지가 있는지 판단함
+    customers := []Customer{}
+    err := sqlq.New("SELECT id, name, gender, created_at, updated_at FROM customers WHERE id > ? ORDER BY id LIMIT ?", c.Query("cursor"), limit+1).
+        SelectContext(ctx, db, &customers)
+    if err != nil {
+        logger.ErrorContext(ctx, "Error listing customers", "error", err)
+        respond.BackendError(c, err, "failed to list customers")
+        return
+    }
+
+    nextCursor := ""
+    if len(customers) > limit {
+        customers = customers[:limit]
+        nextCursor = customers[limit-1].ID
+    }
+
+    respond.JSON(c, http.StatusOK, gin.H{
+        "customers":   customers,
+        "next_cursor": nextCursor,
+    })
+}
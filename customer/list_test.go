@@ -0,0 +1,84 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "testing"
+)
+
+type customerPage struct {
+    Customers  []Customer `json:"customers"`
+    NextCursor string     `json:"next_cursor"`
+}
+
+func listPage(t *testing.T, router http.Handler, query string) customerPage {
+    t.Helper()
+    w := doRequest(router, http.MethodGet, "/v1/customers?"+query, nil, nil)
+    if w.Code != http.StatusOK {
+        t.Fatalf("GET /v1/customers?%s: status %d, want 200 (%s)", query, w.Code, w.Body)
+    }
+    var page customerPage
+    if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+        t.Fatal(err)
+    }
+    return page
+}
+
+// next_cursor를 따라가면 모든 고객을 id 순으로 한 번씩 읽고, 마지막 페이지의 next_cursor는 빈 문자열임
+func TestListCustomersPagination(t *testing.T) {
+    conn := useTestDB(t)
+    for _, id := range []string{"c3", "c1", "c5", "c2", "c4"} {
+        if _, err := conn.Exec("INSERT INTO customers (id, name, gender, created_at, updated_at) VALUES (?, 'x', 'other', NOW(), NOW())", id); err != nil {
+            t.Fatal(err)
+        }
+    }
+    router := newRouter()
+
+    var ids, cursors []string
+    cursor := ""
+    for i := 0; i < 5; i++ {
+        page := listPage(t, router, "limit=2&cursor="+cursor)
+        for _, customer := range page.Customers {
+            ids = append(ids, customer.ID)
+        }
+        cursors = append(cursors, page.NextCursor)
+        if cursor = page.NextCursor; cursor == "" {
+            break
+        }
+    }
+    if got := fmt.Sprint(ids); got != "[c1 c2 c3 c4 c5]" {
+        t.Errorf("ids %s, want [c1 c2 c3 c4 c5]", got)
+    }
+    if got := fmt.Sprint(cursors); got != "[c2 c4 ]" {
+        t.Errorf("cursors %q, want c2, c4, then empty", cursors)
+    }
+}
+
+// 빈 테이블은 빈 배열이고, 정확히 limit개만 남은 페이지는 다음 커서가 없음
+func TestListCustomersLastPage(t *testing.T) {
+    conn := useTestDB(t)
+    router := newRouter()
+
+    if page := listPage(t, router, ""); page.Customers == nil || len(page.Customers) != 0 || page.NextCursor != "" {
+        t.Errorf("empty table = %+v, want [] and no cursor", page)
+    }
+
+    for _, id := range []string{"c1", "c2"} {
+        if _, err := conn.Exec("INSERT INTO customers (id, name, gender, created_at, updated_at) VALUES (?, 'x', 'other', NOW(), NOW())", id); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if page := listPage(t, router, "limit=2"); len(page.Customers) != 2 || page.NextCursor != "" {
+        t.Errorf("exact page = %+v, want 2 customers and no cursor", page)
+    }
+}
+
+func TestListCustomersBadLimit(t *testing.T) {
+    router := newRouter()
+    for _, limit := range []string{"0", "-1", "1001", "abc"} {
+        if w := doRequest(router, http.MethodGet, "/v1/customers?limit="+limit, nil, nil); w.Code != http.StatusBadRequest {
+            t.Errorf("limit=%s: status %d, want 400 (%s)", limit, w.Code, w.Body)
+        }
+    }
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "io"
+    "log/slog"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "strings"
+    "testing"
+
+    "github.com/alicebob/miniredis/v2"
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/migrate"
+    "github.com/gmstcl/eCommerce-System/internal/mysqltest"
+    "github.com/go-redis/redis/v8"
+    "github.com/jmoiron/sqlx"
+)
+
+func TestMain(m *testing.M) {
+    gin.SetMode(gin.TestMode)
+    gin.DefaultWriter = io.Discard
+    logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+    slog.SetDefault(logger)
+    os.Exit(m.Run())
+}
+
+// 빈 인메모리 MySQL에 연결하고 db를 바꿔 끼움
+func connectTestDB(t testing.TB) *sqlx.DB {
+    t.Helper()
+    conn, err := sqlx.Connect("mysql", mysqltest.Start(t))
+    if err != nil {
+        t.Fatal(err)
+    }
+    prev := db
+    db = conn
+    t.Cleanup(func() {
+        conn.Close()
+        db = prev
+    })
+    return conn
+}
+
+// 마이그레이션을 적용하고 준비된 문장까지 만든 DB
+func useTestDB(t testing.TB) *sqlx.DB {
+    t.Helper()
+    conn := connectTestDB(t)
+    if err := migrate.Run(context.Background(), db, migrationFiles); err != nil {
+        t.Fatal(err)
+    }
+    prepareStatements()
+    return conn
+}
+
+func useMiniredis(t *testing.T) *miniredis.Miniredis {
+    t.Helper()
+    mr := miniredis.RunT(t)
+    client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+    prev := redisConn.Replace(client)
+    t.Cleanup(func() {
+        client.Close()
+        redisConn.Replace(prev)
+    })
+    return mr
+}
+
+func doRequest(router http.Handler, method, path string, body interface{}, headers map[string]string) *httptest.ResponseRecorder {
+    var reader io.Reader
+    switch b := body.(type) {
+    case nil:
+    case string:
+        reader = strings.NewReader(b)
+    default:
+        data, _ := json.Marshal(b)
+        reader = bytes.NewReader(data)
+    }
+    req := httptest.NewRequest(method, path, reader)
+    if body != nil {
+        req.Header.Set("Content-Type", "application/json")
+    }
+    for k, v := range headers {
+        req.Header.Set(k, v)
+    }
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+    return w
+}
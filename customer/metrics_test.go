@@ -0,0 +1,56 @@
+package main
+
+import (
+    "bufio"
+    "net/http"
+    "strconv"
+    "strings"
+    "testing"
+
+    "github.com/gmstcl/eCommerce-System/internal/apikey"
+)
+
+// /metrics를 읽어 name{labels} 줄의 값을 반환함. 아직 없는 시계열은 0
+func scrapeMetric(t *testing.T, router http.Handler, series string) float64 {
+    t.Helper()
+    w := doRequest(router, http.MethodGet, "/metrics", nil, nil)
+    if w.Code != http.StatusOK {
+        t.Fatalf("scrape: status %d, want 200 (%s)", w.Code, w.Body)
+    }
+    scanner := bufio.NewScanner(w.Body)
+    for scanner.Scan() {
+        if value, ok := strings.CutPrefix(scanner.Text(), series+" "); ok {
+            v, err := strconv.ParseFloat(value, 64)
+            if err != nil {
+                t.Fatalf("parse %q: %v", scanner.Text(), err)
+            }
+            return v
+        }
+    }
+    return 0
+}
+
+// API 키가 켜져 있어도 /metrics는 키 없이 읽히고, 요청 수와 캐시 조회 수가 늘어남
+func TestMetricsScrape(t *testing.T) {
+    useMiniredis(t)
+    useTestDB(t)
+    prevKeys := apiKeys
+    apiKeys = apikey.Parse("k1")
+    t.Cleanup(func() { apiKeys = prevKeys })
+    router := newRouter()
+    key := map[string]string{apikey.Header: "k1"}
+
+    requests := `http_requests_total{method="GET",route="/v1/customer",status="404"}`
+    before := map[string]float64{
+        requests:             scrapeMetric(t, router, requests),
+        "cache_misses_total": scrapeMetric(t, router, "cache_misses_total"),
+    }
+    if w := doRequest(router, http.MethodGet, "/v1/customer?id=missing", nil, key); w.Code != http.StatusNotFound {
+        t.Fatalf("request: status %d, want 404 (%s)", w.Code, w.Body)
+    }
+    for series, value := range before {
+        if after := scrapeMetric(t, router, series); after != value+1 {
+            t.Errorf("%s = %v, want %v", series, after, value+1)
+        }
+    }
+}
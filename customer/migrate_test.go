@@ -0,0 +1,77 @@
+package main
+
+import (
+    "context"
+    "reflect"
+    "testing"
+    "time"
+
+    "github.com/gmstcl/eCommerce-System/internal/migrate"
+)
+
+var migratedCustomerColumns = []string{"id", "name", "gender", "created_at", "updated_at"}
+
+func tableColumns(t *testing.T, table string) []string {
+    t.Helper()
+    var columns []string
+    err := db.Select(&columns, "SELECT column_name FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position", table)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return columns
+}
+
+func TestMigrationsOnEmptyDB(t *testing.T) {
+    connectTestDB(t)
+    ctx := context.Background()
+
+    if err := migrate.Run(ctx, db, migrationFiles); err != nil {
+        t.Fatal(err)
+    }
+    if got := tableColumns(t, "customers"); !reflect.DeepEqual(got, migratedCustomerColumns) {
+        t.Errorf("columns %v, want %v", got, migratedCustomerColumns)
+    }
+
+    // 두 번째 실행은 적용된 파일을 건너뜀
+    if err := migrate.Run(ctx, db, migrationFiles); err != nil {
+        t.Fatalf("second run: %v", err)
+    }
+}
+
+// 러너가 생기기 전의 배포 환경. 처음 스키마의 테이블에 데이터가 있고 schema_migrations는 없음
+func TestMigrationsOnBaselineDB(t *testing.T) {
+    connectTestDB(t)
+
+    db.MustExec("CREATE TABLE customers (id VARCHAR(64) NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, gender VARCHAR(32) NOT NULL)")
+    db.MustExec("INSERT INTO customers (id, name, gender) VALUES ('c1', 'kim', 'F')")
+
+    if err := migrate.Run(context.Background(), db, migrationFiles); err != nil {
+        t.Fatal(err)
+    }
+    if got := tableColumns(t, "customers"); !reflect.DeepEqual(got, migratedCustomerColumns) {
+        t.Errorf("columns %v, want %v", got, migratedCustomerColumns)
+    }
+
+    var row struct {
+        Name      string
+        CreatedAt time.Time `db:"created_at"`
+    }
+    if err := db.Get(&row, "SELECT name, created_at FROM customers WHERE id = 'c1'"); err != nil {
+        t.Fatal(err)
+    }
+    if row.Name != "kim" || row.CreatedAt.IsZero() {
+        t.Errorf("existing row after migration %+v, want name kim and created_at set", row)
+    }
+}
+
+func TestBaselineMigrationMatchesFirstSchema(t *testing.T) {
+    connectTestDB(t)
+    migrations, err := migrate.Load(migrationFiles)
+    if err != nil {
+        t.Fatal(err)
+    }
+    db.MustExec(migrations[0].SQL)
+    if got, want := tableColumns(t, "customers"), []string{"id", "name", "gender"}; !reflect.DeepEqual(got, want) {
+        t.Errorf("0001 creates %v, want the first schema %v", got, want)
+    }
+}
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS customers (
+    id     VARCHAR(64)  NOT NULL PRIMARY KEY,
+    name   VARCHAR(255) NOT NULL,
+    gender VARCHAR(32)  NOT NULL
+);
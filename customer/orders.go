@@ -0,0 +1,65 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "net/url"
+    "os"
+    "time"
+
+    "github.com/gmstcl/eCommerce-System/internal/apikey"
+    "github.com/gmstcl/eCommerce-System/internal/requestid"
+    "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// ORDER_SERVICE_URL이 있으면 주문이 남아 있는 고객의 삭제를 막음. 없으면 확인하지 않음
+var (
+    orderServiceURL = os.Getenv("ORDER_SERVICE_URL")
+    // otelhttp가 traceparent 헤더를 넣어 주문 서비스의 스팬이 같은 트레이스에 붙음
+    orderHTTPClient = &http.Client{Timeout: 2 * time.Second, Transport: otelhttp.NewTransport(http.DefaultTransport)}
+)
+
+func customerHasOrders(ctx context.Context, customerID string) (bool, error) {
+    orders, err := fetchCustomerOrders(ctx, customerID, "")
+    if err != nil {
+        return false, err
+    }
+    return len(orders) > 0, nil
+}
+
+// 주문 서비스의 GET /v1/orders 결과를 그대로 돌려줌. authorization이 있으면 함께 보내
+// 주문 서비스가 호출한 사용자 기준으로 소유자를 확인하게 함
+func fetchCustomerOrders(ctx context.Context, customerID, authorization string) ([]json.RawMessage, error) {
+    endpoint := orderServiceURL + "/v1/orders?" + url.Values{"customerid": {customerID}}.Encode()
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+    if err != nil {
+        return nil, err
+    }
+    if id := requestid.From(ctx); id != "" {
+        req.Header.Set(requestid.Header, id)
+    }
+    if key := os.Getenv("SERVICE_API_KEY"); key != "" {
+        req.Header.Set(apikey.Header, key)
+    }
+    if authorization != "" {
+        req.Header.Set("Authorization", authorization)
+    }
+
+    resp, err := orderHTTPClient.Do(req)
+    if err != nil {
+        return nil, err
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode != http.StatusOK {
+        return nil, fmt.Errorf("GET /v1/orders: unexpected status %d", resp.StatusCode)
+    }
+
+    var orders []json.RawMessage
+    if err := json.NewDecoder(resp.Body).Decode(&orders); err != nil {
+        return nil, err
+    }
+    return orders, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+    "context"
+    "testing"
+)
+
+// 준비된 문장이 인자를 순서대로 바인딩하는지 확인함. 따옴표가 든 값도 그대로 저장되고 조회됨
+func TestPreparedStatementsBindParameters(t *testing.T) {
+    useTestDB(t)
+    ctx := context.Background()
+
+    customers := []Customer{
+        {ID: "c1", Name: "alice", Gender: "female"},
+        {ID: "c2", Name: "o'brien \"bob\"", Gender: "male"},
+    }
+    for i := range customers {
+        if err := saveToDB(ctx, &customers[i]); err != nil {
+            t.Fatalf("save %s: %v", customers[i].ID, err)
+        }
+    }
+
+    for _, want := range customers {
+        got, err := queryCustomer(ctx, want.ID)
+        if err != nil {
+            t.Fatalf("query %s: %v", want.ID, err)
+        }
+        if got.ID != want.ID || got.Name != want.Name || got.Gender != want.Gender || !got.CreatedAt.Equal(want.CreatedAt) {
+            t.Errorf("query %s = %+v, want %+v", want.ID, got, want)
+        }
+    }
+}
+
+// 동시 요청에서의 id 조회. 인메모리 MySQL(TCP)에 대해 준비된 문장은 실행만 보내고, 준비하지 않은 쿼리는
+// 드라이버가 요청마다 준비, 실행, 닫기를 보냄. go test -run XXX -bench SelectCustomer -benchtime 5000x ./customer
+// 결과(1코어, 세 번 실행):
+//
+//    prepared      73-76µs/op
+//    unprepared    175-183µs/op
+func BenchmarkSelectCustomer(b *testing.B) {
+    conn := useTestDB(b)
+    ctx := context.Background()
+    if err := saveToDB(ctx, &Customer{ID: "c1", Name: "alice", Gender: "female"}); err != nil {
+        b.Fatal(err)
+    }
+
+    b.Run("prepared", func(b *testing.B) {
+        b.RunParallel(func(pb *testing.PB) {
+            for pb.Next() {
+                var customer Customer
+                if err := selectCustomerStmt.GetContext(ctx, &customer, "c1"); err != nil {
+                    b.Error(err)
+                    return
+                }
+            }
+        })
+    })
+    b.Run("unprepared", func(b *testing.B) {
+        b.RunParallel(func(pb *testing.PB) {
+            for pb.Next() {
+                var customer Customer
+                if err := conn.GetContext(ctx, &customer, "SELECT id, name, gender, created_at, updated_at FROM customers WHERE id = ?", "c1"); err != nil {
+                    b.Error(err)
+                    return
+                }
+            }
+        })
+    })
+}
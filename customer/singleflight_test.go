@@ -0,0 +1,129 @@
+package main
+
+import (
+    "context"
+    "database/sql"
+    "database/sql/driver"
+    "errors"
+    "io"
+    "net/http"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/jmoiron/sqlx"
+)
+
+// 준비된 SELECT마다 delay만큼 걸려 고객 한 행을 돌려주고 실행 횟수를 세는 드라이버
+type countingConnector struct {
+    queries atomic.Int32
+    delay   time.Duration
+}
+
+func (c *countingConnector) Connect(context.Context) (driver.Conn, error) {
+    return &countingConn{c}, nil
+}
+
+func (c *countingConnector) Driver() driver.Driver {
+    return connectorDriver{}
+}
+
+type countingConn struct {
+    c *countingConnector
+}
+
+func (c *countingConn) Prepare(string) (driver.Stmt, error) {
+    return &countingStmt{c.c}, nil
+}
+
+func (c *countingConn) Close() error {
+    return nil
+}
+
+func (c *countingConn) Begin() (driver.Tx, error) {
+    return nil, errors.New("transactions not supported")
+}
+
+type countingStmt struct {
+    c *countingConnector
+}
+
+func (s *countingStmt) Close() error {
+    return nil
+}
+
+func (s *countingStmt) NumInput() int {
+    return -1
+}
+
+func (s *countingStmt) Exec([]driver.Value) (driver.Result, error) {
+    return nil, errors.New("exec not supported")
+}
+
+func (s *countingStmt) Query(args []driver.Value) (driver.Rows, error) {
+    s.c.queries.Add(1)
+    time.Sleep(s.c.delay)
+    return &customerRows{id: args[0].(string)}, nil
+}
+
+type customerRows struct {
+    id   string
+    done bool
+}
+
+func (r *customerRows) Columns() []string {
+    return []string{"id", "name", "gender", "created_at", "updated_at"}
+}
+
+func (r *customerRows) Close() error {
+    return nil
+}
+
+func (r *customerRows) Next(dest []driver.Value) error {
+    if r.done {
+        return io.EOF
+    }
+    r.done = true
+    now := time.Now().UTC()
+    dest[0], dest[1], dest[2], dest[3], dest[4] = r.id, "alice", "female", now, now
+    return nil
+}
+
+// 동시에 들어온 같은 고객 조회는 쿼리를 한 번만 실행함
+func TestConcurrentGetCustomerSharesOneQuery(t *testing.T) {
+    useMiniredis(t)
+    counter := &countingConnector{delay: 50 * time.Millisecond}
+    conn := sqlx.NewDb(sql.OpenDB(counter), "mysql")
+    stmt, err := conn.Preparex("SELECT id, name, gender, created_at, updated_at FROM customers WHERE id = ?")
+    if err != nil {
+        t.Fatal(err)
+    }
+    prevDB, prevStmt := db, selectCustomerStmt
+    db, selectCustomerStmt = conn, stmt
+    t.Cleanup(func() {
+        db, selectCustomerStmt = prevDB, prevStmt
+        conn.Close()
+    })
+    router := newRouter()
+
+    var wg sync.WaitGroup
+    codes := make([]int, 20)
+    for i := range codes {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            codes[i] = doRequest(router, http.MethodGet, "/v1/customer?id=c1", nil, nil).Code
+        }(i)
+    }
+    wg.Wait()
+
+    for i, code := range codes {
+        if code != http.StatusOK {
+            t.Fatalf("request %d: status %d, want 200", i, code)
+        }
+    }
+    if n := counter.queries.Load(); n != 1 {
+        t.Errorf("query ran %d times, want 1", n)
+    }
+}
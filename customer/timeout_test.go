@@ -0,0 +1,23 @@
+package main
+
+import (
+    "net/http"
+    "testing"
+    "time"
+
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+)
+
+// MySQL이 BACKEND_TIMEOUT_MS 안에 응답하지 않으면 504
+func TestSlowDBReturnsGatewayTimeout(t *testing.T) {
+    useMiniredis(t)
+    useBlockingDB(t)
+    prev := backend.Timeout
+    backend.Timeout = 50 * time.Millisecond
+    t.Cleanup(func() { backend.Timeout = prev })
+
+    w := doRequest(newRouter(), http.MethodGet, "/v1/customer?id=c1", nil, nil)
+    if w.Code != http.StatusGatewayTimeout {
+        t.Errorf("status %d, want 504 (%s)", w.Code, w.Body)
+    }
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+    "net/http"
+    "slices"
+    "testing"
+
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/propagation"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// 전역 프로바이더를 메모리 익스포터로 바꿈. 스팬은 끝나는 즉시 익스포터에 기록됨
+func useSpanExporter(t *testing.T) *tracetest.InMemoryExporter {
+    t.Helper()
+    exporter := tracetest.NewInMemoryExporter()
+    provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+    prev := otel.GetTracerProvider()
+    otel.SetTracerProvider(provider)
+    t.Cleanup(func() {
+        otel.SetTracerProvider(prev)
+        provider.Shutdown(t.Context())
+    })
+    return exporter
+}
+
+// 백엔드 헬퍼의 스팬은 요청 스팬의 자식이고 customer_id 속성을 가지며, 들어온 traceparent의 트레이스를 이어감
+func TestRequestSpans(t *testing.T) {
+    useMiniredis(t)
+    useTestDB(t)
+    exporter := useSpanExporter(t)
+    prevPropagator := otel.GetTextMapPropagator()
+    otel.SetTextMapPropagator(propagation.TraceContext{})
+    t.Cleanup(func() { otel.SetTextMapPropagator(prevPropagator) })
+    router := newRouter()
+
+    const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+    headers := map[string]string{"traceparent": "00-" + traceID + "-00f067aa0ba902b7-01"}
+    if w := doRequest(router, http.MethodGet, "/v1/customer?id=c1", nil, headers); w.Code != http.StatusNotFound {
+        t.Fatalf("status %d, want 404 (%s)", w.Code, w.Body)
+    }
+
+    spans := map[string]tracetest.SpanStub{}
+    for _, span := range exporter.GetSpans() {
+        spans[span.Name] = span
+    }
+    server, ok := spans["GET /v1/customer"]
+    if !ok {
+        t.Fatalf("no server span in %v", spans)
+    }
+    if got := server.SpanContext.TraceID().String(); got != traceID {
+        t.Errorf("trace id %s, want %s from traceparent", got, traceID)
+    }
+    for _, name := range []string{"getFromCache", "getFromDB"} {
+        span, ok := spans[name]
+        if !ok {
+            t.Errorf("no %s span", name)
+            continue
+        }
+        if span.Parent.SpanID() != server.SpanContext.SpanID() {
+            t.Errorf("%s parent %s, want server span %s", name, span.Parent.SpanID(), server.SpanContext.SpanID())
+        }
+        if !slices.Contains(span.Attributes, attribute.String("customer_id", "c1")) {
+            t.Errorf("%s attributes %v, want customer_id=c1", name, span.Attributes)
+        }
+    }
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+    "context"
+
+    "github.com/jmoiron/sqlx"
+)
+
+// fn이 nil을 반환하면 커밋하고, 오류를 반환하거나 panic이 나면 롤백함.
+// 여러 테이블에 함께 써야 하는 쓰기(예: 고객 + 감사 기록)는 이 안에서 처리함
+func withTx(ctx context.Context, fn func(tx *sqlx.Tx) error) (err error) {
+    tx, err := db.BeginTxx(ctx, nil)
+    if err != nil {
+        return err
+    }
+
+    defer func() {
+        if p := recover(); p != nil {
+            tx.Rollback()
+            panic(p)
+        }
+        if err != nil {
+            if rbErr := tx.Rollback(); rbErr != nil {
+                logger.ErrorContext(ctx, "Failed to roll back transaction", "error", rbErr)
+            }
+        }
+    }()
+
+    if err = fn(tx); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "testing"
+
+    "github.com/jmoiron/sqlx"
+)
+
+func insertInTx(ctx context.Context, tx *sqlx.Tx, id string) error {
+    _, err := tx.ExecContext(ctx, "INSERT INTO customers (id, name, gender, created_at, updated_at) VALUES (?, 'alice', 'female', NOW(), NOW())", id)
+    return err
+}
+
+func customerExists(t *testing.T, id string) bool {
+    t.Helper()
+    var n int
+    if err := db.Get(&n, "SELECT COUNT(*) FROM customers WHERE id = ?", id); err != nil {
+        t.Fatal(err)
+    }
+    return n > 0
+}
+
+// 첫 INSERT 뒤에 오류가 나면 그 INSERT까지 되돌림
+func TestWithTxRollsBackOnError(t *testing.T) {
+    useTestDB(t)
+    ctx := context.Background()
+    errMidway := errors.New("audit write failed")
+
+    err := withTx(ctx, func(tx *sqlx.Tx) error {
+        if err := insertInTx(ctx, tx, "c1"); err != nil {
+            return err
+        }
+        return errMidway
+    })
+    if !errors.Is(err, errMidway) {
+        t.Fatalf("withTx returned %v, want the error from fn", err)
+    }
+    if customerExists(t, "c1") {
+        t.Error("c1 was committed despite the error")
+    }
+}
+
+// panic도 롤백한 뒤 다시 panic함
+func TestWithTxRollsBackOnPanic(t *testing.T) {
+    useTestDB(t)
+    ctx := context.Background()
+
+    func() {
+        defer func() {
+            if recover() == nil {
+                t.Error("withTx swallowed the panic")
+            }
+        }()
+        withTx(ctx, func(tx *sqlx.Tx) error {
+            if err := insertInTx(ctx, tx, "c1"); err != nil {
+                return err
+            }
+            panic("boom")
+        })
+    }()
+    if customerExists(t, "c1") {
+        t.Error("c1 was committed despite the panic")
+    }
+}
+
+func TestWithTxCommitsOnSuccess(t *testing.T) {
+    useTestDB(t)
+    ctx := context.Background()
+
+    err := withTx(ctx, func(tx *sqlx.Tx) error {
+        if err := insertInTx(ctx, tx, "c1"); err != nil {
+            return err
+        }
+        return insertInTx(ctx, tx, "c2")
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !customerExists(t, "c1") || !customerExists(t, "c2") {
+        t.Error("rows were not committed")
+    }
+}
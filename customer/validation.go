@@ -0,0 +1,54 @@
+package main
+
+import (
+    "net/http"
+    "os"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/metrics"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+)
+
+// 표기가 제각각(M, male, Male)이 되지 않도록 소문자로 맞춘 뒤 허용 목록과 비교함.
+// CUSTOMER_GENDERS로 목록을 바꿀 수 있음
+var allowedGenders = parseGenders(os.Getenv("CUSTOMER_GENDERS"))
+
+func parseGenders(v string) []string {
+    if v == "" {
+        return []string{"male", "female", "other"}
+    }
+    var genders []string
+    for _, gender := range strings.Split(v, ",") {
+        if gender = strings.ToLower(strings.TrimSpace(gender)); gender != "" {
+            genders = append(genders, gender)
+        }
+    }
+    return genders
+}
+
+func genderError() string {
+    return "gender must be one of " + strings.Join(allowedGenders, ", ")
+}
+
+// 허용된 값이면 정규화한 값을 customer에 다시 씀
+func normalizeGender(customer *Customer) bool {
+    gender := strings.ToLower(strings.TrimSpace(customer.Gender))
+    for _, allowed := range allowedGenders {
+        if gender == allowed {
+            customer.Gender = gender
+            return true
+        }
+    }
+    return false
+}
+
+// id 형식은 생성할 때만 검사함. 규칙이 생기기 전에 만든 id도 수정할 수 있어야 함
+func validateCustomer(c *gin.Context, customer *Customer) bool {
+    if !normalizeGender(customer) {
+        metrics.RecordValidationField(c, "gender")
+        respond.ErrorDetails(c, http.StatusBadRequest, respond.CodeInvalidRequest, genderError(), gin.H{"allowed": allowedGenders})
+        return false
+    }
+    return true
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+    "context"
+    "net/http"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+// id 규칙이 생기기 전에 만든 고객은 id 형식과 관계없이 수정할 수 있고, 같은 id로 새로 만들 수는 없음
+func TestIDRuleOnlyAppliesToCreate(t *testing.T) {
+    conn := useTestDB(t)
+    useMiniredis(t)
+    if _, err := conn.Exec("INSERT INTO customers (id, name, gender, created_at, updated_at) VALUES ('legacy_id', 'alice', 'female', NOW(), NOW())"); err != nil {
+        t.Fatal(err)
+    }
+    router := newRouter()
+
+    w := doRequest(router, http.MethodPut, "/v1/customer", map[string]string{"id": "legacy_id", "name": "alicia", "gender": "female"}, nil)
+    if w.Code != http.StatusOK {
+        t.Errorf("update: status %d, want 200 (%s)", w.Code, w.Body)
+    }
+
+    w = doRequest(router, http.MethodPost, "/v1/customer", map[string]string{"id": "new_id", "name": "bob", "gender": "male"}, nil)
+    if w.Code != http.StatusBadRequest {
+        t.Errorf("create: status %d, want 400 (%s)", w.Code, w.Body)
+    }
+}
+
+func TestNormalizeGender(t *testing.T) {
+    tests := []struct {
+        in, want string
+        ok       bool
+    }{
+        {"male", "male", true},
+        {"Female", "female", true},
+        {" OTHER ", "other", true},
+        {"M", "M", false},
+        {"", "", false},
+        {"unknown", "unknown", false},
+    }
+    for _, tt := range tests {
+        customer := Customer{Gender: tt.in}
+        if ok := normalizeGender(&customer); ok != tt.ok || customer.Gender != tt.want {
+            t.Errorf("normalizeGender(%q) = %q, %v, want %q, %v", tt.in, customer.Gender, ok, tt.want, tt.ok)
+        }
+    }
+}
+
+func TestParseGenders(t *testing.T) {
+    if got := parseGenders(""); !reflect.DeepEqual(got, []string{"male", "female", "other"}) {
+        t.Errorf("default = %v", got)
+    }
+    if got := parseGenders(" M, f ,,X "); !reflect.DeepEqual(got, []string{"m", "f", "x"}) {
+        t.Errorf("custom = %v, want [m f x]", got)
+    }
+}
+
+// 허용하지 않는 값은 400과 허용 목록을 주고, 허용된 값은 소문자로 저장함
+func TestCreateCustomerGender(t *testing.T) {
+    useMiniredis(t)
+    useTestDB(t)
+    router := newRouter()
+
+    w := doRequest(router, http.MethodPost, "/v1/customer", map[string]string{"id": "c1", "name": "alice", "gender": "robot"}, nil)
+    if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"allowed":["male","female","other"]`) {
+        t.Errorf("invalid gender: status %d body %s, want 400 with allowed list", w.Code, w.Body)
+    }
+
+    w = doRequest(router, http.MethodPost, "/v1/customer", map[string]string{"id": "c1", "name": "alice", "gender": "FEMALE"}, nil)
+    if w.Code != http.StatusCreated {
+        t.Fatalf("valid gender: status %d, want 201 (%s)", w.Code, w.Body)
+    }
+    if stored, err := queryCustomer(context.Background(), "c1"); err != nil || stored.Gender != "female" {
+        t.Errorf("stored = %+v, %v, want gender female", stored, err)
+    }
+}
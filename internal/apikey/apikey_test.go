@@ -0,0 +1,83 @@
+package apikey
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/gin-gonic/gin"
+)
+
+func newTestRouter(keys Keys, authenticated bool, gotID *string) *gin.Engine {
+    gin.SetMode(gin.TestMode)
+    router := gin.New()
+    router.Use(Middleware(keys, func(*gin.Context) bool { return authenticated }))
+    handler := func(c *gin.Context) {
+        *gotID = ID(c)
+        c.Status(http.StatusOK)
+    }
+    for _, path := range []string{"/v1/item", "/healthz", "/metrics"} {
+        router.GET(path, handler)
+    }
+    return router
+}
+
+func serve(router http.Handler, path, key string) int {
+    req := httptest.NewRequest(http.MethodGet, path, nil)
+    if key != "" {
+        req.Header.Set(Header, key)
+    }
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+    return w.Code
+}
+
+func TestMiddleware(t *testing.T) {
+    keys := Parse(" k1, ,k2 ")
+    if len(keys) != 2 {
+        t.Fatalf("Parse kept %d keys, want 2", len(keys))
+    }
+    var gotID string
+    router := newTestRouter(keys, false, &gotID)
+
+    tests := []struct {
+        name, path, key string
+        status          int
+    }{
+        {"valid", "/v1/item", "k2", http.StatusOK},
+        {"invalid", "/v1/item", "nope", http.StatusUnauthorized},
+        {"missing", "/v1/item", "", http.StatusUnauthorized},
+        {"healthz", "/healthz", "", http.StatusOK},
+        {"metrics", "/metrics", "", http.StatusOK},
+    }
+    for _, tt := range tests {
+        if got := serve(router, tt.path, tt.key); got != tt.status {
+            t.Errorf("%s: status %d, want %d", tt.name, got, tt.status)
+        }
+    }
+}
+
+// 키 원문 대신 해시 앞부분을 식별자로 남기고, 키 없이 통과한 요청은 빈 문자열
+func TestID(t *testing.T) {
+    var gotID string
+    router := newTestRouter(Parse("k1"), false, &gotID)
+
+    serve(router, "/v1/item", "k1")
+    if len(gotID) != 16 || gotID == "k1" {
+        t.Errorf("ID = %q, want 16 hex characters", gotID)
+    }
+    serve(router, "/healthz", "")
+    if gotID != "" {
+        t.Errorf("ID without key = %q, want empty", gotID)
+    }
+}
+
+func TestMiddlewareSkipped(t *testing.T) {
+    var gotID string
+    if got := serve(newTestRouter(nil, false, &gotID), "/v1/item", ""); got != http.StatusOK {
+        t.Errorf("no keys configured: status %d, want 200", got)
+    }
+    if got := serve(newTestRouter(Parse("k1"), true, &gotID), "/v1/item", ""); got != http.StatusOK {
+        t.Errorf("already authenticated: status %d, want 200", got)
+    }
+}
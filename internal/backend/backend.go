@@ -0,0 +1,55 @@
+// Package backend는 MySQL, Redis, DynamoDB 같은 백엔드 호출의 제한 시간과 오류 분류임.
+// 느린 백엔드 호출 하나가 핸들러를 붙잡지 않도록 호출마다 제한 시간을 둠
+package backend
+
+import (
+    "context"
+    "errors"
+    "log"
+    "net"
+    "net/http"
+    "os"
+    "strconv"
+    "time"
+)
+
+// 서킷 브레이커가 열려 호출하지 않은 경우처럼 백엔드를 잠시 쓸 수 없을 때 감싸는 오류. 503으로 응답함
+var ErrUnavailable = errors.New("backend unavailable")
+
+// 테스트에서 줄일 수 있도록 변수로 둠
+var Timeout = timeoutFromEnv()
+
+func timeoutFromEnv() time.Duration {
+    v := os.Getenv("BACKEND_TIMEOUT_MS")
+    if v == "" {
+        return 5 * time.Second
+    }
+    ms, err := strconv.Atoi(v)
+    if err != nil || ms <= 0 {
+        log.Fatalf("invalid BACKEND_TIMEOUT_MS %q", v)
+    }
+    return time.Duration(ms) * time.Millisecond
+}
+
+func WithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+    return context.WithTimeout(ctx, Timeout)
+}
+
+// Redis는 컨텍스트 마감 시간을 소켓 deadline으로 쓰므로 net 타임아웃도 함께 확인함
+func IsTimeout(err error) bool {
+    if errors.Is(err, context.DeadlineExceeded) {
+        return true
+    }
+    var netErr net.Error
+    return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func Status(err error) int {
+    switch {
+    case IsTimeout(err):
+        return http.StatusGatewayTimeout
+    case errors.Is(err, ErrUnavailable):
+        return http.StatusServiceUnavailable
+    }
+    return http.StatusInternalServerError
+}
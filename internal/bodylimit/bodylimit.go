@@ -0,0 +1,67 @@
+// Package bodylimit는 요청 본문 크기를 제한함. 본문 전체를 메모리에 읽는 핸들러가 많으므로 모든 경로에 적용하고,
+// 배치와 CSV 가져오기 경로(/batch, /import로 끝나는 경로)는 MAX_BATCH_BODY_BYTES를 따로 적용함
+package bodylimit
+
+import (
+    "errors"
+    "fmt"
+    "log"
+    "net/http"
+    "os"
+    "strconv"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+)
+
+var (
+    Max      = fromEnv("MAX_BODY_BYTES", 1<<20)
+    MaxBatch = fromEnv("MAX_BATCH_BODY_BYTES", 10<<20)
+)
+
+func fromEnv(name string, def int64) int64 {
+    v := os.Getenv(name)
+    if v == "" {
+        return def
+    }
+    n, err := strconv.ParseInt(v, 10, 64)
+    if err != nil || n <= 0 {
+        log.Fatalf("invalid %s %q (want a positive number of bytes)", name, v)
+    }
+    return n
+}
+
+func limitFor(path string) int64 {
+    if strings.HasSuffix(path, "/batch") || strings.HasSuffix(path, "/import") {
+        return MaxBatch
+    }
+    return Max
+}
+
+// Content-Length로 알 수 있으면 바로 413을 돌려주고, chunked 본문은 읽는 도중에 끊음
+func Middleware() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        limit := limitFor(c.FullPath())
+        if c.Request.ContentLength > limit {
+            respondTooLarge(c, limit)
+            return
+        }
+        c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+        c.Next()
+    }
+}
+
+func respondTooLarge(c *gin.Context, limit int64) {
+    respond.ErrorDetails(c, http.StatusRequestEntityTooLarge, respond.CodePayloadTooLarge, fmt.Sprintf("request body must be at most %d bytes", limit), gin.H{"limit": limit})
+}
+
+// 본문을 읽다가 한도를 넘었으면 413을 쓰고 true를 반환함. 바인딩 오류를 400으로 돌려주기 전에 호출함
+func TooLarge(c *gin.Context, err error) bool {
+    var maxErr *http.MaxBytesError
+    if !errors.As(err, &maxErr) {
+        return false
+    }
+    respondTooLarge(c, maxErr.Limit)
+    return true
+}
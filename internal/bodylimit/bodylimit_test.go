@@ -0,0 +1,88 @@
+package bodylimit
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+)
+
+func newTestRouter(t *testing.T) *gin.Engine {
+    t.Helper()
+    prevMax, prevBatch := Max, MaxBatch
+    Max, MaxBatch = 32, 128
+    t.Cleanup(func() { Max, MaxBatch = prevMax, prevBatch })
+
+    gin.SetMode(gin.TestMode)
+    router := gin.New()
+    router.Use(Middleware())
+    handler := func(c *gin.Context) {
+        var body map[string]interface{}
+        if err := c.ShouldBindJSON(&body); err != nil {
+            if TooLarge(c, err) {
+                return
+            }
+            respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, err.Error())
+            return
+        }
+        c.Status(http.StatusOK)
+    }
+    router.POST("/v1/item", handler)
+    router.POST("/v1/items/batch", handler)
+    return router
+}
+
+// size 바이트짜리 JSON 객체. chunked이면 Content-Length 없이 보냄
+func post(router http.Handler, path string, size int, chunked bool) *httptest.ResponseRecorder {
+    body := `{"a":"` + strings.Repeat("x", size-8) + `"}`
+    req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+    if chunked {
+        req.ContentLength = -1
+    }
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+    return w
+}
+
+// Content-Length로 알 수 있든 읽는 도중에 넘든 413과 한도를 돌려주고, 배치 경로에는 더 큰 한도를 적용함
+func TestBodyLimit(t *testing.T) {
+    router := newTestRouter(t)
+    tests := []struct {
+        name    string
+        path    string
+        size    int
+        chunked bool
+        status  int
+        limit   int64
+    }{
+        {"at limit", "/v1/item", 32, false, http.StatusOK, 0},
+        {"over limit", "/v1/item", 33, false, http.StatusRequestEntityTooLarge, 32},
+        {"chunked over limit", "/v1/item", 33, true, http.StatusRequestEntityTooLarge, 32},
+        {"batch under batch limit", "/v1/items/batch", 100, false, http.StatusOK, 0},
+        {"batch over batch limit", "/v1/items/batch", 129, true, http.StatusRequestEntityTooLarge, 128},
+    }
+    for _, tt := range tests {
+        w := post(router, tt.path, tt.size, tt.chunked)
+        if w.Code != tt.status {
+            t.Errorf("%s: status %d, want %d (%s)", tt.name, w.Code, tt.status, w.Body)
+            continue
+        }
+        if tt.status != http.StatusRequestEntityTooLarge {
+            continue
+        }
+        var resp struct {
+            Code    string `json:"code"`
+            Details struct {
+                Limit int64 `json:"limit"`
+            } `json:"details"`
+        }
+        if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Code != respond.CodePayloadTooLarge || resp.Details.Limit != tt.limit {
+            t.Errorf("%s: body %s, want %s with limit %d", tt.name, w.Body, respond.CodePayloadTooLarge, tt.limit)
+        }
+    }
+}
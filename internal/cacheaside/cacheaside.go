@@ -0,0 +1,139 @@
+// Package cacheaside는 세 서비스가 함께 쓰는 캐시 조회/저장/삭제 흐름임.
+// 저장소는 Store 인터페이스로 받고 스팬, 타임아웃, 장애 주입, 히트율 기록은 서비스가 Cache의 훅으로 넘김.
+// 저장소 장애는 캐시 미스처럼 처리해 호출하는 쪽이 DB로 넘어가도록 함
+package cacheaside
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "log/slog"
+    "time"
+
+    "github.com/go-redis/redis/v8"
+)
+
+// 키가 없을 때 Store.Get이 반환하는 오류
+var ErrMiss = errors.New("cacheaside: miss")
+
+type Store interface {
+    Get(ctx context.Context, key string) ([]byte, error)
+    Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+    Delete(ctx context.Context, key string) error
+}
+
+// 재연결 중에 클라이언트가 교체될 수 있으므로 호출할 때마다 현재 클라이언트를 가져옴
+type RedisStore func() *redis.Client
+
+func (s RedisStore) Get(ctx context.Context, key string) ([]byte, error) {
+    val, err := s().Get(ctx, key).Bytes()
+    if err == redis.Nil {
+        return nil, ErrMiss
+    }
+    return val, err
+}
+
+func (s RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+    return s().Set(ctx, key, value, ttl).Err()
+}
+
+func (s RedisStore) Delete(ctx context.Context, key string) error {
+    return s().Del(ctx, key).Err()
+}
+
+// 로그에는 키를 IDKey 속성으로 남김. Begin, Fail, Record는 없으면 건너뜀
+type Cache struct {
+    Store  Store
+    TTL    time.Duration
+    IDKey  string
+    // 저장소 키 앞에 붙여 같은 Redis를 쓰는 다른 캐시와 키가 겹치지 않게 함. 로그와 훅에는 붙이지 않은 키를 넘김
+    Prefix string
+    Logger *slog.Logger
+    // 스팬과 백엔드 타임아웃을 건 ctx와 정리 함수를 반환함
+    Begin func(ctx context.Context, op, key string) (context.Context, func())
+    // 장애 주입. 오류를 반환하면 저장소가 죽은 것처럼 처리함
+    Fail   func() error
+    Record func(hit bool)
+}
+
+func (c *Cache) begin(ctx context.Context, op, key string) (context.Context, func()) {
+    if c.Begin == nil {
+        return ctx, func() {}
+    }
+    return c.Begin(ctx, op, key)
+}
+
+func (c *Cache) fail() error {
+    if c.Fail == nil {
+        return nil
+    }
+    return c.Fail()
+}
+
+func (c *Cache) record(hit bool) {
+    if c.Record != nil {
+        c.Record(hit)
+    }
+}
+
+// 캐시에 없거나 저장소에 닿지 못하면 nil, nil. 저장된 값을 디코딩하지 못한 경우만 오류를 반환함
+func Get[T any](ctx context.Context, c *Cache, key string) (*T, error) {
+    ctx, end := c.begin(ctx, "getFromCache", key)
+    defer end()
+
+    if err := c.fail(); err != nil {
+        c.Logger.WarnContext(ctx, "Redis unavailable, falling back to DB", c.IDKey, key, "error", err)
+        return nil, nil
+    }
+
+    data, err := c.Store.Get(ctx, c.Prefix+key)
+    if errors.Is(err, ErrMiss) {
+        c.Logger.DebugContext(ctx, "No cache found", c.IDKey, key)
+        c.record(false)
+        return nil, nil
+    } else if err != nil {
+        c.Logger.WarnContext(ctx, "Redis unavailable, falling back to DB", c.IDKey, key, "error", err)
+        return nil, nil
+    }
+
+    var value T
+    if err := json.Unmarshal(data, &value); err != nil {
+        c.Logger.ErrorContext(ctx, "Error unmarshalling data", c.IDKey, key, "error", err)
+        return nil, err
+    }
+
+    c.record(true)
+    return &value, nil
+}
+
+// 실패는 로그만 남김. 다음 조회가 DB에서 다시 채움
+func (c *Cache) Set(ctx context.Context, key string, value interface{}) {
+    ctx, end := c.begin(ctx, "saveToCache", key)
+    defer end()
+
+    if err := c.fail(); err != nil {
+        c.Logger.WarnContext(ctx, "Redis unavailable, skipping cache write", c.IDKey, key, "error", err)
+        return
+    }
+
+    data, err := json.Marshal(value)
+    if err != nil {
+        c.Logger.ErrorContext(ctx, "Failed to marshal cache value", c.IDKey, key, "error", err)
+        return
+    }
+
+    if err := c.Store.Set(ctx, c.Prefix+key, data, c.TTL); err != nil {
+        c.Logger.WarnContext(ctx, "Redis unavailable, skipping cache write", c.IDKey, key, "error", err)
+    } else {
+        c.Logger.InfoContext(ctx, "Successfully saved to cache", c.IDKey, key)
+    }
+}
+
+func (c *Cache) Delete(ctx context.Context, key string) {
+    ctx, end := c.begin(ctx, "deleteFromCache", key)
+    defer end()
+
+    if err := c.Store.Delete(ctx, c.Prefix+key); err != nil {
+        c.Logger.ErrorContext(ctx, "Failed to delete cache", c.IDKey, key, "error", err)
+    }
+}
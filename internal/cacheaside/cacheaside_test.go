@@ -0,0 +1,202 @@
+package cacheaside
+
+import (
+    "context"
+    "errors"
+    "io"
+    "log/slog"
+    "testing"
+    "time"
+)
+
+// 메모리 맵 저장소. err가 있으면 모든 호출이 그 오류로 실패함
+type stubStore struct {
+    data    map[string][]byte
+    ttls    map[string]time.Duration
+    err     error
+    deleted []string
+}
+
+func newStubStore() *stubStore {
+    return &stubStore{data: map[string][]byte{}, ttls: map[string]time.Duration{}}
+}
+
+func (s *stubStore) Get(ctx context.Context, key string) ([]byte, error) {
+    if s.err != nil {
+        return nil, s.err
+    }
+    val, ok := s.data[key]
+    if !ok {
+        return nil, ErrMiss
+    }
+    return val, nil
+}
+
+func (s *stubStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+    if s.err != nil {
+        return s.err
+    }
+    s.data[key] = value
+    s.ttls[key] = ttl
+    return nil
+}
+
+func (s *stubStore) Delete(ctx context.Context, key string) error {
+    if s.err != nil {
+        return s.err
+    }
+    s.deleted = append(s.deleted, key)
+    delete(s.data, key)
+    return nil
+}
+
+type item struct {
+    ID   string `json:"id"`
+    Name string `json:"name"`
+}
+
+func newCache(store Store, lookups *[]bool) *Cache {
+    return &Cache{
+        Store:  store,
+        TTL:    time.Minute,
+        IDKey:  "item_id",
+        Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+        Record: func(hit bool) { *lookups = append(*lookups, hit) },
+    }
+}
+
+func TestSetThenGet(t *testing.T) {
+    store := newStubStore()
+    var lookups []bool
+    cache := newCache(store, &lookups)
+    ctx := context.Background()
+
+    cache.Set(ctx, "a", item{ID: "a", Name: "apple"})
+    if store.ttls["a"] != time.Minute {
+        t.Errorf("ttl %v, want %v", store.ttls["a"], time.Minute)
+    }
+
+    got, err := Get[item](ctx, cache, "a")
+    if err != nil || got == nil || got.Name != "apple" {
+        t.Fatalf("Get = %+v, %v, want apple", got, err)
+    }
+    if len(lookups) != 1 || !lookups[0] {
+        t.Errorf("recorded %v, want one hit", lookups)
+    }
+}
+
+func TestGetMissIsRecorded(t *testing.T) {
+    var lookups []bool
+    got, err := Get[item](context.Background(), newCache(newStubStore(), &lookups), "missing")
+    if got != nil || err != nil {
+        t.Fatalf("Get = %+v, %v, want nil, nil", got, err)
+    }
+    if len(lookups) != 1 || lookups[0] {
+        t.Errorf("recorded %v, want one miss", lookups)
+    }
+}
+
+// 저장소 장애는 미스처럼 nil, nil이지만 히트율에는 넣지 않음
+func TestStoreErrorFallsBackToMiss(t *testing.T) {
+    store := newStubStore()
+    store.err = errors.New("connection refused")
+    var lookups []bool
+    cache := newCache(store, &lookups)
+
+    got, err := Get[item](context.Background(), cache, "a")
+    if got != nil || err != nil {
+        t.Fatalf("Get = %+v, %v, want nil, nil", got, err)
+    }
+    if len(lookups) != 0 {
+        t.Errorf("recorded %v, want nothing", lookups)
+    }
+    cache.Set(context.Background(), "a", item{ID: "a"})
+    cache.Delete(context.Background(), "a")
+}
+
+func TestCorruptValueReturnsError(t *testing.T) {
+    store := newStubStore()
+    store.data["a"] = []byte("{not json")
+    var lookups []bool
+
+    got, err := Get[item](context.Background(), newCache(store, &lookups), "a")
+    if got != nil || err == nil {
+        t.Fatalf("Get = %+v, %v, want an unmarshal error", got, err)
+    }
+}
+
+// 장애 주입이 켜지면 저장소를 건드리지 않음
+func TestFailSkipsStore(t *testing.T) {
+    store := newStubStore()
+    store.data["a"] = []byte(`{"id":"a"}`)
+    var lookups []bool
+    cache := newCache(store, &lookups)
+    cache.Fail = func() error { return errors.New("injected") }
+
+    if got, err := Get[item](context.Background(), cache, "a"); got != nil || err != nil {
+        t.Fatalf("Get = %+v, %v, want nil, nil", got, err)
+    }
+    cache.Set(context.Background(), "b", item{ID: "b"})
+    if _, ok := store.data["b"]; ok {
+        t.Error("Set wrote to the store while failure injection was on")
+    }
+}
+
+func TestDelete(t *testing.T) {
+    store := newStubStore()
+    store.data["a"] = []byte(`{"id":"a"}`)
+    var lookups []bool
+    cache := newCache(store, &lookups)
+
+    cache.Delete(context.Background(), "a")
+    if _, ok := store.data["a"]; ok || len(store.deleted) != 1 {
+        t.Errorf("deleted %v, data %v", store.deleted, store.data)
+    }
+}
+
+// Prefix는 저장소 키에만 붙고 호출하는 쪽은 붙이지 않은 키를 씀
+func TestPrefix(t *testing.T) {
+    store := newStubStore()
+    var lookups []bool
+    cache := newCache(store, &lookups)
+    cache.Prefix = "order:"
+    ctx := context.Background()
+
+    cache.Set(ctx, "a", item{ID: "a"})
+    if _, ok := store.data["order:a"]; !ok {
+        t.Fatalf("stored keys %v, want order:a", store.data)
+    }
+    if got, err := Get[item](ctx, cache, "a"); err != nil || got == nil || got.ID != "a" {
+        t.Errorf("Get = %+v, %v, want a", got, err)
+    }
+    cache.Delete(ctx, "a")
+    if len(store.deleted) != 1 || store.deleted[0] != "order:a" {
+        t.Errorf("deleted %v, want [order:a]", store.deleted)
+    }
+}
+
+func TestBeginWrapsEachOperation(t *testing.T) {
+    var ops []string
+    var lookups []bool
+    cache := newCache(newStubStore(), &lookups)
+    cache.Begin = func(ctx context.Context, op, key string) (context.Context, func()) {
+        ops = append(ops, op+":"+key)
+        return ctx, func() {}
+    }
+    ctx := context.Background()
+
+    cache.Set(ctx, "a", item{ID: "a"})
+    Get[item](ctx, cache, "a")
+    cache.Delete(ctx, "a")
+
+    want := []string{"saveToCache:a", "getFromCache:a", "deleteFromCache:a"}
+    if len(ops) != len(want) {
+        t.Fatalf("ops %v, want %v", ops, want)
+    }
+    for i := range want {
+        if ops[i] != want[i] {
+            t.Errorf("ops %v, want %v", ops, want)
+            break
+        }
+    }
+}
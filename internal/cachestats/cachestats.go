@@ -0,0 +1,123 @@
+// Package cachestats는 customer, product 서비스의 캐시 적중률 집계임.
+// /v1/cache/report용 윈도우 집계와 Prometheus 카운터를 함께 갱신함
+package cachestats
+
+import (
+    "net/http"
+    "sync"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+    "github.com/prometheus/client_golang/prometheus"
+)
+
+// 초 단위 카운터를 고정 크기 링 버퍼에 보관하므로 메모리 사용량이 일정함.
+// 조회 가능한 최대 윈도우는 windowSeconds 초.
+const windowSeconds = 3600
+
+type bucket struct {
+    second int64
+    hits   uint64
+    misses uint64
+}
+
+type window struct {
+    mu      sync.Mutex
+    buckets [windowSeconds]bucket
+}
+
+var stats window
+
+var (
+    cacheHits = prometheus.NewCounter(prometheus.CounterOpts{
+        Name: "cache_hits_total",
+        Help: "Cache lookups that returned a cached value.",
+    })
+    cacheMisses = prometheus.NewCounter(prometheus.CounterOpts{
+        Name: "cache_misses_total",
+        Help: "Cache lookups that found no entry.",
+    })
+    // 조회 시점의 최근 5분 적중률. 조회가 없었으면 0
+    cacheHitRatio = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+        Name: "cache_hit_ratio",
+        Help: "Cache hit ratio over the last 5 minutes.",
+    }, func() float64 {
+        hits, misses := stats.totals(5 * time.Minute)
+        if hits+misses == 0 {
+            return 0
+        }
+        return float64(hits) / float64(hits+misses)
+    })
+)
+
+func init() {
+    prometheus.MustRegister(cacheHitRatio, cacheHits, cacheMisses)
+}
+
+func Record(hit bool) {
+    stats.record(hit)
+    if hit {
+        cacheHits.Inc()
+    } else {
+        cacheMisses.Inc()
+    }
+}
+
+func (w *window) record(hit bool) {
+    now := clock.Now().Unix()
+
+    w.mu.Lock()
+    defer w.mu.Unlock()
+
+    b := &w.buckets[now%windowSeconds]
+    if b.second != now {
+        *b = bucket{second: now}
+    }
+    if hit {
+        b.hits++
+    } else {
+        b.misses++
+    }
+}
+
+func (w *window) totals(d time.Duration) (hits, misses uint64) {
+    now := clock.Now().Unix()
+    seconds := int64(d / time.Second)
+
+    w.mu.Lock()
+    defer w.mu.Unlock()
+
+    for i := int64(0); i < seconds; i++ {
+        second := now - i
+        b := w.buckets[second%windowSeconds]
+        if b.second == second {
+            hits += b.hits
+            misses += b.misses
+        }
+    }
+    return hits, misses
+}
+
+// GET /v1/cache/report?window=5m
+func Report(c *gin.Context) {
+    d, err := time.ParseDuration(c.DefaultQuery("window", "5m"))
+    if err != nil || d < time.Second || d > windowSeconds*time.Second {
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "window must be a duration between 1s and 1h")
+        return
+    }
+
+    hits, misses := stats.totals(d)
+    ratio := 0.0
+    if hits+misses > 0 {
+        ratio = float64(hits) / float64(hits+misses)
+    }
+
+    respond.JSON(c, http.StatusOK, gin.H{
+        "window":    d.String(),
+        "hits":      hits,
+        "misses":    misses,
+        "hit_ratio": ratio,
+    })
+}
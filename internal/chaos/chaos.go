@@ -0,0 +1,36 @@
+// Package chaos는 게임데이용 장애 주입임. 환경 변수가 없거나 0이면 아무 동작도 하지 않음
+package chaos
+
+import (
+    "errors"
+    "log"
+    "log/slog"
+    "math/rand"
+    "os"
+    "strconv"
+)
+
+var ErrInjected = errors.New("chaos: injected failure")
+
+// name 환경 변수에서 0과 1 사이의 실패 비율을 읽음. 잘못된 값이면 종료함
+func Rate(logger *slog.Logger, name string) float64 {
+    v := os.Getenv(name)
+    if v == "" {
+        return 0
+    }
+    rate, err := strconv.ParseFloat(v, 64)
+    if err != nil || rate < 0 || rate > 1 {
+        log.Fatalf("invalid %s %q (want a value between 0 and 1)", name, v)
+    }
+    if rate > 0 {
+        logger.Warn("Chaos failure injection enabled", "variable", name, "rate", rate)
+    }
+    return rate
+}
+
+func Inject(rate float64) error {
+    if rate > 0 && rand.Float64() < rate {
+        return ErrInjected
+    }
+    return nil
+}
@@ -0,0 +1,63 @@
+// Package clock는 세 서비스가 함께 쓰는 시계임. 시간에 의존하는 코드는 time.Now() 대신 clock.Now()를 사용함.
+// 기본값은 실제 시계이며 테스트에서는 Set으로 Fake를 끼움
+package clock
+
+import (
+    "sync"
+    "time"
+)
+
+type Clock interface {
+    Now() time.Time
+}
+
+type Real struct{}
+
+func (Real) Now() time.Time {
+    return time.Now()
+}
+
+type Fake struct {
+    mu  sync.Mutex
+    now time.Time
+}
+
+func NewFake(now time.Time) *Fake {
+    return &Fake{now: now}
+}
+
+func (f *Fake) Now() time.Time {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    return f.now
+}
+
+func (f *Fake) Advance(d time.Duration) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    f.now = f.now.Add(d)
+}
+
+var (
+    mu      sync.RWMutex
+    current Clock = Real{}
+)
+
+func Now() time.Time {
+    mu.RLock()
+    defer mu.RUnlock()
+    return current.Now()
+}
+
+// 이전 시계로 되돌리는 함수를 반환함
+func Set(c Clock) (restore func()) {
+    mu.Lock()
+    defer mu.Unlock()
+    prev := current
+    current = c
+    return func() {
+        mu.Lock()
+        defer mu.Unlock()
+        current = prev
+    }
+}
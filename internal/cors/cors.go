@@ -0,0 +1,94 @@
+// Package cors는 세 서비스의 CORS 미들웨어임
+package cors
+
+import (
+    "log"
+    "net/http"
+    "os"
+    "strconv"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+)
+
+// CORS_ALLOWED_ORIGINS가 비어 있으면 CORS 헤더를 붙이지 않아 브라우저의 교차 출처 호출은 모두 막힘.
+// "*"를 넣으면 모든 출처를 허용함. 메서드와 헤더 목록은 기본값으로 이 서비스들이 쓰는 것을 모두 포함함
+var (
+    AllowedOrigins = parseCSV(os.Getenv("CORS_ALLOWED_ORIGINS"))
+    allowedMethods = strings.Join(csvEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE"), ", ")
+    allowedHeaders = strings.Join(csvEnv("CORS_ALLOWED_HEADERS", "Authorization,Content-Type,X-API-Key,X-Request-ID,Idempotency-Key,If-None-Match"), ", ")
+    exposedHeaders = "X-Request-ID, ETag, Retry-After, Idempotent-Replayed"
+    maxAge         = maxAgeSeconds()
+)
+
+func parseCSV(v string) []string {
+    var items []string
+    for _, item := range strings.Split(v, ",") {
+        if item = strings.TrimSpace(item); item != "" {
+            items = append(items, item)
+        }
+    }
+    return items
+}
+
+func csvEnv(name, def string) []string {
+    if v := os.Getenv(name); v != "" {
+        return parseCSV(v)
+    }
+    return parseCSV(def)
+}
+
+func maxAgeSeconds() string {
+    v := os.Getenv("CORS_MAX_AGE_SECONDS")
+    if v == "" {
+        return "600"
+    }
+    seconds, err := strconv.Atoi(v)
+    if err != nil || seconds < 0 {
+        log.Fatalf("invalid CORS_MAX_AGE_SECONDS %q (want a non-negative integer)", v)
+    }
+    return strconv.Itoa(seconds)
+}
+
+func originAllowed(origin string) bool {
+    for _, allowed := range AllowedOrigins {
+        if allowed == "*" || strings.EqualFold(allowed, origin) {
+            return true
+        }
+    }
+    return false
+}
+
+// 인증 미들웨어보다 앞에 두어야 함. 브라우저는 preflight 요청에 Authorization이나 API 키를 싣지 않음
+func Middleware() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        origin := c.GetHeader("Origin")
+        if origin == "" {
+            c.Next()
+            return
+        }
+        // 출처별로 응답이 달라지므로 캐시가 섞이지 않도록 함
+        c.Writer.Header().Add("Vary", "Origin")
+
+        preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+        if !originAllowed(origin) {
+            if preflight {
+                c.AbortWithStatus(http.StatusForbidden)
+                return
+            }
+            c.Next()
+            return
+        }
+
+        c.Header("Access-Control-Allow-Origin", origin)
+        if preflight {
+            c.Header("Access-Control-Allow-Methods", allowedMethods)
+            c.Header("Access-Control-Allow-Headers", allowedHeaders)
+            c.Header("Access-Control-Max-Age", maxAge)
+            c.AbortWithStatus(http.StatusNoContent)
+            return
+        }
+        c.Header("Access-Control-Expose-Headers", exposedHeaders)
+        c.Next()
+    }
+}
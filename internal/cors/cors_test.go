@@ -0,0 +1,91 @@
+package cors
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/apikey"
+)
+
+// API 키가 필요한 경로 앞에 CORS를 두고 https://shop.example만 허용함
+func newTestRouter(t *testing.T) *gin.Engine {
+    t.Helper()
+    prev := AllowedOrigins
+    AllowedOrigins = []string{"https://shop.example"}
+    t.Cleanup(func() { AllowedOrigins = prev })
+
+    gin.SetMode(gin.TestMode)
+    router := gin.New()
+    router.Use(Middleware())
+    router.Use(apikey.Middleware(apikey.Parse("k1"), func(*gin.Context) bool { return false }))
+    router.GET("/v1/item", func(c *gin.Context) { c.Status(http.StatusOK) })
+    return router
+}
+
+func serve(router http.Handler, method, origin string, headers map[string]string) *httptest.ResponseRecorder {
+    req := httptest.NewRequest(method, "/v1/item", nil)
+    if origin != "" {
+        req.Header.Set("Origin", origin)
+    }
+    for k, v := range headers {
+        req.Header.Set(k, v)
+    }
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+    return w
+}
+
+// 허용된 출처의 preflight는 API 키 없이 204와 허용 목록을 받고, 다른 출처는 403
+func TestPreflight(t *testing.T) {
+    router := newTestRouter(t)
+    preflight := map[string]string{"Access-Control-Request-Method": "PUT", "Access-Control-Request-Headers": "Authorization"}
+
+    w := serve(router, http.MethodOptions, "https://SHOP.example", preflight)
+    h := w.Header()
+    if w.Code != http.StatusNoContent || h.Get("Access-Control-Allow-Origin") != "https://SHOP.example" {
+        t.Fatalf("allowed preflight: status %d headers %v, want 204 echoing the origin", w.Code, h)
+    }
+    if h.Get("Access-Control-Allow-Methods") != allowedMethods || h.Get("Access-Control-Allow-Headers") != allowedHeaders || h.Get("Access-Control-Max-Age") != maxAge || h.Get("Vary") != "Origin" {
+        t.Errorf("allowed preflight headers %v", h)
+    }
+
+    w = serve(router, http.MethodOptions, "https://evil.example", preflight)
+    if w.Code != http.StatusForbidden || w.Header().Get("Access-Control-Allow-Origin") != "" {
+        t.Errorf("other origin preflight: status %d headers %v, want 403 without Allow-Origin", w.Code, w.Header())
+    }
+}
+
+// 실제 요청은 출처와 관계없이 인증을 거치고, 허용된 출처에만 Allow-Origin과 Expose-Headers를 붙임
+func TestSimpleRequest(t *testing.T) {
+    router := newTestRouter(t)
+    key := map[string]string{apikey.Header: "k1"}
+
+    w := serve(router, http.MethodGet, "https://shop.example", key)
+    if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "https://shop.example" || w.Header().Get("Access-Control-Expose-Headers") != exposedHeaders {
+        t.Errorf("allowed origin: status %d headers %v", w.Code, w.Header())
+    }
+    if w := serve(router, http.MethodGet, "https://shop.example", nil); w.Code != http.StatusUnauthorized {
+        t.Errorf("allowed origin without key: status %d, want 401", w.Code)
+    }
+
+    w = serve(router, http.MethodGet, "https://evil.example", key)
+    if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "" {
+        t.Errorf("other origin: status %d headers %v, want 200 without Allow-Origin", w.Code, w.Header())
+    }
+    w = serve(router, http.MethodGet, "", key)
+    if w.Code != http.StatusOK || w.Header().Get("Vary") != "" {
+        t.Errorf("no origin: status %d headers %v, want 200 without CORS headers", w.Code, w.Header())
+    }
+}
+
+func TestWildcardOrigin(t *testing.T) {
+    router := newTestRouter(t)
+    AllowedOrigins = []string{"*"}
+
+    w := serve(router, http.MethodOptions, "https://any.example", map[string]string{"Access-Control-Request-Method": "GET"})
+    if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "https://any.example" {
+        t.Errorf("status %d headers %v, want 204 echoing the origin", w.Code, w.Header())
+    }
+}
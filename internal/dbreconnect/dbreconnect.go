@@ -0,0 +1,87 @@
+// Package dbreconnect는 MySQL 연결이 끊긴 뒤 쿼리를 다시 실행함.
+// RDS 장애 조치나 유지 보수로 풀의 연결이 끊기면 database/sql은 이미 보낸 쿼리를 재시도하지 않으므로
+// 연결 오류일 때 풀을 Ping으로 확인한 뒤 최대 Retries번 다시 실행함.
+// 쓰기는 첫 시도가 서버에서 이미 적용됐을 수 있으므로 재시도 결과를 호출하는 쪽이 확인해야 함
+package dbreconnect
+
+import (
+    "context"
+    "database/sql/driver"
+    "errors"
+    "log"
+    "log/slog"
+    "os"
+    "strconv"
+
+    "github.com/go-sql-driver/mysql"
+)
+
+// DB_RECONNECT_RETRIES. 테스트에서 바꿀 수 있도록 변수로 둠
+var Retries = retriesFromEnv()
+
+func retriesFromEnv() int {
+    v := os.Getenv("DB_RECONNECT_RETRIES")
+    if v == "" {
+        return 1
+    }
+    n, err := strconv.Atoi(v)
+    if err != nil || n < 0 {
+        log.Fatalf("invalid DB_RECONNECT_RETRIES %q", v)
+    }
+    return n
+}
+
+// driver.ErrBadConn은 드라이버가 아무것도 보내기 전에 연결이 끊긴 경우이고,
+// mysql.ErrInvalidConn은 쿼리를 보낸 뒤 끊겨 서버에서 실행됐는지 알 수 없는 경우임
+func isConnectionError(err error) bool {
+    return errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn)
+}
+
+func IsDuplicateKey(err error) bool {
+    var mysqlErr *mysql.MySQLError
+    return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
+// *sql.DB와 *sqlx.DB가 만족함
+type Pinger interface {
+    PingContext(ctx context.Context) error
+}
+
+// 읽기와, 같은 값으로 다시 실행해도 되는 UPDATE/DELETE용
+func Do(ctx context.Context, db Pinger, fn func() error) error {
+    return retryAfterConnectionError(ctx, db, fn(), fn)
+}
+
+// INSERT용. 끊기기 전에 첫 시도가 커밋됐다면 재시도는 중복 키(1062)로 실패하므로,
+// 재시도에서 중복 키가 나면 stored로 저장된 행이 이번에 넣은 값인지 확인하고 맞으면 성공으로 봄.
+// 다른 요청이 먼저 넣은 행이면 중복 키 오류를 그대로 반환함
+func DoInsert(ctx context.Context, db Pinger, fn func() error, stored func() (bool, error)) error {
+    return retryAfterConnectionError(ctx, db, fn(), func() error {
+        err := fn()
+        if !IsDuplicateKey(err) {
+            return err
+        }
+        ok, verifyErr := stored()
+        if verifyErr != nil {
+            return verifyErr
+        }
+        if !ok {
+            return err
+        }
+        slog.InfoContext(ctx, "Insert was applied before the connection dropped")
+        return nil
+    })
+}
+
+// Ping이 실패하면 풀이 아직 복구되지 않은 것이므로 다시 실행하지 않고 원래 오류를 반환함
+func retryAfterConnectionError(ctx context.Context, db Pinger, err error, retry func() error) error {
+    for attempt := 1; attempt <= Retries && isConnectionError(err); attempt++ {
+        slog.WarnContext(ctx, "Retrying after DB connection error", "attempt", attempt, "error", err)
+        if pingErr := db.PingContext(ctx); pingErr != nil {
+            slog.ErrorContext(ctx, "DB ping failed after connection error", "error", pingErr)
+            return err
+        }
+        err = retry()
+    }
+    return err
+}
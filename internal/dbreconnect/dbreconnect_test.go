@@ -0,0 +1,227 @@
+package dbreconnect
+
+import (
+    "context"
+    "database/sql"
+    "database/sql/driver"
+    "errors"
+    "io"
+    "sync"
+    "testing"
+
+    "github.com/go-sql-driver/mysql"
+    "github.com/jmoiron/sqlx"
+)
+
+// 연결이 끊기는 상황을 흉내 내는 database/sql 드라이버. 행은 연결끼리 공유하고 drops의 오류를
+// 다음 문장부터 하나씩 반환함. applied가 true면 서버에서 실행된 뒤 응답 전에 끊긴 경우임
+type droppingServer struct {
+    mu      sync.Mutex
+    rows    map[string]string
+    drops   []connDrop
+    execs   int
+    pingErr error
+}
+
+type connDrop struct {
+    err     error
+    applied bool
+}
+
+func (s *droppingServer) Connect(context.Context) (driver.Conn, error) {
+    return &droppingConn{s}, nil
+}
+
+func (s *droppingServer) Driver() driver.Driver {
+    return droppingDriver{}
+}
+
+// 다음 문장의 끊김 여부를 꺼내고 applied가 아니면 run을 건너뜀
+func (s *droppingServer) do(run func() error) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.execs++
+    var drop connDrop
+    if len(s.drops) > 0 {
+        drop, s.drops = s.drops[0], s.drops[1:]
+    }
+    if drop.err != nil && !drop.applied {
+        return drop.err
+    }
+    if err := run(); err != nil {
+        return err
+    }
+    return drop.err
+}
+
+type droppingDriver struct{}
+
+func (droppingDriver) Open(string) (driver.Conn, error) {
+    return nil, errors.New("use the connector")
+}
+
+type droppingConn struct {
+    s *droppingServer
+}
+
+func (c *droppingConn) Prepare(string) (driver.Stmt, error) {
+    return nil, errors.New("prepare not supported")
+}
+
+func (c *droppingConn) Close() error {
+    return nil
+}
+
+func (c *droppingConn) Begin() (driver.Tx, error) {
+    return nil, errors.New("transactions not supported")
+}
+
+func (c *droppingConn) Ping(context.Context) error {
+    return c.s.pingErr
+}
+
+// INSERT (id, name)만 처리함
+func (c *droppingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+    id, name := args[0].Value.(string), args[1].Value.(string)
+    err := c.s.do(func() error {
+        if _, ok := c.s.rows[id]; ok {
+            return &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '" + id + "' for key 'PRIMARY'"}
+        }
+        c.s.rows[id] = name
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return driver.RowsAffected(1), nil
+}
+
+// SELECT name ... WHERE id = ?만 처리함
+func (c *droppingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+    id := args[0].Value.(string)
+    rows := &nameRows{}
+    err := c.s.do(func() error {
+        if name, ok := c.s.rows[id]; ok {
+            rows.names = []string{name}
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return rows, nil
+}
+
+type nameRows struct {
+    names []string
+}
+
+func (r *nameRows) Columns() []string {
+    return []string{"name"}
+}
+
+func (r *nameRows) Close() error {
+    return nil
+}
+
+func (r *nameRows) Next(dest []driver.Value) error {
+    if len(r.names) == 0 {
+        return io.EOF
+    }
+    dest[0], r.names = r.names[0], r.names[1:]
+    return nil
+}
+
+var db *sqlx.DB
+
+func useDroppingDB(t *testing.T, drops ...connDrop) *droppingServer {
+    t.Helper()
+    server := &droppingServer{rows: map[string]string{}, drops: drops}
+    db = sqlx.NewDb(sql.OpenDB(server), "mysql")
+    prevRetries := Retries
+    Retries = 1
+    t.Cleanup(func() {
+        db.Close()
+        Retries = prevRetries
+    })
+    return server
+}
+
+func insertName(ctx context.Context, id, name string) error {
+    return DoInsert(ctx, db, func() error {
+        _, err := db.ExecContext(ctx, "INSERT INTO items (id, name) VALUES (?, ?)", id, name)
+        return err
+    }, func() (bool, error) {
+        var stored string
+        if err := db.GetContext(ctx, &stored, "SELECT name FROM items WHERE id = ?", id); err != nil {
+            return false, err
+        }
+        return stored == name, nil
+    })
+}
+
+// 첫 INSERT가 커밋된 뒤 연결이 끊기면 재시도는 중복 키가 나지만 저장된 행이 같으므로 성공
+func TestInsertAppliedBeforeDropSucceeds(t *testing.T) {
+    server := useDroppingDB(t, connDrop{err: mysql.ErrInvalidConn, applied: true})
+
+    if err := insertName(context.Background(), "c1", "alice"); err != nil {
+        t.Fatalf("insert: %v", err)
+    }
+    if server.rows["c1"] != "alice" || len(server.rows) != 1 {
+        t.Errorf("rows %v, want c1=alice once", server.rows)
+    }
+}
+
+// 재시도에서 난 중복 키가 다른 요청이 넣은 행이면 성공으로 바꾸지 않음
+func TestInsertRetryKeepsDuplicateOfOtherRow(t *testing.T) {
+    server := useDroppingDB(t, connDrop{err: mysql.ErrInvalidConn})
+    server.rows["c1"] = "bob"
+
+    err := insertName(context.Background(), "c1", "alice")
+    if !IsDuplicateKey(err) {
+        t.Fatalf("insert: %v, want duplicate key", err)
+    }
+    if server.rows["c1"] != "bob" {
+        t.Errorf("row overwritten: %v", server.rows)
+    }
+}
+
+// 보내기 전에 끊긴 연결(driver.ErrBadConn)은 database/sql의 자체 재시도가 끝난 뒤에도 다시 실행함
+func TestInsertNotSentIsRetried(t *testing.T) {
+    bad := connDrop{err: driver.ErrBadConn}
+    server := useDroppingDB(t, bad, bad, bad)
+
+    if err := insertName(context.Background(), "c1", "alice"); err != nil {
+        t.Fatalf("insert: %v", err)
+    }
+    if server.rows["c1"] != "alice" {
+        t.Errorf("rows %v, want c1=alice", server.rows)
+    }
+}
+
+func TestReadRetriedAfterDrop(t *testing.T) {
+    server := useDroppingDB(t, connDrop{err: mysql.ErrInvalidConn})
+    server.rows["c1"] = "alice"
+
+    var name string
+    err := Do(context.Background(), db, func() error {
+        return db.GetContext(context.Background(), &name, "SELECT name FROM items WHERE id = ?", "c1")
+    })
+    if err != nil || name != "alice" {
+        t.Fatalf("read = %q, %v, want alice", name, err)
+    }
+}
+
+// Ping이 실패하면 다시 실행하지 않고 원래 오류를 반환함
+func TestNoRetryWhenPingFails(t *testing.T) {
+    server := useDroppingDB(t, connDrop{err: mysql.ErrInvalidConn})
+    server.pingErr = errors.New("connection refused")
+
+    err := insertName(context.Background(), "c1", "alice")
+    if !errors.Is(err, mysql.ErrInvalidConn) {
+        t.Fatalf("insert: %v, want ErrInvalidConn", err)
+    }
+    if server.execs != 1 {
+        t.Errorf("executed %d statements, want 1", server.execs)
+    }
+}
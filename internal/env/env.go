@@ -0,0 +1,49 @@
+// Package env는 세 서비스가 시작할 때 공통으로 하는 환경 변수 확인임
+package env
+
+import (
+    "log"
+    "log/slog"
+    "os"
+    "strings"
+)
+
+// 필수 값이 비어 있으면 서비스가 떠도 첫 요청에서야 알기 어려운 오류로 실패하므로
+// 시작할 때 한 번에 확인하고 빠진 이름을 모두 남긴 뒤 종료함
+func Require(names []string) {
+    if empty := missing(names); len(empty) > 0 {
+        log.Fatalf("missing required environment variables: %s", strings.Join(empty, ", "))
+    }
+}
+
+// 비어 있거나 공백뿐인 변수 이름을 names 순서대로 반환함
+func missing(names []string) []string {
+    var empty []string
+    for _, name := range names {
+        if strings.TrimSpace(os.Getenv(name)) == "" {
+            empty = append(empty, name)
+        }
+    }
+    return empty
+}
+
+// 모든 서비스가 AWS_REGION을 기준으로 함. 예전 product 배포가 쓰던 REGION은 호환을 위해 대신 읽고,
+// 둘 다 없으면 빈 리전으로 뜬 뒤 첫 AWS 호출에서 실패하지 않도록 바로 종료함
+func Region(logger *slog.Logger) string {
+    region, ok := lookupRegion(logger)
+    if !ok {
+        log.Fatalf("AWS_REGION is not set")
+    }
+    return region
+}
+
+func lookupRegion(logger *slog.Logger) (string, bool) {
+    if v := os.Getenv("AWS_REGION"); v != "" {
+        return v, true
+    }
+    if v := os.Getenv("REGION"); v != "" {
+        logger.Warn("REGION is deprecated, set AWS_REGION instead", "region", v)
+        return v, true
+    }
+    return "", false
+}
@@ -0,0 +1,73 @@
+package env
+
+import (
+    "bytes"
+    "errors"
+    "log/slog"
+    "os"
+    "os/exec"
+    "slices"
+    "strings"
+    "testing"
+)
+
+// AWS_REGION이 있으면 그 값을, 없으면 REGION을 경고와 함께 씀
+func TestLookupRegion(t *testing.T) {
+    tests := []struct {
+        name, awsRegion, region, want string
+        ok, warn                      bool
+    }{
+        {"AWS_REGION only", "ap-northeast-2", "", "ap-northeast-2", true, false},
+        {"both prefers AWS_REGION", "ap-northeast-2", "us-east-1", "ap-northeast-2", true, false},
+        {"REGION fallback", "", "us-east-1", "us-east-1", true, true},
+        {"neither", "", "", "", false, false},
+    }
+    for _, tt := range tests {
+        t.Setenv("AWS_REGION", tt.awsRegion)
+        t.Setenv("REGION", tt.region)
+        var buf bytes.Buffer
+        logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+        got, ok := lookupRegion(logger)
+        if got != tt.want || ok != tt.ok {
+            t.Errorf("%s: lookupRegion = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+        }
+        if warned := strings.Contains(buf.String(), "REGION is deprecated"); warned != tt.warn {
+            t.Errorf("%s: deprecation warning %v, want %v", tt.name, warned, tt.warn)
+        }
+    }
+}
+
+// 빈 값과 공백뿐인 값을 모두 빠진 것으로 보고 names 순서대로 반환함
+func TestMissing(t *testing.T) {
+    t.Setenv("TEST_ENV_SET", "value")
+    t.Setenv("TEST_ENV_EMPTY", "")
+    t.Setenv("TEST_ENV_BLANK", "  ")
+
+    got := missing([]string{"TEST_ENV_BLANK", "TEST_ENV_SET", "TEST_ENV_UNSET", "TEST_ENV_EMPTY"})
+    if want := []string{"TEST_ENV_BLANK", "TEST_ENV_UNSET", "TEST_ENV_EMPTY"}; !slices.Equal(got, want) {
+        t.Errorf("missing = %v, want %v", got, want)
+    }
+    if got := missing([]string{"TEST_ENV_SET"}); got != nil {
+        t.Errorf("missing = %v, want none", got)
+    }
+}
+
+// Require는 빠진 이름을 한 줄에 모두 남기고 종료함. 종료를 확인하려고 테스트 바이너리를 자식 프로세스로 다시 실행함
+func TestRequireExits(t *testing.T) {
+    if os.Getenv("TEST_ENV_REQUIRE_CHILD") == "1" {
+        Require([]string{"TEST_ENV_A", "TEST_ENV_B"})
+        return
+    }
+
+    cmd := exec.Command(os.Args[0], "-test.run=^TestRequireExits$")
+    cmd.Env = append(os.Environ(), "TEST_ENV_REQUIRE_CHILD=1", "TEST_ENV_A=", "TEST_ENV_B=")
+    out, err := cmd.CombinedOutput()
+    var exitErr *exec.ExitError
+    if !errors.As(err, &exitErr) || exitErr.ExitCode() == 0 {
+        t.Fatalf("child exited with %v, want a non-zero exit (%s)", err, out)
+    }
+    if !strings.Contains(string(out), "missing required environment variables: TEST_ENV_A, TEST_ENV_B") {
+        t.Errorf("output %q does not name both variables", out)
+    }
+}
@@ -0,0 +1,70 @@
+// Package healthz는 세 서비스의 /healthz 핸들러임. 확인할 의존성 목록은 서비스가 넘김
+package healthz
+
+import (
+    "context"
+    "log/slog"
+    "net/http"
+    "sync"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+)
+
+const checkTimeout = 2 * time.Second
+
+// Critical이 false인 의존성은 실패해도 서비스가 주 저장소만으로 응답할 수 있음
+type Check struct {
+    Run      func(context.Context) error
+    Critical bool
+}
+
+// 의존성을 동시에 확인하므로 응답 시간은 가장 느린 확인 하나(최대 checkTimeout)로 제한됨.
+// 필수 의존성이 실패하면 503, 캐시처럼 없어도 요청을 처리할 수 있는 의존성만 실패하면 degraded로 200을 반환함.
+// 오류 내용은 내부 주소가 드러날 수 있으므로 응답에 넣지 않고 로그로만 남김.
+// checks는 요청마다 읽으므로 시작 후에 항목을 더해도 됨
+func Handler(checks map[string]Check) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        ctx := c.Request.Context()
+        checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+        defer cancel()
+
+        var mu sync.Mutex
+        var wg sync.WaitGroup
+        statuses := make(map[string]string, len(checks))
+        status := "ok"
+
+        for name, check := range checks {
+            wg.Add(1)
+            go func(name string, check Check) {
+                defer wg.Done()
+                err := check.Run(checkCtx)
+
+                mu.Lock()
+                defer mu.Unlock()
+                if err == nil {
+                    statuses[name] = "ok"
+                    return
+                }
+                statuses[name] = "error"
+                if check.Critical {
+                    slog.ErrorContext(ctx, "Health check failed", "dependency", name, "error", err)
+                    status = "unavailable"
+                } else {
+                    slog.WarnContext(ctx, "Health check failed for optional dependency", "dependency", name, "error", err)
+                    if status == "ok" {
+                        status = "degraded"
+                    }
+                }
+            }(name, check)
+        }
+        wg.Wait()
+
+        code := http.StatusOK
+        if status == "unavailable" {
+            code = http.StatusServiceUnavailable
+        }
+        respond.JSON(c, code, gin.H{"status": status, "dependencies": statuses})
+    }
+}
@@ -0,0 +1,24 @@
+// Package ids는 요청으로 받은 id를 검사함. id는 그대로 캐시 키와 기본 키가 되므로 길이와 문자 집합을 제한함
+package ids
+
+import (
+    "fmt"
+    "regexp"
+)
+
+const MaxLength = 64
+
+var pattern = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+
+// field는 오류 메시지에 쓰는 필드 이름임
+func Check(field, id string) error {
+    switch {
+    case id == "":
+        return fmt.Errorf("%s is required", field)
+    case len(id) > MaxLength:
+        return fmt.Errorf("%s must be at most %d characters", field, MaxLength)
+    case !pattern.MatchString(id):
+        return fmt.Errorf("%s may only contain letters, digits and dashes", field)
+    }
+    return nil
+}
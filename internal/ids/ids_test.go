@@ -0,0 +1,32 @@
+package ids
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestCheck(t *testing.T) {
+    tests := []struct {
+        name, id string
+        ok       bool
+    }{
+        {"letters digits dashes", "Ab-09", true},
+        {"max length", strings.Repeat("a", MaxLength), true},
+        {"empty", "", false},
+        {"oversized", strings.Repeat("a", MaxLength+1), false},
+        {"underscore", "a_b", false},
+        {"space", "a b", false},
+        {"cache key separator", "a:b", false},
+        {"quote", "a'b", false},
+        {"non-ascii", "주문1", false},
+    }
+    for _, tt := range tests {
+        err := Check("id", tt.id)
+        if (err == nil) != tt.ok {
+            t.Errorf("%s: Check(%q) = %v, want ok %v", tt.name, tt.id, err, tt.ok)
+        }
+        if err != nil && !strings.HasPrefix(err.Error(), "id ") {
+            t.Errorf("%s: error %q does not name the field", tt.name, err)
+        }
+    }
+}
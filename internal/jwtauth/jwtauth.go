@@ -0,0 +1,170 @@
+// Package jwtauth는 세 서비스의 Bearer 토큰 검증 미들웨어임.
+// JWT_SECRET(HS256/384/512) 또는 JWT_JWKS_URL(RS/ES/PS 계열)이 있으면 Authorization: Bearer 토큰을 검증함.
+// 둘 다 없으면 JWT 인증을 하지 않음. JWT_ISSUER, JWT_AUDIENCE가 있으면 iss/aud도 확인함.
+// Bearer 토큰이 없는 요청은 API 키 인증이 켜져 있으면 API 키 검사로 넘기므로
+// 서비스 간 호출은 지금처럼 SERVICE_API_KEY로 인증할 수 있음
+package jwtauth
+
+import (
+    "context"
+    "errors"
+    "log"
+    "log/slog"
+    "net/http"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/MicahParks/keyfunc/v3"
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+    "github.com/golang-jwt/jwt/v5"
+)
+
+const (
+    subjectKey = "jwt_subject"
+    scopesKey  = "jwt_scopes"
+    Leeway     = 30 * time.Second
+)
+
+var (
+    keyFunc jwt.Keyfunc
+    parser  *jwt.Parser
+)
+
+func init() {
+    secret := os.Getenv("JWT_SECRET")
+    jwksURL := os.Getenv("JWT_JWKS_URL")
+
+    var methods []string
+    switch {
+    case secret != "" && jwksURL != "":
+        log.Fatalf("set only one of JWT_SECRET and JWT_JWKS_URL")
+    case secret != "":
+        keyFunc = secretKeyfunc(secret)
+        methods = hmacMethods
+    case jwksURL != "":
+        // 키 목록은 백그라운드에서 주기적으로 다시 받아 키 교체를 따라감
+        jwks, err := keyfunc.NewDefaultCtx(context.Background(), []string{jwksURL})
+        if err != nil {
+            log.Fatalf("failed to load JWT_JWKS_URL: %v", err)
+        }
+        keyFunc = jwks.Keyfunc
+        methods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "PS256", "PS384", "PS512"}
+    default:
+        return
+    }
+
+    options := parserOptions(methods)
+    if v := os.Getenv("JWT_ISSUER"); v != "" {
+        options = append(options, jwt.WithIssuer(v))
+    }
+    if v := os.Getenv("JWT_AUDIENCE"); v != "" {
+        options = append(options, jwt.WithAudience(v))
+    }
+    parser = jwt.NewParser(options...)
+}
+
+var hmacMethods = []string{"HS256", "HS384", "HS512"}
+
+func secretKeyfunc(secret string) jwt.Keyfunc {
+    key := []byte(secret)
+    return func(*jwt.Token) (interface{}, error) {
+        return key, nil
+    }
+}
+
+func parserOptions(methods []string) []jwt.ParserOption {
+    return []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired(), jwt.WithLeeway(Leeway)}
+}
+
+func Enabled() bool {
+    return keyFunc != nil
+}
+
+// JWT_SECRET이 설정된 것과 같게 HMAC 검증을 켬. 이전 설정으로 되돌리는 함수를 반환하며 테스트에서 씀
+func SetSecret(secret string) (restore func()) {
+    prevKeyFunc, prevParser := keyFunc, parser
+    keyFunc = secretKeyfunc(secret)
+    parser = jwt.NewParser(parserOptions(hmacMethods)...)
+    return func() { keyFunc, parser = prevKeyFunc, prevParser }
+}
+
+// 로드밸런서 헬스 체크는 토큰 없이 통과시킴. apiKeys가 true이면 Bearer 토큰이 없는 요청을 API 키 검사로 넘김
+func Middleware(apiKeys bool) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if keyFunc == nil || c.Request.URL.Path == "/healthz" {
+            c.Next()
+            return
+        }
+
+        token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+        if !ok {
+            if apiKeys {
+                c.Next()
+                return
+            }
+            respond.Error(c, http.StatusUnauthorized, respond.CodeUnauthorized, "missing bearer token")
+            return
+        }
+
+        subject, scopes, err := parse(token)
+        if err != nil {
+            slog.InfoContext(c.Request.Context(), "Rejected bearer token", "error", err)
+            respond.Error(c, http.StatusUnauthorized, respond.CodeUnauthorized, "invalid or expired token")
+            return
+        }
+
+        c.Set(subjectKey, subject)
+        c.Set(scopesKey, scopes)
+        c.Next()
+    }
+}
+
+func parse(tokenString string) (string, []string, error) {
+    var claims jwt.MapClaims
+    if _, err := parser.ParseWithClaims(tokenString, &claims, keyFunc); err != nil {
+        return "", nil, err
+    }
+    subject, err := claims.GetSubject()
+    if err != nil {
+        return "", nil, err
+    }
+    if subject == "" {
+        return "", nil, errors.New("token has no subject")
+    }
+    return subject, tokenScopes(claims), nil
+}
+
+// OAuth2 형식의 공백 구분 scope 문자열과 scp 배열(Azure AD, Okta 등)을 모두 받음
+func tokenScopes(claims jwt.MapClaims) []string {
+    var scopes []string
+    if v, ok := claims["scope"].(string); ok {
+        scopes = append(scopes, strings.Fields(v)...)
+    }
+    switch v := claims["scp"].(type) {
+    case string:
+        scopes = append(scopes, strings.Fields(v)...)
+    case []interface{}:
+        for _, item := range v {
+            if s, ok := item.(string); ok {
+                scopes = append(scopes, s)
+            }
+        }
+    }
+    return scopes
+}
+
+// 검증된 토큰의 sub. JWT로 인증하지 않은 요청이면 빈 문자열
+func Subject(c *gin.Context) string {
+    return c.GetString(subjectKey)
+}
+
+func HasScope(c *gin.Context, scope string) bool {
+    for _, s := range c.GetStringSlice(scopesKey) {
+        if s == scope {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,100 @@
+// Package keystyle은 JSON_KEY_STYLE에 따라 응답 JSON의 키 표기만 바꾸고 내부 구조체 태그는 그대로 둠.
+// 기본값 Current는 기존 키(customerid, productid)를 유지하므로 기존 연동은 영향 없음.
+// customer, product, order 서비스가 모든 응답을 이 패키지를 거쳐 보냄
+package keystyle
+
+import (
+    "encoding/json"
+    "fmt"
+    "strings"
+)
+
+const (
+    Current = "current"
+    Camel   = "camel"
+    Snake   = "snake"
+)
+
+// 단어 경계 없이 붙여 쓴 예전 키의 snake_case 이름. 나머지 키는 이미 snake_case이거나 한 단어라고 보고
+// camelCase는 snake_case 이름에서 기계적으로 만듦. 붙여 쓴 키를 새로 추가할 때만 여기에 넣음
+var legacySnakeNames = map[string]string{
+    "customerid": "customer_id",
+    "productid":  "product_id",
+    "unitprice":  "unit_price",
+    "createdat":  "created_at",
+    "updatedat":  "updated_at",
+    "deletedat":  "deleted_at",
+}
+
+func snakeName(key string) string {
+    if name, ok := legacySnakeNames[key]; ok {
+        return name
+    }
+    return key
+}
+
+// order_id -> orderId, missing_from_db -> missingFromDb
+func camelName(key string) string {
+    words := strings.Split(snakeName(key), "_")
+    for i := 1; i < len(words); i++ {
+        if words[i] != "" {
+            words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+        }
+    }
+    return strings.Join(words, "")
+}
+
+var renamers = map[string]func(string) string{
+    Camel: camelName,
+    Snake: snakeName,
+}
+
+// JSON_KEY_STYLE 값을 검사함. 빈 값은 Current
+func Parse(v string) (string, error) {
+    switch v {
+    case "":
+        return Current, nil
+    case Current, Camel, Snake:
+        return v, nil
+    default:
+        return "", fmt.Errorf("invalid JSON_KEY_STYLE %q (want current, camel or snake)", v)
+    }
+}
+
+// obj를 JSON 값으로 바꾼 뒤 키를 style로 고쳐 반환함. Current이거나 변환하지 못하면 obj를 그대로 반환함.
+// 응답의 맵 키는 모두 필드 이름이어야 함. id 같은 데이터를 키로 쓰면 그 값도 바뀜
+func Apply(style string, obj interface{}) interface{} {
+    rename, ok := renamers[style]
+    if !ok {
+        return obj
+    }
+
+    data, err := json.Marshal(obj)
+    if err != nil {
+        return obj
+    }
+
+    var generic interface{}
+    if err := json.Unmarshal(data, &generic); err != nil {
+        return obj
+    }
+    return renameKeys(generic, rename)
+}
+
+func renameKeys(v interface{}, rename func(string) string) interface{} {
+    switch val := v.(type) {
+    case map[string]interface{}:
+        out := make(map[string]interface{}, len(val))
+        for k, item := range val {
+            out[rename(k)] = renameKeys(item, rename)
+        }
+        return out
+    case []interface{}:
+        for i, item := range val {
+            val[i] = renameKeys(item, rename)
+        }
+        return val
+    default:
+        return v
+    }
+}
@@ -0,0 +1,80 @@
+package keystyle
+
+import (
+    "encoding/json"
+    "testing"
+    "time"
+)
+
+type order struct {
+    ID         string    `json:"id"`
+    CustomerID string    `json:"customerid"`
+    CreatedAt  time.Time `json:"createdat"`
+}
+
+func TestParse(t *testing.T) {
+    for _, tc := range []struct {
+        in, want string
+        ok       bool
+    }{
+        {"", Current, true},
+        {"current", Current, true},
+        {"camel", Camel, true},
+        {"snake", Snake, true},
+        {"kebab", "", false},
+    } {
+        got, err := Parse(tc.in)
+        if (err == nil) != tc.ok || got != tc.want {
+            t.Errorf("Parse(%q) = %q, %v", tc.in, got, err)
+        }
+    }
+}
+
+// 중첩된 객체와 배열 안의 키도 바꾸고 값과 다른 키는 그대로 둠
+func TestApplyRenamesNestedKeys(t *testing.T) {
+    obj := map[string]interface{}{
+        "results": []order{{ID: "o1", CustomerID: "alice"}},
+        "order":   order{ID: "o2", CustomerID: "customerid"},
+    }
+    for style, want := range map[string]string{
+        Current: `{"order":{"id":"o2","customerid":"customerid","createdat":"0001-01-01T00:00:00Z"},"results":[{"id":"o1","customerid":"alice","createdat":"0001-01-01T00:00:00Z"}]}`,
+        Camel:   `{"order":{"createdAt":"0001-01-01T00:00:00Z","customerId":"customerid","id":"o2"},"results":[{"createdAt":"0001-01-01T00:00:00Z","customerId":"alice","id":"o1"}]}`,
+        Snake:   `{"order":{"created_at":"0001-01-01T00:00:00Z","customer_id":"customerid","id":"o2"},"results":[{"created_at":"0001-01-01T00:00:00Z","customer_id":"alice","id":"o1"}]}`,
+    } {
+        data, err := json.Marshal(Apply(style, obj))
+        if err != nil {
+            t.Fatal(err)
+        }
+        if string(data) != want {
+            t.Errorf("%s:\n got %s\nwant %s", style, data, want)
+        }
+    }
+}
+
+// camel이면 원래 snake_case였던 키도 바꾸고, snake면 그대로 둠
+func TestApplySnakeCaseKeys(t *testing.T) {
+    obj := map[string]string{"request_id": "r1", "order_id": "o1", "download_url": "u", "missing_from_db": "m"}
+    for style, want := range map[string]string{
+        Camel: `{"downloadUrl":"u","missingFromDb":"m","orderId":"o1","requestId":"r1"}`,
+        Snake: `{"download_url":"u","missing_from_db":"m","order_id":"o1","request_id":"r1"}`,
+    } {
+        data, _ := json.Marshal(Apply(style, obj))
+        if string(data) != want {
+            t.Errorf("%s: got %s, want %s", style, data, want)
+        }
+    }
+}
+
+func TestCamelName(t *testing.T) {
+    for in, want := range map[string]string{
+        "id":                  "id",
+        "customerid":          "customerId",
+        "expires_at":          "expiresAt",
+        "missing_from_export": "missingFromExport",
+        "x_":                  "x",
+    } {
+        if got := camelName(in); got != want {
+            t.Errorf("camelName(%q) = %q, want %q", in, got, want)
+        }
+    }
+}
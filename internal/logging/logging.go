@@ -0,0 +1,51 @@
+// Package logging은 세 서비스의 로거임. 로그는 CloudWatch에서 파싱할 수 있도록 JSON 한 줄로 출력하고,
+// 표준 log 패키지와 slog 기본 로거도 같은 핸들러를 거치도록 기본 로거로 등록함
+package logging
+
+import (
+    "context"
+    "log/slog"
+    "os"
+
+    "github.com/gmstcl/eCommerce-System/internal/requestid"
+)
+
+func New(service string) *slog.Logger {
+    level := slog.LevelInfo
+    invalidLevel := false
+    if v := os.Getenv("LOG_LEVEL"); v != "" {
+        if err := level.UnmarshalText([]byte(v)); err != nil {
+            level = slog.LevelInfo
+            invalidLevel = true
+        }
+    }
+
+    handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})
+    l := slog.New(requestIDHandler{handler}).With("service", service)
+    slog.SetDefault(l)
+
+    if invalidLevel {
+        l.Warn("Ignoring invalid LOG_LEVEL", "value", os.Getenv("LOG_LEVEL"))
+    }
+    return l
+}
+
+// *Context 로깅 함수에 요청 컨텍스트를 넘기면 request_id가 붙음
+type requestIDHandler struct {
+    slog.Handler
+}
+
+func (h requestIDHandler) Handle(ctx context.Context, r slog.Record) error {
+    if id := requestid.From(ctx); id != "" {
+        r.AddAttrs(slog.String("request_id", id))
+    }
+    return h.Handler.Handle(ctx, r)
+}
+
+func (h requestIDHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+    return requestIDHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h requestIDHandler) WithGroup(name string) slog.Handler {
+    return requestIDHandler{h.Handler.WithGroup(name)}
+}
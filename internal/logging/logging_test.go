@@ -0,0 +1,32 @@
+package logging
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "log/slog"
+    "testing"
+
+    "github.com/gmstcl/eCommerce-System/internal/requestid"
+)
+
+// 요청 컨텍스트로 남긴 로그에만 request_id가 붙음
+func TestRequestIDHandler(t *testing.T) {
+    var buf bytes.Buffer
+    l := slog.New(requestIDHandler{slog.NewJSONHandler(&buf, nil)}).With("service", "test")
+
+    l.InfoContext(requestid.NewContext(context.Background(), "req-1"), "with id")
+    l.InfoContext(context.Background(), "without id")
+
+    dec := json.NewDecoder(&buf)
+    for _, want := range []string{"req-1", ""} {
+        var entry map[string]interface{}
+        if err := dec.Decode(&entry); err != nil {
+            t.Fatal(err)
+        }
+        got, _ := entry["request_id"].(string)
+        if got != want {
+            t.Errorf("%v: request_id %q, want %q", entry["msg"], got, want)
+        }
+    }
+}
@@ -0,0 +1,78 @@
+// Package metrics는 세 서비스가 함께 내보내는 HTTP 요청과 검증 실패 Prometheus 지표임
+package metrics
+
+import (
+    "encoding/json"
+    "errors"
+    "strconv"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/go-playground/validator/v10"
+    "github.com/prometheus/client_golang/prometheus"
+)
+
+var validationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+    Name: "validation_failures_total",
+    Help: "Requests rejected by validation, by endpoint and failing field.",
+}, []string{"endpoint", "field"})
+
+var (
+    httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Name: "http_requests_total",
+        Help: "HTTP requests, by route, method and status code.",
+    }, []string{"route", "method", "status"})
+    httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+        Name:    "http_request_duration_seconds",
+        Help:    "HTTP request latency, by route and method.",
+        Buckets: prometheus.DefBuckets,
+    }, []string{"route", "method"})
+    httpRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Name: "http_request_errors_total",
+        Help: "HTTP requests that ended with a 5xx status, by route and status code.",
+    }, []string{"route", "status"})
+)
+
+func init() {
+    prometheus.MustRegister(validationFailures, httpRequests, httpRequestDuration, httpRequestErrors)
+}
+
+// 등록되지 않은 경로는 라벨 수가 늘지 않도록 하나로 묶음
+func Middleware() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        start := time.Now()
+        c.Next()
+
+        route := c.FullPath()
+        if route == "" {
+            route = "unmatched"
+        }
+        status := strconv.Itoa(c.Writer.Status())
+
+        httpRequests.WithLabelValues(route, c.Request.Method, status).Inc()
+        httpRequestDuration.WithLabelValues(route, c.Request.Method).Observe(time.Since(start).Seconds())
+        if c.Writer.Status() >= 500 {
+            httpRequestErrors.WithLabelValues(route, status).Inc()
+        }
+    }
+}
+
+func RecordValidationField(c *gin.Context, field string) {
+    validationFailures.WithLabelValues(c.FullPath(), field).Inc()
+}
+
+// 필드를 특정할 수 없는 JSON 파싱 오류는 "body"로 집계함
+func RecordValidationFailure(c *gin.Context, err error) {
+    var validationErrs validator.ValidationErrors
+    var typeErr *json.UnmarshalTypeError
+    switch {
+    case errors.As(err, &validationErrs):
+        for _, fe := range validationErrs {
+            RecordValidationField(c, fe.Field())
+        }
+    case errors.As(err, &typeErr) && typeErr.Field != "":
+        RecordValidationField(c, typeErr.Field)
+    default:
+        RecordValidationField(c, "body")
+    }
+}
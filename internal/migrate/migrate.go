@@ -0,0 +1,151 @@
+// Package migrate는 customer, product 서비스의 스키마 마이그레이션 러너임.
+// 서비스의 migrations/NNNN_설명.sql을 번호 순으로 한 번씩 실행하고 schema_migrations에 기록함.
+// MIGRATE_ON_START=true일 때만 시작 시 실행함. 이미 적용된 파일은 고치지 말고 새 번호로 추가함.
+// 0001은 처음 배포된 스키마 그대로이고 이후 컬럼과 인덱스는 0002부터 ALTER로 추가함.
+// MySQL의 DDL은 트랜잭션으로 묶이지 않으므로 ALTER 문장 하나에는 컬럼이나 인덱스를 하나만 넣음
+package migrate
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "io/fs"
+    "log"
+    "log/slog"
+    "os"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+    "github.com/gmstcl/eCommerce-System/internal/sqlq"
+    "github.com/go-sql-driver/mysql"
+    "github.com/jmoiron/sqlx"
+)
+
+const lockTimeout = 60 * time.Second
+
+type Migration struct {
+    Version int
+    Name    string
+    SQL     string
+}
+
+func OnStart() bool {
+    v := os.Getenv("MIGRATE_ON_START")
+    if v == "" {
+        return false
+    }
+    enabled, err := strconv.ParseBool(v)
+    if err != nil {
+        log.Fatalf("invalid MIGRATE_ON_START %q (want true or false)", v)
+    }
+    return enabled
+}
+
+// files는 서비스가 embed한 migrations/*.sql임
+func Load(files fs.FS) ([]Migration, error) {
+    paths, err := fs.Glob(files, "migrations/*.sql")
+    if err != nil {
+        return nil, err
+    }
+
+    var migrations []Migration
+    seen := make(map[int]string)
+    for _, path := range paths {
+        name := strings.TrimPrefix(path, "migrations/")
+        prefix, _, ok := strings.Cut(name, "_")
+        version, err := strconv.Atoi(prefix)
+        if !ok || err != nil || version <= 0 {
+            return nil, fmt.Errorf("migration %s must be named NNNN_description.sql", name)
+        }
+        if prev, ok := seen[version]; ok {
+            return nil, fmt.Errorf("migrations %s and %s share version %d", prev, name, version)
+        }
+        seen[version] = name
+
+        data, err := fs.ReadFile(files, path)
+        if err != nil {
+            return nil, err
+        }
+        migrations = append(migrations, Migration{Version: version, Name: name, SQL: string(data)})
+    }
+
+    sort.Slice(migrations, func(i, j int) bool {
+        return migrations[i].Version < migrations[j].Version
+    })
+    return migrations, nil
+}
+
+// 여러 인스턴스가 동시에 시작해도 한 곳에서만 실행되도록 GET_LOCK으로 잠근 같은 연결에서 처리함
+func Run(ctx context.Context, db *sqlx.DB, files fs.FS) error {
+    migrations, err := Load(files)
+    if err != nil {
+        return err
+    }
+
+    conn, err := db.Connx(ctx)
+    if err != nil {
+        return err
+    }
+    defer conn.Close()
+
+    var locked int
+    if err := sqlq.New("SELECT GET_LOCK('schema_migrations', ?)", int(lockTimeout.Seconds())).GetContext(ctx, conn, &locked); err != nil {
+        return err
+    }
+    if locked != 1 {
+        return fmt.Errorf("timed out waiting for migration lock")
+    }
+    defer sqlq.New("SELECT RELEASE_LOCK('schema_migrations')").ExecContext(context.WithoutCancel(ctx), conn)
+
+    _, err = sqlq.New("CREATE TABLE IF NOT EXISTS schema_migrations (version INT NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at DATETIME(6) NOT NULL)").ExecContext(ctx, conn)
+    if err != nil {
+        return err
+    }
+
+    var applied []int
+    if err := sqlq.New("SELECT version FROM schema_migrations").SelectContext(ctx, conn, &applied); err != nil {
+        return err
+    }
+    done := make(map[int]bool)
+    for _, version := range applied {
+        done[version] = true
+    }
+
+    for _, m := range migrations {
+        if done[m.Version] {
+            continue
+        }
+        // DSN에 multiStatements를 켜지 않았으므로 문장 단위로 나눠 실행함.
+        // 파일 내용은 상수가 아니므로 sqlq를 거치지 않는 유일한 SQL임
+        for _, stmt := range strings.Split(m.SQL, ";") {
+            if stmt = strings.TrimSpace(stmt); stmt == "" {
+                continue
+            }
+            if _, err := conn.ExecContext(ctx, stmt); err != nil {
+                if alreadyApplied(err) {
+                    slog.Warn("Skipping statement already applied by hand", "version", m.Version, "name", m.Name, "error", err)
+                    continue
+                }
+                return fmt.Errorf("migration %s: %w", m.Name, err)
+            }
+        }
+        if _, err := sqlq.New("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)", m.Version, m.Name, clock.Now().UTC().Truncate(time.Microsecond)).ExecContext(ctx, conn); err != nil {
+            return fmt.Errorf("migration %s: %w", m.Name, err)
+        }
+        slog.Info("Applied migration", "version", m.Version, "name", m.Name)
+    }
+    return nil
+}
+
+// 러너가 생기기 전에는 ALTER를 직접 실행하도록 안내했으므로, 이미 있는 컬럼(1060)이나 인덱스(1061)는
+// 그 문장이 적용된 것으로 봄
+func alreadyApplied(err error) bool {
+    var mysqlErr *mysql.MySQLError
+    if !errors.As(err, &mysqlErr) {
+        return false
+    }
+    return mysqlErr.Number == 1060 || mysqlErr.Number == 1061
+}
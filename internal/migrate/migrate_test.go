@@ -0,0 +1,25 @@
+package migrate
+
+import (
+    "errors"
+    "testing"
+
+    "github.com/go-sql-driver/mysql"
+)
+
+func TestAlreadyApplied(t *testing.T) {
+    tests := []struct {
+        err  error
+        want bool
+    }{
+        {&mysql.MySQLError{Number: 1060, Message: "Duplicate column name 'version'"}, true},
+        {&mysql.MySQLError{Number: 1061, Message: "Duplicate key name 'idx_category'"}, true},
+        {&mysql.MySQLError{Number: 1146, Message: "Table 'items' doesn't exist"}, false},
+        {errors.New("connection refused"), false},
+    }
+    for _, tt := range tests {
+        if got := alreadyApplied(tt.err); got != tt.want {
+            t.Errorf("alreadyApplied(%v) = %v, want %v", tt.err, got, tt.want)
+        }
+    }
+}
@@ -0,0 +1,44 @@
+// Package mysqltest는 customer, product 서비스 테스트에서 쓰는 인메모리 MySQL 서버를 띄움.
+// go-mysql-server를 실제 MySQL 프로토콜로 열어 서비스와 같은 드라이버와 준비된 문장으로 접속함
+package mysqltest
+
+import (
+    "fmt"
+    "net"
+    "testing"
+
+    sqle "github.com/dolthub/go-mysql-server"
+    "github.com/dolthub/go-mysql-server/memory"
+    "github.com/dolthub/go-mysql-server/server"
+    "github.com/dolthub/go-mysql-server/sql"
+    "github.com/sirupsen/logrus"
+)
+
+const dbName = "test"
+
+func init() {
+    // 서버가 쿼리 오류마다 남기는 경고가 테스트 출력을 덮지 않도록 함
+    logrus.SetLevel(logrus.ErrorLevel)
+}
+
+// 빈 데이터베이스 하나를 가진 서버를 띄우고 서비스와 같은 옵션의 DSN을 반환함. 서버는 테스트가 끝나면 닫힘
+func Start(t testing.TB) string {
+    t.Helper()
+    db := memory.NewDatabase(dbName)
+    db.BaseDatabase.EnablePrimaryKeyIndexes()
+    provider := memory.NewDBProvider(db)
+    engine := sqle.NewDefault(provider)
+
+    listener, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatalf("mysqltest: listen: %v", err)
+    }
+    srv, err := server.NewServer(server.Config{Protocol: "tcp", Address: listener.Addr().String(), Listener: listener}, engine, sql.NewContext, memory.NewSessionBuilder(provider), nil)
+    if err != nil {
+        t.Fatalf("mysqltest: start server: %v", err)
+    }
+    go srv.Start()
+    t.Cleanup(func() { srv.Close() })
+
+    return fmt.Sprintf("root:@tcp(%s)/%s?clientFoundRows=true&parseTime=true", listener.Addr(), dbName)
+}
@@ -0,0 +1,170 @@
+// Package redisconn은 세 서비스의 Redis 연결 설정과, customer/product 서비스의 재연결 루프임
+package redisconn
+
+import (
+    "context"
+    "crypto/tls"
+    "crypto/x509"
+    "log"
+    "log/slog"
+    "os"
+    "strconv"
+    "sync/atomic"
+    "time"
+
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/go-redis/redis/v8"
+)
+
+// 시작 시 Redis에 연결하지 못해도 서비스는 DB만으로 뜨고 백그라운드 루프가 주기적으로 다시 확인함.
+// 연속 rebuildAfter번 실패하면 풀에 남은 끊긴 연결을 버리도록 클라이언트를 새로 만듦
+const (
+    checkInterval = 10 * time.Second
+    rebuildAfter  = 3
+)
+
+// 영값은 Connect를 부르기 전까지 클라이언트가 없는 상태임
+type Conn struct {
+    opts      *redis.Options
+    current   atomic.Pointer[redis.Client]
+    connected atomic.Bool
+}
+
+func (c *Conn) Connect(opts *redis.Options) {
+    c.opts = opts
+    c.current.Store(redis.NewClient(opts))
+    if err := c.Check(context.Background()); err != nil {
+        slog.Error("Redis connection error", "error", err)
+    }
+}
+
+func (c *Conn) Options() *redis.Options {
+    return c.opts
+}
+
+// 재연결 중에 교체될 수 있으므로 호출할 때마다 현재 클라이언트를 가져옴
+func (c *Conn) Client() *redis.Client {
+    return c.current.Load()
+}
+
+// client로 바꾸고 이전 클라이언트를 반환함. 테스트에서 miniredis를 끼울 때 씀
+func (c *Conn) Replace(client *redis.Client) *redis.Client {
+    return c.current.Swap(client)
+}
+
+// 상태가 바뀔 때만 로그를 남김. /healthz도 이 함수를 통해 확인함
+func (c *Conn) Check(ctx context.Context) error {
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := c.Client().Ping(ctx).Err(); err != nil {
+        if c.connected.Swap(false) {
+            slog.Error("Lost Redis connection", "error", err)
+        }
+        return err
+    }
+    if !c.connected.Swap(true) {
+        slog.Info("Connected to Redis successfully")
+    }
+    return nil
+}
+
+// 반환한 함수를 부르면 루프를 멈추고 끝날 때까지 기다림
+func (c *Conn) StartMonitor() func() {
+    ctx, cancel := context.WithCancel(context.Background())
+    done := make(chan struct{})
+    go func() {
+        defer close(done)
+        ticker := time.NewTicker(checkInterval)
+        defer ticker.Stop()
+        failures := 0
+        for {
+            select {
+            case <-ctx.Done():
+                return
+            case <-ticker.C:
+            }
+            failures = c.monitorTick(ctx, failures)
+        }
+    }()
+
+    return func() {
+        cancel()
+        <-done
+    }
+}
+
+// 한 번 확인하고 다음 연속 실패 횟수를 반환함
+func (c *Conn) monitorTick(ctx context.Context, failures int) int {
+    if err := c.Check(ctx); err == nil {
+        return 0
+    }
+    if failures++; failures >= rebuildAfter {
+        c.rebuild()
+        return 0
+    }
+    return failures
+}
+
+func (c *Conn) rebuild() {
+    old := c.current.Swap(redis.NewClient(c.opts))
+    slog.Warn("Rebuilt Redis client after repeated ping failures", "failures", rebuildAfter)
+    // 진행 중인 요청이 이전 클라이언트를 쓰고 있을 수 있으므로 백엔드 타임아웃이 지난 뒤 닫음
+    time.AfterFunc(backend.Timeout, func() {
+        old.Close()
+    })
+}
+
+// 운영(ElastiCache 전송 중 암호화)에서는 기본값대로 TLS를 쓰고, 로컬 개발용 평문 Redis에는 REDIS_TLS=false로 끔.
+// REDIS_TLS_CA_FILE을 주면 시스템 루트 대신 그 CA로 서버 인증서를 검증함
+func TLSConfig() *tls.Config {
+    if v := os.Getenv("REDIS_TLS"); v != "" {
+        enabled, err := strconv.ParseBool(v)
+        if err != nil {
+            log.Fatalf("invalid REDIS_TLS %q (want true or false)", v)
+        }
+        if !enabled {
+            return nil
+        }
+    }
+
+    cfg := &tls.Config{}
+    if path := os.Getenv("REDIS_TLS_CA_FILE"); path != "" {
+        pem, err := os.ReadFile(path)
+        if err != nil {
+            log.Fatalf("failed to read REDIS_TLS_CA_FILE: %v", err)
+        }
+        pool := x509.NewCertPool()
+        if !pool.AppendCertsFromPEM(pem) {
+            log.Fatalf("no certificates found in REDIS_TLS_CA_FILE %q", path)
+        }
+        cfg.RootCAs = pool
+    }
+    return cfg
+}
+
+// 동시 요청이 많을 때 조정할 수 있도록 풀 크기와 타임아웃을 환경 변수로 받음.
+// 설정하지 않으면 go-redis 기본값(GOMAXPROCS당 10개, 연결 5s, 읽기 3s)을 씀
+func ApplyPoolOptions(opts *redis.Options) {
+    if v := os.Getenv("REDIS_POOL_SIZE"); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil || n <= 0 {
+            log.Fatalf("invalid REDIS_POOL_SIZE %q (want a positive integer)", v)
+        }
+        opts.PoolSize = n
+    }
+    opts.DialTimeout = durationEnv("REDIS_DIAL_TIMEOUT")
+    opts.ReadTimeout = durationEnv("REDIS_READ_TIMEOUT")
+}
+
+func durationEnv(name string) time.Duration {
+    v := os.Getenv(name)
+    if v == "" {
+        return 0
+    }
+    d, err := time.ParseDuration(v)
+    if err != nil || d <= 0 {
+        log.Fatalf("invalid %s %q (want a positive duration such as 500ms)", name, v)
+    }
+    return d
+}
@@ -0,0 +1,145 @@
+package redisconn
+
+import (
+    "context"
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "crypto/rand"
+    "crypto/x509"
+    "crypto/x509/pkix"
+    "encoding/pem"
+    "math/big"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+
+    "github.com/alicebob/miniredis/v2"
+    "github.com/go-redis/redis/v8"
+)
+
+// 연속 rebuildAfter번 실패하면 클라이언트를 새로 만들고, Redis가 돌아오면 다시 성공함
+func TestMonitorRebuildsAndRecovers(t *testing.T) {
+    mr := miniredis.RunT(t)
+    var conn Conn
+    conn.Connect(&redis.Options{Addr: mr.Addr()})
+    t.Cleanup(func() { conn.Client().Close() })
+    ctx := context.Background()
+
+    if failures := conn.monitorTick(ctx, 0); failures != 0 {
+        t.Fatalf("healthy tick: failures %d, want 0", failures)
+    }
+
+    mr.SetError("LOADING Redis is loading the dataset in memory")
+    original := conn.Client()
+    failures := 0
+    for i := 1; i < rebuildAfter; i++ {
+        if failures = conn.monitorTick(ctx, failures); failures != i {
+            t.Fatalf("tick %d: failures %d, want %d", i, failures, i)
+        }
+        if conn.Client() != original {
+            t.Fatalf("tick %d: client rebuilt before %d failures", i, rebuildAfter)
+        }
+    }
+    if failures = conn.monitorTick(ctx, failures); failures != 0 {
+        t.Errorf("tick %d: failures %d, want reset to 0", rebuildAfter, failures)
+    }
+    if conn.Client() == original {
+        t.Errorf("client not rebuilt after %d failures", rebuildAfter)
+    }
+
+    mr.SetError("")
+    if err := conn.Check(ctx); err != nil {
+        t.Errorf("after recovery: %v", err)
+    }
+    if !conn.connected.Load() {
+        t.Error("not marked connected after recovery")
+    }
+}
+
+// 시작할 때 Redis가 없어도 Connect는 클라이언트를 두고, 나중에 뜬 Redis에 같은 주소로 연결됨
+func TestConnectWhileRedisDown(t *testing.T) {
+    mr := miniredis.RunT(t)
+    addr := mr.Addr()
+    mr.Close()
+
+    var conn Conn
+    conn.Connect(&redis.Options{Addr: addr})
+    t.Cleanup(func() { conn.Client().Close() })
+    if conn.Client() == nil || conn.connected.Load() {
+        t.Fatalf("client %v connected %v, want a client that is not connected", conn.Client(), conn.connected.Load())
+    }
+
+    if err := mr.StartAddr(addr); err != nil {
+        t.Fatal(err)
+    }
+    if err := conn.Check(context.Background()); err != nil {
+        t.Errorf("after Redis started: %v", err)
+    }
+}
+
+func TestTLSConfig(t *testing.T) {
+    t.Setenv("REDIS_TLS", "")
+    t.Setenv("REDIS_TLS_CA_FILE", "")
+    if cfg := TLSConfig(); cfg == nil || cfg.RootCAs != nil {
+        t.Errorf("default = %+v, want TLS with system roots", cfg)
+    }
+
+    t.Setenv("REDIS_TLS", "false")
+    if cfg := TLSConfig(); cfg != nil {
+        t.Errorf("REDIS_TLS=false = %+v, want nil", cfg)
+    }
+
+    t.Setenv("REDIS_TLS", "true")
+    t.Setenv("REDIS_TLS_CA_FILE", writeTestCA(t))
+    if cfg := TLSConfig(); cfg == nil || cfg.RootCAs == nil {
+        t.Errorf("REDIS_TLS_CA_FILE = %+v, want TLS with the given CA", cfg)
+    }
+}
+
+// 자체 서명한 CA 인증서를 PEM 파일로 써서 경로를 반환함
+func writeTestCA(t *testing.T) string {
+    t.Helper()
+    key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+    template := &x509.Certificate{
+        SerialNumber:          big.NewInt(1),
+        Subject:               pkix.Name{CommonName: "test redis ca"},
+        NotBefore:             time.Now(),
+        NotAfter:              time.Now().Add(time.Hour),
+        IsCA:                  true,
+        BasicConstraintsValid: true,
+        KeyUsage:              x509.KeyUsageCertSign,
+    }
+    der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+    if err != nil {
+        t.Fatal(err)
+    }
+    path := filepath.Join(t.TempDir(), "ca.pem")
+    if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+        t.Fatal(err)
+    }
+    return path
+}
+
+func TestApplyPoolOptions(t *testing.T) {
+    t.Setenv("REDIS_POOL_SIZE", "")
+    t.Setenv("REDIS_DIAL_TIMEOUT", "")
+    t.Setenv("REDIS_READ_TIMEOUT", "")
+    opts := &redis.Options{}
+    ApplyPoolOptions(opts)
+    if opts.PoolSize != 0 || opts.DialTimeout != 0 || opts.ReadTimeout != 0 {
+        t.Errorf("unset = pool %d dial %v read %v, want go-redis defaults (zero)", opts.PoolSize, opts.DialTimeout, opts.ReadTimeout)
+    }
+
+    t.Setenv("REDIS_POOL_SIZE", "50")
+    t.Setenv("REDIS_DIAL_TIMEOUT", "250ms")
+    t.Setenv("REDIS_READ_TIMEOUT", "2s")
+    opts = &redis.Options{}
+    ApplyPoolOptions(opts)
+    if opts.PoolSize != 50 || opts.DialTimeout != 250*time.Millisecond || opts.ReadTimeout != 2*time.Second {
+        t.Errorf("set = pool %d dial %v read %v, want 50, 250ms, 2s", opts.PoolSize, opts.DialTimeout, opts.ReadTimeout)
+    }
+}
@@ -0,0 +1,43 @@
+// Package requestid는 요청마다 X-Request-ID를 붙이고 컨텍스트로 전달함. 로그, 오류 응답, 다른 서비스 호출이 같은 값을 씀
+package requestid
+
+import (
+    "context"
+
+    "github.com/gin-gonic/gin"
+    "github.com/google/uuid"
+)
+
+const (
+    Header = "X-Request-ID"
+    // 로그를 오염시키지 않도록 너무 긴 외부 값은 새 ID로 바꿈
+    maxLength = 128
+)
+
+type contextKey struct{}
+
+// 들어온 X-Request-ID를 그대로 쓰고 없으면 UUID를 만들어 요청 컨텍스트와 응답 헤더에 넣음
+func Middleware() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        id := c.GetHeader(Header)
+        if id == "" || len(id) > maxLength {
+            id = uuid.NewString()
+        }
+
+        c.Request = c.Request.WithContext(NewContext(c.Request.Context(), id))
+        c.Header(Header, id)
+        c.Next()
+    }
+}
+
+func NewContext(ctx context.Context, id string) context.Context {
+    return context.WithValue(ctx, contextKey{}, id)
+}
+
+func From(ctx context.Context) string {
+    if ctx == nil {
+        return ""
+    }
+    id, _ := ctx.Value(contextKey{}).(string)
+    return id
+}
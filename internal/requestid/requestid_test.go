@@ -0,0 +1,56 @@
+package requestid
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/gin-gonic/gin"
+)
+
+// 받은 ID는 응답 헤더와 핸들러 컨텍스트에 그대로 돌아오고, 없거나 너무 길면 새로 만듦
+func TestMiddlewareRoundTrip(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    var seen string
+    router := gin.New()
+    router.Use(Middleware())
+    router.GET("/v1/item", func(c *gin.Context) {
+        seen = From(c.Request.Context())
+        c.Status(http.StatusOK)
+    })
+
+    tests := []struct {
+        name, sent string
+        keep       bool
+    }{
+        {"given", "req-123", true},
+        {"missing", "", false},
+        {"too long", strings.Repeat("a", maxLength+1), false},
+    }
+    for _, tt := range tests {
+        req := httptest.NewRequest(http.MethodGet, "/v1/item", nil)
+        if tt.sent != "" {
+            req.Header.Set(Header, tt.sent)
+        }
+        w := httptest.NewRecorder()
+        router.ServeHTTP(w, req)
+
+        got := w.Header().Get(Header)
+        if got == "" || got != seen {
+            t.Errorf("%s: header %q, context %q, want the same non-empty ID", tt.name, got, seen)
+        }
+        if (got == tt.sent) != tt.keep {
+            t.Errorf("%s: header %q, sent %q, keep %v", tt.name, got, tt.sent, tt.keep)
+        }
+    }
+}
+
+func TestFromWithoutID(t *testing.T) {
+    if id := From(nil); id != "" {
+        t.Errorf("From(nil) = %q, want empty", id)
+    }
+    if id := From(NewContext(t.Context(), "r1")); id != "r1" {
+        t.Errorf("From = %q, want r1", id)
+    }
+}
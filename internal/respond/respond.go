@@ -0,0 +1,80 @@
+// Package respond는 세 서비스가 같은 형식으로 JSON 응답과 오류 응답을 쓰도록 함.
+// 오류 응답을 포함한 모든 JSON 응답은 JSON_KEY_STYLE(current, camel, snake)에 따라 키 표기를 바꿔 나감
+package respond
+
+import (
+    "log"
+    "net/http"
+    "os"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/keystyle"
+    "github.com/gmstcl/eCommerce-System/internal/requestid"
+)
+
+// 오류 응답의 code 값. 클라이언트는 message 대신 code로 분기함
+const (
+    CodeInvalidRequest     = "invalid_request"
+    CodeUnauthorized       = "unauthorized"
+    CodeForbidden          = "forbidden"
+    CodeNotFound           = "not_found"
+    CodeConflict           = "conflict"
+    CodePreconditionFailed = "precondition_failed"
+    CodeUnprocessable      = "unprocessable"
+    CodePayloadTooLarge    = "payload_too_large"
+    CodeRateLimited        = "rate_limited"
+    CodeUnavailable        = "unavailable"
+    CodeInternal           = "internal_error"
+    CodeTimeout            = "backend_timeout"
+    CodeNotImplemented     = "not_implemented"
+)
+
+// 시작할 때 InitKeyStyle이 정하고 테스트에서는 직접 바꿈
+var KeyStyle = keystyle.Current
+
+func InitKeyStyle() {
+    style, err := keystyle.Parse(os.Getenv("JSON_KEY_STYLE"))
+    if err != nil {
+        log.Fatal(err)
+    }
+    KeyStyle = style
+}
+
+func JSON(c *gin.Context, status int, obj interface{}) {
+    c.JSON(status, keystyle.Apply(KeyStyle, obj))
+}
+
+// 모든 핸들러와 미들웨어의 오류 응답 형식. details에는 빠진 필드 목록처럼 오류별 추가 정보를 담음
+type errorResponse struct {
+    Code      string      `json:"code"`
+    Message   string      `json:"message"`
+    RequestID string      `json:"request_id"`
+    Details   interface{} `json:"details,omitempty"`
+}
+
+func Error(c *gin.Context, status int, code, message string) {
+    ErrorDetails(c, status, code, message, nil)
+}
+
+func ErrorDetails(c *gin.Context, status int, code, message string, details interface{}) {
+    c.AbortWithStatusJSON(status, keystyle.Apply(KeyStyle, errorResponse{
+        Code:      code,
+        Message:   message,
+        RequestID: requestid.From(c.Request.Context()),
+        Details:   details,
+    }))
+}
+
+// 백엔드 오류의 상태 코드는 backend.Status가 정하고 code는 그에 맞춰 고름
+func BackendError(c *gin.Context, err error, message string) {
+    status := backend.Status(err)
+    code := CodeInternal
+    switch status {
+    case http.StatusGatewayTimeout:
+        code = CodeTimeout
+    case http.StatusServiceUnavailable:
+        code = CodeUnavailable
+    }
+    Error(c, status, code, message)
+}
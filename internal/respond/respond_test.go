@@ -0,0 +1,84 @@
+package respond
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/requestid"
+)
+
+// handler 앞에 requestid 미들웨어를 두고 X-Request-ID: r1로 한 번 호출함. 뒤에 등록한 핸들러가 불렸는지도 반환함
+func serve(handler gin.HandlerFunc) (*httptest.ResponseRecorder, bool) {
+    gin.SetMode(gin.TestMode)
+    next := false
+    router := gin.New()
+    router.GET("/", requestid.Middleware(), handler, func(*gin.Context) { next = true })
+    req := httptest.NewRequest(http.MethodGet, "/", nil)
+    req.Header.Set(requestid.Header, "r1")
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+    return w, next
+}
+
+func decode(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+    t.Helper()
+    var body map[string]interface{}
+    if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+        t.Fatalf("body %s: %v", w.Body, err)
+    }
+    return body
+}
+
+// code, message, request_id는 항상 있고 details는 있을 때만 나가며, 이후 핸들러는 실행하지 않음
+func TestErrorEnvelope(t *testing.T) {
+    w, next := serve(func(c *gin.Context) {
+        ErrorDetails(c, http.StatusBadRequest, CodeInvalidRequest, "bad field", gin.H{"fields": []string{"name"}})
+    })
+    body := decode(t, w)
+    if w.Code != http.StatusBadRequest || body["code"] != CodeInvalidRequest || body["message"] != "bad field" || body["request_id"] != "r1" {
+        t.Errorf("got %d %v, want 400 with code, message and request_id r1", w.Code, body)
+    }
+    if details, _ := body["details"].(map[string]interface{}); fmt.Sprint(details["fields"]) != "[name]" {
+        t.Errorf("details %v, want fields [name]", body["details"])
+    }
+    if next {
+        t.Error("handler after the error ran")
+    }
+
+    w, _ = serve(func(c *gin.Context) {
+        Error(c, http.StatusNotFound, CodeNotFound, "missing")
+    })
+    body = decode(t, w)
+    if _, ok := body["details"]; ok || len(body) != 3 {
+        t.Errorf("got %v, want only code, message and request_id", body)
+    }
+}
+
+func TestBackendError(t *testing.T) {
+    tests := []struct {
+        name   string
+        err    error
+        status int
+        code   string
+    }{
+        {"timeout", fmt.Errorf("query: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, CodeTimeout},
+        {"unavailable", fmt.Errorf("breaker open: %w", backend.ErrUnavailable), http.StatusServiceUnavailable, CodeUnavailable},
+        {"other", errors.New("boom"), http.StatusInternalServerError, CodeInternal},
+    }
+    for _, tt := range tests {
+        w, _ := serve(func(c *gin.Context) {
+            BackendError(c, tt.err, "failed")
+        })
+        body := decode(t, w)
+        if w.Code != tt.status || body["code"] != tt.code || body["message"] != "failed" {
+            t.Errorf("%s: got %d %v, want %d %s", tt.name, w.Code, body, tt.status, tt.code)
+        }
+    }
+}
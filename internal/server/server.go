@@ -0,0 +1,67 @@
+// Package server는 세 서비스가 같은 방식으로 시작하고 종료하도록 하는 HTTP 서버 실행 함수임
+package server
+
+import (
+    "context"
+    "errors"
+    "log"
+    "net/http"
+    "os"
+    "os/signal"
+    "strconv"
+    "syscall"
+    "time"
+)
+
+const (
+    addr                   = ":8080"
+    defaultShutdownTimeout = 15 * time.Second
+)
+
+// SHUTDOWN_TIMEOUT_SECONDS가 잘못된 값이면 종료함
+func shutdownTimeout() time.Duration {
+    v := os.Getenv("SHUTDOWN_TIMEOUT_SECONDS")
+    if v == "" {
+        return defaultShutdownTimeout
+    }
+    seconds, err := strconv.Atoi(v)
+    if err != nil || seconds <= 0 {
+        log.Fatalf("invalid SHUTDOWN_TIMEOUT_SECONDS %q", v)
+    }
+    return time.Duration(seconds) * time.Second
+}
+
+// SIGINT/SIGTERM을 받으면 처리 중인 요청을 기다린 뒤 종료하고, 마지막에 cleanup으로 연결을 닫음
+func Run(handler http.Handler, cleanup func()) {
+    timeout := shutdownTimeout()
+    server := &http.Server{
+        Addr:    addr,
+        Handler: handler,
+    }
+
+    go func() {
+        if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+            log.Fatalf("server error: %v", err)
+        }
+    }()
+
+    quit := make(chan os.Signal, 1)
+    signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+    sig := <-quit
+    log.Printf("Received %s, shutting down (timeout %s)", sig, timeout)
+
+    shutdown(server, timeout, cleanup)
+    log.Println("Server stopped")
+}
+
+// timeout 안에 끝나지 않은 요청은 연결을 끊음. cleanup은 어느 경우든 마지막에 호출함
+func shutdown(server *http.Server, timeout time.Duration, cleanup func()) {
+    ctx, cancel := context.WithTimeout(context.Background(), timeout)
+    defer cancel()
+
+    if err := server.Shutdown(ctx); err != nil {
+        log.Printf("Graceful shutdown did not finish in %s, closing remaining connections: %v", timeout, err)
+        server.Close()
+    }
+    cleanup()
+}
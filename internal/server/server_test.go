@@ -0,0 +1,82 @@
+package server
+
+import (
+    "errors"
+    "io"
+    "log"
+    "net"
+    "net/http"
+    "testing"
+    "time"
+)
+
+func init() {
+    log.SetOutput(io.Discard)
+}
+
+// handler가 release를 기다리는 서버를 띄우고 요청 하나가 handler에 들어갈 때까지 기다림.
+// 요청의 결과는 done으로 받음
+func startSlowRequest(t *testing.T, release <-chan struct{}) (*http.Server, <-chan error) {
+    t.Helper()
+    ln, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    entered := make(chan struct{})
+    server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        close(entered)
+        <-release
+        w.WriteHeader(http.StatusOK)
+    })}
+    go server.Serve(ln)
+
+    done := make(chan error, 1)
+    go func() {
+        resp, err := http.Get("http://" + ln.Addr().String())
+        if err == nil {
+            resp.Body.Close()
+            if resp.StatusCode != http.StatusOK {
+                err = errors.New(resp.Status)
+            }
+        }
+        done <- err
+    }()
+    <-entered
+    return server, done
+}
+
+// 처리 중인 요청이 끝난 뒤 cleanup을 호출함
+func TestShutdownWaitsForInFlightRequests(t *testing.T) {
+    release := make(chan struct{})
+    server, done := startSlowRequest(t, release)
+
+    cleaned := make(chan struct{})
+    go shutdown(server, 5*time.Second, func() { close(cleaned) })
+
+    select {
+    case <-cleaned:
+        t.Fatal("cleanup ran before the in-flight request finished")
+    case <-time.After(50 * time.Millisecond):
+    }
+    close(release)
+    if err := <-done; err != nil {
+        t.Errorf("in-flight request: %v", err)
+    }
+    <-cleaned
+}
+
+// 시간 안에 끝나지 않는 요청은 끊고 cleanup을 호출함
+func TestShutdownTimeoutClosesConnections(t *testing.T) {
+    release := make(chan struct{})
+    defer close(release)
+    server, done := startSlowRequest(t, release)
+
+    cleaned := false
+    shutdown(server, 20*time.Millisecond, func() { cleaned = true })
+    if !cleaned {
+        t.Error("cleanup not called after timeout")
+    }
+    if err := <-done; err == nil {
+        t.Error("request succeeded, want the connection to be closed")
+    }
+}
@@ -0,0 +1,136 @@
+// Package sqlq는 customer, product 서비스가 MySQL 쿼리를 만드는 유일한 경로임.
+// SQL 조각은 sqlFragment로만 받는데, 이 타입은 내보내지 않으므로 다른 패키지에서는 타입 없는 문자열 상수만
+// 넘길 수 있고 string 변수나 fmt.Sprintf 결과는 컴파일되지 않음. 요청에서 온 값은 모두 인자로 넘김.
+// 예외는 migrations/의 .sql 파일 내용뿐이며 그것도 코드와 함께 배포되는 파일임
+package sqlq
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "strings"
+
+    "github.com/jmoiron/sqlx"
+)
+
+type sqlFragment string
+
+// 인자 수가 placeholder 수와 다르거나 한 조각에 문장이 둘 이상이면 Build가 반환하는 오류
+type FragmentError struct {
+    Fragment string
+    Reason   string
+}
+
+func (e *FragmentError) Error() string {
+    return fmt.Sprintf("sqlq: fragment %q: %s", e.Fragment, e.Reason)
+}
+
+type Query struct {
+    sql  strings.Builder
+    args []interface{}
+    err  error
+}
+
+func New(fragment sqlFragment, args ...interface{}) *Query {
+    return new(Query).Add(fragment, args...)
+}
+
+// 첫 오류 이후의 조각은 무시하고 Build에서 그 오류를 반환함
+func (q *Query) Add(fragment sqlFragment, args ...interface{}) *Query {
+    if q.err != nil {
+        return q
+    }
+    n, err := countPlaceholders(string(fragment))
+    if err != nil {
+        q.err = err
+        return q
+    }
+    if n != len(args) {
+        q.err = &FragmentError{Fragment: string(fragment), Reason: fmt.Sprintf("%d placeholders but %d args", n, len(args))}
+        return q
+    }
+    q.sql.WriteString(string(fragment))
+    q.args = append(q.args, args...)
+    return q
+}
+
+// column IN (?, ?, ...)를 붙임. values가 비어 있으면 아무 행과도 맞지 않는 IN (NULL)이 됨
+func (q *Query) AddIn(column sqlFragment, values []string) *Query {
+    if len(values) == 0 {
+        return q.Add(column + " IN (NULL)")
+    }
+    args := make([]interface{}, len(values))
+    for i, v := range values {
+        args[i] = v
+    }
+    return q.Add(column+" IN (?"+sqlFragment(strings.Repeat(", ?", len(values)-1))+")", args...)
+}
+
+func (q *Query) Build() (string, []interface{}, error) {
+    if q.err != nil {
+        return "", nil, q.err
+    }
+    return q.sql.String(), q.args, nil
+}
+
+// db, tx, conn 어느 쪽이든 받음. 쿼리를 만들지 못했으면 실행하지 않고 그 오류를 반환함
+func (q *Query) ExecContext(ctx context.Context, e sqlx.ExecerContext) (sql.Result, error) {
+    query, args, err := q.Build()
+    if err != nil {
+        return nil, err
+    }
+    return e.ExecContext(ctx, query, args...)
+}
+
+func (q *Query) SelectContext(ctx context.Context, e sqlx.QueryerContext, dest interface{}) error {
+    query, args, err := q.Build()
+    if err != nil {
+        return err
+    }
+    return sqlx.SelectContext(ctx, e, dest, query, args...)
+}
+
+func (q *Query) GetContext(ctx context.Context, e sqlx.QueryerContext, dest interface{}) error {
+    query, args, err := q.Build()
+    if err != nil {
+        return err
+    }
+    return sqlx.GetContext(ctx, e, dest, query, args...)
+}
+
+// 인자 없이 준비된 문장(Preparex)으로 쓸 SQL. 같은 검사를 거치며 placeholder는 실행할 때 채움
+func Statement(fragment sqlFragment) (string, error) {
+    if _, err := countPlaceholders(string(fragment)); err != nil {
+        return "", err
+    }
+    return string(fragment), nil
+}
+
+// 작은따옴표 리터럴 안의 ?는 세지 않음. 리터럴 밖의 ;는 문장을 이어 붙인 것으로 보고 거부함
+func countPlaceholders(fragment string) (int, error) {
+    n := 0
+    quoted := false
+    for _, r := range fragment {
+        switch {
+        case r == '\'':
+            quoted = !quoted
+        case quoted:
+        case r == '?':
+            n++
+        case r == ';':
+            return 0, &FragmentError{Fragment: fragment, Reason: "contains a statement separator"}
+        }
+    }
+    if quoted {
+        return 0, &FragmentError{Fragment: fragment, Reason: "has an unterminated string literal"}
+    }
+    return n, nil
+}
+
+// 백슬래시 해석이 sql_mode(NO_BACKSLASH_ESCAPES)에 따라 달라지므로 이스케이프 문자로 !를 씀
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// LIKE ? ESCAPE '!'에 넘길 부분 일치 패턴. s의 %와 _는 와일드카드가 아닌 문자로 취급함
+func LikeContains(s string) string {
+    return "%" + likeEscaper.Replace(s) + "%"
+}
@@ -0,0 +1,137 @@
+package sqlq
+
+import (
+    "errors"
+    "go/ast"
+    "go/importer"
+    "go/parser"
+    "go/token"
+    "go/types"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+// 흔한 주입 문자열. 인자로 넘기면 SQL 문자열에는 하나도 들어가지 않아야 함
+var injectionPayloads = []string{
+    "' OR '1'='1",
+    "x'; DROP TABLE customers; --",
+    "1 OR 1=1",
+    `\' OR 1=1 #`,
+    "?",
+    "%' UNION SELECT password FROM users --",
+}
+
+func TestArgsNeverReachSQL(t *testing.T) {
+    for _, payload := range injectionPayloads {
+        query, args, err := New("SELECT id FROM customers WHERE id > ? ORDER BY id LIMIT ?", payload, 10).Build()
+        if err != nil {
+            t.Fatalf("%q: %v", payload, err)
+        }
+        if query != "SELECT id FROM customers WHERE id > ? ORDER BY id LIMIT ?" {
+            t.Errorf("%q: query changed to %q", payload, query)
+        }
+        if !reflect.DeepEqual(args, []interface{}{payload, 10}) {
+            t.Errorf("%q: args %v", payload, args)
+        }
+    }
+}
+
+func TestAddInKeepsValuesOutOfSQL(t *testing.T) {
+    query, args, err := New("SELECT id FROM product WHERE ").AddIn("LOWER(TRIM(id))", injectionPayloads).Build()
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := "SELECT id FROM product WHERE LOWER(TRIM(id)) IN (?" + strings.Repeat(", ?", len(injectionPayloads)-1) + ")"
+    if query != want {
+        t.Errorf("query %q, want %q", query, want)
+    }
+    if len(args) != len(injectionPayloads) {
+        t.Errorf("%d args, want %d", len(args), len(injectionPayloads))
+    }
+
+    query, args, err = New("SELECT id FROM product WHERE ").AddIn("id", nil).Build()
+    if err != nil || query != "SELECT id FROM product WHERE id IN (NULL)" || len(args) != 0 {
+        t.Errorf("empty IN: %q %v %v", query, args, err)
+    }
+}
+
+func TestRejectsMalformedFragments(t *testing.T) {
+    tests := []struct {
+        name string
+        q    *Query
+    }{
+        {"too few args", New("SELECT id FROM customers WHERE id = ? AND name = ?", "a")},
+        {"too many args", New("SELECT id FROM customers WHERE id = 'a'", "a")},
+        {"stacked statement", New("SELECT 1; DROP TABLE customers")},
+        {"unterminated literal", New("SELECT id FROM product WHERE name = 'x")},
+        {"error in a later fragment", New("SELECT id FROM product WHERE id = ?", "a").Add(" LIMIT ?")},
+    }
+    for _, tt := range tests {
+        query, args, err := tt.q.Build()
+        var fragErr *FragmentError
+        if !errors.As(err, &fragErr) {
+            t.Errorf("%s: err %v, want *FragmentError", tt.name, err)
+        }
+        if query != "" || args != nil {
+            t.Errorf("%s: returned %q %v with an error", tt.name, query, args)
+        }
+    }
+}
+
+func TestPlaceholderInsideLiteralIsNotCounted(t *testing.T) {
+    query, args, err := New("SELECT id FROM product WHERE name LIKE ? ESCAPE '!' AND note <> '?'", "%a%").Build()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(args) != 1 || !strings.HasSuffix(query, "note <> '?'") {
+        t.Errorf("%q %v", query, args)
+    }
+}
+
+func TestStatementChecksFragment(t *testing.T) {
+    if _, err := Statement("SELECT id FROM customers WHERE id = ?"); err != nil {
+        t.Errorf("valid statement: %v", err)
+    }
+    if _, err := Statement("DELETE FROM customers; DROP TABLE customers"); err == nil {
+        t.Error("stacked statement accepted")
+    }
+}
+
+func TestLikeContainsEscapesWildcards(t *testing.T) {
+    if got := LikeContains("50%_off!"); got != "%50!%!_off!!%" {
+        t.Errorf("LikeContains = %q", got)
+    }
+}
+
+// 다른 패키지에서 string 변수나 Sprintf 결과를 조각으로 넘기면 컴파일되지 않아야 함
+func TestOnlyConstantsCompile(t *testing.T) {
+    tests := []struct {
+        name    string
+        body    string
+        compile bool
+    }{
+        {"constant", `sqlq.New("SELECT id FROM customers WHERE id = ?", id)`, true},
+        {"named constant", `const q = "SELECT id FROM customers"; sqlq.New(q)`, true},
+        {"string variable", `q := "SELECT id FROM customers WHERE id = '" + id + "'"; sqlq.New(q)`, false},
+        {"sprintf", `sqlq.New(fmt.Sprintf("SELECT id FROM customers WHERE id = '%s'", id))`, false},
+        {"concatenated value", `sqlq.New("SELECT id FROM customers WHERE id = '" + id + "'")`, false},
+        {"add variable", `q := " LIMIT " + id; sqlq.New("SELECT id FROM customers").Add(q)`, false},
+        {"in column variable", `sqlq.New("SELECT id FROM customers WHERE ").AddIn(id, nil)`, false},
+    }
+
+    fset := token.NewFileSet()
+    imp := importer.ForCompiler(fset, "source", nil)
+    for _, tt := range tests {
+        src := "package caller\n\nimport (\n\t\"fmt\"\n\t\"github.com/gmstcl/eCommerce-System/internal/sqlq\"\n)\n\nvar _ = fmt.Sprint\n\nfunc f(id string) {\n\t" + tt.body + "\n}\n"
+        file, err := parser.ParseFile(fset, "caller.go", src, 0)
+        if err != nil {
+            t.Fatalf("%s: %v", tt.name, err)
+        }
+        conf := types.Config{Importer: imp}
+        _, err = conf.Check("caller", fset, []*ast.File{file}, nil)
+        if compiled := err == nil; compiled != tt.compile {
+            t.Errorf("%s: compiled %v, want %v (%v)", tt.name, compiled, tt.compile, err)
+        }
+    }
+}
@@ -0,0 +1,55 @@
+// Package tracing은 세 서비스의 OpenTelemetry 설정임. 전역 트레이서는 Init에서 프로바이더를 설정하기 전까지 no-op임
+package tracing
+
+import (
+    "context"
+    "log"
+    "log/slog"
+    "os"
+    "time"
+
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+    "go.opentelemetry.io/otel/propagation"
+    "go.opentelemetry.io/otel/sdk/resource"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/gmstcl/eCommerce-System"
+
+// OTEL_EXPORTER_OTLP_ENDPOINT(또는 OTEL_EXPORTER_OTLP_TRACES_ENDPOINT)가 있으면 OTLP/HTTP로 스팬을 내보냄.
+// 엔드포인트 외의 설정(헤더, 샘플링 등)도 표준 OTEL_* 환경 변수를 그대로 따름.
+// 내보내기를 끄더라도 traceparent 헤더는 이어받아 다음 서비스로 전달함
+func Init(service string, logger *slog.Logger) func() {
+    otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+    if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+        return func() {}
+    }
+
+    exporter, err := otlptracehttp.New(context.Background())
+    if err != nil {
+        log.Fatalf("failed to create OTLP trace exporter: %v", err)
+    }
+    provider := sdktrace.NewTracerProvider(
+        sdktrace.WithBatcher(exporter),
+        sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", service))),
+    )
+    otel.SetTracerProvider(provider)
+    logger.Info("Tracing enabled")
+
+    return func() {
+        ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+        defer cancel()
+        if err := provider.Shutdown(ctx); err != nil {
+            logger.Error("Failed to flush traces", "error", err)
+        }
+    }
+}
+
+// 스팬 이름은 백엔드 헬퍼의 함수 이름을 그대로 씀. 서비스 구분은 프로바이더의 service.name으로 함
+func Start(ctx context.Context, name, idKey, id string) (context.Context, trace.Span) {
+    return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attribute.String(idKey, id)))
+}
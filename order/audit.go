@@ -0,0 +1,189 @@
+package main
+
+import (
+    "context"
+    "log"
+    "net/http"
+    "os"
+    "strconv"
+    "time"
+
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/apikey"
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/chaos"
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+    "github.com/gmstcl/eCommerce-System/internal/jwtauth"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+)
+
+// 주문 변경 이력은 별도 테이블에 남김. 파티션 키 order_id(S), 정렬 키 ts(N, UnixNano)
+const (
+    auditCreate = "create"
+    auditUpdate = "update"
+    auditDelete = "delete"
+)
+
+var orderAuditTable = "order_audit"
+
+type auditEntry struct {
+    OrderID   string    `json:"order_id"`
+    Action    string    `json:"action"`
+    Actor     string    `json:"actor"`
+    Timestamp time.Time `json:"timestamp"`
+}
+
+func initOrderAudit() {
+    if v, ok := os.LookupEnv("ORDER_AUDIT_TABLE_NAME"); ok {
+        if v == "" {
+            log.Fatalf("ORDER_AUDIT_TABLE_NAME is set but empty")
+        }
+        orderAuditTable = v
+    }
+}
+
+// JWT로 인증한 요청은 토큰의 sub, API 키로 인증한 요청은 키 해시로 구분함. 인증을 끈 환경에서는 anonymous
+func auditActor(c *gin.Context) string {
+    if subject := jwtauth.Subject(c); subject != "" {
+        return "sub:" + subject
+    }
+    if id := apikey.ID(c); id != "" {
+        return "key:" + id
+    }
+    return "anonymous"
+}
+
+func auditItem(entry auditEntry) map[string]types.AttributeValue {
+    return map[string]types.AttributeValue{
+        "order_id": &types.AttributeValueMemberS{Value: entry.OrderID},
+        "ts":       &types.AttributeValueMemberN{Value: strconv.FormatInt(entry.Timestamp.UnixNano(), 10)},
+        "action":   &types.AttributeValueMemberS{Value: entry.Action},
+        "actor":    &types.AttributeValueMemberS{Value: entry.Actor},
+    }
+}
+
+// 이력 기록 실패로 이미 반영된 변경을 되돌릴 수는 없으므로 로그만 남김
+func recordOrderAudit(ctx context.Context, c *gin.Context, orderID, action string) {
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    entry := auditEntry{OrderID: orderID, Action: action, Actor: auditActor(c), Timestamp: clock.Now()}
+    _, err := dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+        TableName: aws.String(orderAuditTable),
+        Item:      auditItem(entry),
+    })
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to write order audit entry", "order_id", orderID, "action", action, "error", err)
+    }
+}
+
+// 배치 생성용. 25건씩 나눠 쓰고 UnprocessedItems는 다시 보냄. 재시도 후에도 남은 항목은 로그로만 남김
+func recordOrderAudits(ctx context.Context, c *gin.Context, orderIDs []string, action string) {
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    actor := auditActor(c)
+    now := clock.Now()
+    for start := 0; start < len(orderIDs); start += dynamoBatchSize {
+        end := start + dynamoBatchSize
+        if end > len(orderIDs) {
+            end = len(orderIDs)
+        }
+
+        requests := make([]types.WriteRequest, 0, end-start)
+        for _, id := range orderIDs[start:end] {
+            entry := auditEntry{OrderID: id, Action: action, Actor: actor, Timestamp: now}
+            requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: auditItem(entry)}})
+        }
+
+        unprocessed, err := batchWriteWithRetry(ctx, map[string][]types.WriteRequest{orderAuditTable: requests})
+        if err != nil {
+            logger.ErrorContext(ctx, "Failed to write order audit entries", "count", len(requests), "action", action, "error", err)
+            continue
+        }
+        if n := len(unprocessed[orderAuditTable]); n > 0 {
+            logger.ErrorContext(ctx, "Order audit entries left unprocessed after retries", "count", n, "action", action, "retries", dynamoMaxRetries)
+        }
+    }
+}
+
+func getOrderHistory(c *gin.Context) {
+    ctx := c.Request.Context()
+    orderID := c.Param("id")
+
+    // 소유자 확인에 CustomerID가 필요함. 삭제된 주문의 이력은 관리자 토큰이나 API 키로만 볼 수 있음
+    if jwtauth.Subject(c) != "" && !jwtauth.HasScope(c, jwtAdminScope) {
+        orderData, err := loadOrder(ctx, orderID)
+        if err != nil {
+            logger.ErrorContext(ctx, "Failed to fetch order", "order_id", orderID, "error", err)
+            respond.BackendError(c, err, "failed to fetch order")
+            return
+        }
+        if orderData == nil {
+            respond.Error(c, http.StatusNotFound, respond.CodeNotFound, "order not found")
+            return
+        }
+        if !authorizeOrderRead(c, orderData) {
+            return
+        }
+    }
+
+    entries, err := getOrderAuditEntries(ctx, orderID)
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to fetch order history", "order_id", orderID, "error", err)
+        respond.BackendError(c, err, "failed to fetch order history")
+        return
+    }
+
+    respond.JSON(c, http.StatusOK, entries)
+}
+
+// 정렬 키 순서(오래된 것부터)로 반환함
+func getOrderAuditEntries(ctx context.Context, orderID string) ([]auditEntry, error) {
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosDBFailRate); err != nil {
+        return nil, err
+    }
+
+    input := &dynamodb.QueryInput{
+        TableName:              aws.String(orderAuditTable),
+        KeyConditionExpression: aws.String("order_id = :order_id"),
+        ExpressionAttributeValues: map[string]types.AttributeValue{
+            ":order_id": &types.AttributeValueMemberS{Value: orderID},
+        },
+        ScanIndexForward: aws.Bool(true),
+    }
+
+    entries := []auditEntry{}
+    for {
+        result, err := dynamoClient.Query(ctx, input)
+        if err != nil {
+            return nil, err
+        }
+
+        for _, item := range result.Items {
+            entry := auditEntry{OrderID: orderID}
+            if action, ok := item["action"].(*types.AttributeValueMemberS); ok {
+                entry.Action = action.Value
+            }
+            if actor, ok := item["actor"].(*types.AttributeValueMemberS); ok {
+                entry.Actor = actor.Value
+            }
+            if ts, ok := item["ts"].(*types.AttributeValueMemberN); ok {
+                nanos, _ := strconv.ParseInt(ts.Value, 10, 64)
+                entry.Timestamp = time.Unix(0, nanos).UTC()
+            }
+            entries = append(entries, entry)
+        }
+
+        if len(result.LastEvaluatedKey) == 0 {
+            return entries, nil
+        }
+        input.ExclusiveStartKey = result.LastEvaluatedKey
+    }
+}
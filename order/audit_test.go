@@ -0,0 +1,65 @@
+package main
+
+import (
+    "net/http"
+    "testing"
+)
+
+func auditPutItems(fake *fakeDynamo) []map[string]interface{} {
+    var items []map[string]interface{}
+    for _, call := range fake.callsTo("PutItem") {
+        if call.Body["TableName"] == orderAuditTable {
+            items = append(items, call.Body)
+        }
+    }
+    return items
+}
+
+func TestCreateOrderWritesAuditEntry(t *testing.T) {
+    fake := newFakeDynamo(t, nil)
+
+    body := orderRequest()
+    body["id"] = "o1"
+    if w := doRequest(newRouter(), http.MethodPost, "/v1/order", body, nil); w.Code != http.StatusCreated {
+        t.Fatalf("status %d, want 201 (%s)", w.Code, w.Body)
+    }
+
+    puts := auditPutItems(fake)
+    if len(puts) != 1 {
+        t.Fatalf("audit PutItem called %d times, want 1", len(puts))
+    }
+    if id, action := attrS(puts[0], "Item", "order_id"), attrS(puts[0], "Item", "action"); id != "o1" || action != auditCreate {
+        t.Errorf("audit entry order_id=%q action=%q, want o1 %s", id, action, auditCreate)
+    }
+}
+
+// 스로틀링으로 UnprocessedItems에 남은 감사 기록은 버리지 않고 다시 보냄
+func TestBatchAuditRetriesUnprocessedItems(t *testing.T) {
+    useFastBatchRetry(t)
+    auditWrites := 0
+    fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        if op != "BatchWriteItem" || len(batchPutItems(body, orderAuditTable)) == 0 {
+            return http.StatusOK, map[string]interface{}{}
+        }
+        auditWrites++
+        if auditWrites == 1 {
+            return unprocessedResponse(body, orderAuditTable, 2)
+        }
+        return http.StatusOK, map[string]interface{}{}
+    })
+
+    w := doRequest(newRouter(), http.MethodPost, "/v1/orders/batch", batchOrders(3), nil)
+    if w.Code != http.StatusMultiStatus {
+        t.Fatalf("status %d, want 207 (%s)", w.Code, w.Body)
+    }
+
+    var sizes []int
+    for _, call := range fake.callsTo("BatchWriteItem") {
+        if n := len(batchPutItems(call.Body, orderAuditTable)); n > 0 {
+            sizes = append(sizes, n)
+        }
+    }
+    if len(sizes) != 2 || sizes[0] != 3 || sizes[1] != 2 {
+        t.Errorf("audit BatchWriteItem sizes %v, want [3 2]", sizes)
+    }
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net/http"
+    "strings"
+    "time"
+
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/bodylimit"
+    "github.com/gmstcl/eCommerce-System/internal/chaos"
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+    "github.com/gmstcl/eCommerce-System/internal/metrics"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+)
+
+const (
+    maxOrderBatch = 1000
+    // BatchWriteItem 한 번에 보낼 수 있는 최대 항목 수
+    dynamoBatchSize = 25
+)
+
+type batchItemResult struct {
+    Index  int    `json:"index"`
+    ID     string `json:"id"`
+    Status int    `json:"status"`
+    Error  string `json:"error,omitempty"`
+}
+
+// 재시도 후에도 UnprocessedItems로 남아 쓰지 못한 주문
+var errOrderUnprocessed = errors.New("order was not processed")
+
+// 이미 있는 id는 덮어쓰지 않고 항목별로 409를 반환함
+func createOrdersBatch(c *gin.Context) {
+    ctx := c.Request.Context()
+    // 한 건의 오류로 전체가 거부되지 않도록 바인딩 검증 대신 항목별로 검사함
+    var orders []Order
+    if err := json.NewDecoder(c.Request.Body).Decode(&orders); err != nil {
+        if bodylimit.TooLarge(c, err) {
+            return
+        }
+        metrics.RecordValidationFailure(c, err)
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, err.Error())
+        return
+    }
+
+    if len(orders) == 0 || len(orders) > maxOrderBatch {
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, fmt.Sprintf("batch must contain between 1 and %d orders", maxOrderBatch))
+        return
+    }
+
+    results := make([]batchItemResult, len(orders))
+    var valid []int
+    seen := make(map[string]bool)
+    for i := range orders {
+        order := &orders[i]
+        results[i] = batchItemResult{Index: i, ID: order.ID}
+
+        if msg := validateBatchOrder(c, order); msg != "" {
+            results[i].Status = http.StatusBadRequest
+            results[i].Error = msg
+            continue
+        }
+        if field, err := checkOrderAmounts(order); err != nil {
+            metrics.RecordValidationField(c, field)
+            results[i].Status = http.StatusUnprocessableEntity
+            results[i].Error = err.Error()
+            continue
+        }
+
+        if orderIDStrategy != orderIDClient {
+            id, err := newOrderID()
+            if err != nil {
+                logger.ErrorContext(ctx, "Failed to generate order id", "error", err)
+                results[i].Status = http.StatusInternalServerError
+                results[i].Error = "failed to generate order id"
+                continue
+            }
+            order.ID = id
+            results[i].ID = id
+        }
+
+        // 같은 요청 안에 중복 키가 있으면 BatchWriteItem 전체가 거부됨
+        if seen[order.ID] {
+            results[i].Status = http.StatusBadRequest
+            results[i].Error = "duplicate id in batch"
+            continue
+        }
+        seen[order.ID] = true
+        valid = append(valid, i)
+    }
+
+    var created []string
+    record := func(i int, err error) {
+        switch {
+        case errors.Is(err, errOrderExists):
+            results[i].Status = http.StatusConflict
+            results[i].Error = "order already exists"
+        case errors.Is(err, errOutOfStock):
+            results[i].Status = http.StatusConflict
+            results[i].Error = "insufficient inventory for product"
+        case errors.Is(err, errOrderUnprocessed):
+            results[i].Status = http.StatusServiceUnavailable
+            results[i].Error = "order was not processed, retry later"
+        case err != nil:
+            results[i].Status = backend.Status(err)
+            results[i].Error = "failed to save order"
+        default:
+            results[i].Status = http.StatusCreated
+            saveToCache(ctx, &orders[i])
+            enqueueOrderWebhook(ctx, &orders[i])
+            publishOrderCreated(ctx, &orders[i])
+            created = append(created, orders[i].ID)
+        }
+    }
+
+    // 재고 차감은 주문마다 재고 항목과 묶은 트랜잭션이어야 하므로 한 건씩 저장함.
+    // 같은 상품의 재고를 여러 주문이 나눠 쓰므로 앞선 주문이 재고를 다 쓰면 뒤의 주문은 409가 됨
+    if inventoryTable != "" {
+        for _, i := range valid {
+            record(i, saveOrderToDynamoDB(ctx, &orders[i]))
+        }
+    } else {
+        saveOrderChunks(ctx, orders, valid, record)
+    }
+
+    if len(created) > 0 {
+        recordOrderAudits(ctx, c, created, auditCreate)
+    }
+
+    respond.JSON(c, http.StatusMultiStatus, gin.H{"results": results})
+}
+
+// valid의 주문을 dynamoBatchSize씩 나눠 저장하고 항목마다 record로 결과를 넘김
+func saveOrderChunks(ctx context.Context, orders []Order, valid []int, record func(int, error)) {
+    for start := 0; start < len(valid); start += dynamoBatchSize {
+        end := start + dynamoBatchSize
+        if end > len(valid) {
+            end = len(valid)
+        }
+        chunk := valid[start:end]
+
+        batch := make([]*Order, 0, len(chunk))
+        for _, i := range chunk {
+            batch = append(batch, &orders[i])
+        }
+
+        failed, err := saveOrderBatchToDynamoDB(ctx, batch)
+        for _, i := range chunk {
+            if err != nil {
+                record(i, err)
+                continue
+            }
+            record(i, failed[orders[i].ID])
+        }
+    }
+}
+
+func validateBatchOrder(c *gin.Context, order *Order) string {
+    var missing []string
+    if orderIDStrategy == orderIDClient && order.ID == "" {
+        missing = append(missing, "id")
+    }
+    if order.CustomerID == "" {
+        missing = append(missing, "customerid")
+    }
+    if order.ProductID == "" {
+        missing = append(missing, "productid")
+    }
+    if len(missing) > 0 {
+        for _, field := range missing {
+            metrics.RecordValidationField(c, field)
+        }
+        return "missing required fields: " + strings.Join(missing, ", ")
+    }
+
+    if field, err := checkOrderIDs(order, orderIDStrategy == orderIDClient); err != nil {
+        metrics.RecordValidationField(c, field)
+        return err.Error()
+    }
+    return ""
+}
+
+// 최대 25건을 BatchWriteItem 한 번으로 쓰고 UnprocessedItems는 batchWriteWithRetry가 다시 보냄.
+// BatchWriteItem에는 조건식을 걸 수 없으므로 클라이언트가 id를 정하는 경우 먼저 BatchGetItem으로
+// 이미 있는 id를 찾아 errOrderExists로 빼고 나머지만 씀. 확인과 쓰기 사이에 같은 id로 만든 주문은
+// 덮어쓸 수 있으므로 그 보장이 필요하면 POST /v1/order로 한 건씩 만들어야 함.
+// 서버가 만든 id는 겹치지 않으므로 확인하지 않음. 쓰지 못한 주문의 id별 오류를 반환함
+func saveOrderBatchToDynamoDB(ctx context.Context, orders []*Order) (map[string]error, error) {
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosDBFailRate); err != nil {
+        return nil, err
+    }
+
+    failed := make(map[string]error)
+    if orderIDStrategy == orderIDClient {
+        ids := make([]string, len(orders))
+        for i, order := range orders {
+            ids[i] = order.ID
+        }
+        existing, err := existingOrderIDs(ctx, ids)
+        if err != nil {
+            logger.ErrorContext(ctx, "Error checking existing orders in DynamoDB", "count", len(orders), "error", err)
+            return nil, err
+        }
+        for id := range existing {
+            failed[id] = errOrderExists
+        }
+    }
+
+    now := clock.Now().UTC()
+    requests := make([]types.WriteRequest, 0, len(orders))
+    for _, order := range orders {
+        if failed[order.ID] != nil {
+            continue
+        }
+        order.CreatedAt = now
+        order.UpdatedAt = now
+        requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: orderToItem(order)}})
+    }
+    if len(requests) == 0 {
+        return failed, nil
+    }
+
+    unprocessed, err := batchWriteWithRetry(ctx, map[string][]types.WriteRequest{orderTable: requests})
+    if err != nil {
+        logger.ErrorContext(ctx, "Error saving batch of orders to DynamoDB", "count", len(requests), "error", err)
+        return nil, err
+    }
+    for _, request := range unprocessed[orderTable] {
+        if id, ok := request.PutRequest.Item["id"].(*types.AttributeValueMemberS); ok {
+            failed[id.Value] = errOrderUnprocessed
+        }
+    }
+    if n := len(unprocessed[orderTable]); n > 0 {
+        logger.WarnContext(ctx, "Orders still unprocessed after retries", "count", n, "retries", dynamoMaxRetries)
+    }
+
+    logger.InfoContext(ctx, "Saved batch of orders to DynamoDB", "count", len(orders)-len(failed))
+    return failed, nil
+}
+
+// ids 중 주문 테이블에 이미 있는 것. UnprocessedKeys도 batchWriteWithRetry와 같은 방식으로 다시 읽으며
+// 끝까지 읽지 못하면 덮어쓰지 않도록 errOrderUnprocessed를 반환함
+func existingOrderIDs(ctx context.Context, ids []string) (map[string]bool, error) {
+    keys := make([]map[string]types.AttributeValue, len(ids))
+    for i, id := range ids {
+        keys[i] = orderKey(id)
+    }
+    request := map[string]types.KeysAndAttributes{
+        orderTable: {Keys: keys, ProjectionExpression: aws.String("id"), ConsistentRead: aws.Bool(true)},
+    }
+
+    existing := make(map[string]bool)
+    for attempt := 0; len(request) > 0; attempt++ {
+        if attempt > dynamoMaxRetries {
+            return nil, errOrderUnprocessed
+        }
+        if err := waitBatchRetry(ctx, attempt); err != nil {
+            return nil, err
+        }
+        result, err := dynamoClient.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: request})
+        if err != nil {
+            return nil, err
+        }
+        for _, item := range result.Responses[orderTable] {
+            if id, ok := item["id"].(*types.AttributeValueMemberS); ok {
+                existing[id.Value] = true
+            }
+        }
+        request = result.UnprocessedKeys
+    }
+    return existing, nil
+}
+
+// BatchWriteItem은 스로틀링 등으로 처리하지 못한 항목을 오류 없이 UnprocessedItems로 돌려주므로
+// 남은 항목을 DynamoDB 재시도기와 같은 jitter 백오프로 dynamoMaxRetries번까지 다시 보냄.
+// 끝까지 남은 요청을 반환함
+func batchWriteWithRetry(ctx context.Context, requests map[string][]types.WriteRequest) (map[string][]types.WriteRequest, error) {
+    for attempt := 0; ; attempt++ {
+        if err := waitBatchRetry(ctx, attempt); err != nil {
+            return nil, err
+        }
+        result, err := dynamoClient.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{RequestItems: requests})
+        if err != nil {
+            return nil, err
+        }
+        requests = result.UnprocessedItems
+        if len(requests) == 0 || attempt == dynamoMaxRetries {
+            return requests, nil
+        }
+    }
+}
+
+// 첫 시도(attempt 0)는 기다리지 않음. 백엔드 타임아웃이나 클라이언트 취소로 끝난 요청은 백오프를 기다리지 않음
+func waitBatchRetry(ctx context.Context, attempt int) error {
+    if attempt == 0 {
+        return nil
+    }
+    delay, _ := jitterBackoff{base: dynamoRetryBaseDelay, max: dynamoRetryMaxDelay}.BackoffDelay(attempt, nil)
+    select {
+    case <-ctx.Done():
+        return ctx.Err()
+    case <-time.After(delay):
+        return nil
+    }
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "testing"
+    "time"
+)
+
+// BatchWriteItem 요청에서 table에 넣는 PutRequest 항목들
+func batchPutItems(body map[string]interface{}, table string) []interface{} {
+    requests, _ := body["RequestItems"].(map[string]interface{})
+    items, _ := requests[table].([]interface{})
+    return items
+}
+
+func batchPutIDs(body map[string]interface{}) []string {
+    items := batchPutItems(body, orderTable)
+    ids := make([]string, 0, len(items))
+    for _, item := range items {
+        m, _ := item.(map[string]interface{})
+        ids = append(ids, attrS(m, "PutRequest", "Item", "id"))
+    }
+    return ids
+}
+
+// 받은 항목 중 앞의 n개를 처리하지 못한 것으로 돌려주는 BatchWriteItem 응답
+func unprocessedResponse(body map[string]interface{}, table string, n int) (int, interface{}) {
+    items := batchPutItems(body, table)
+    if n > len(items) {
+        n = len(items)
+    }
+    if n == 0 {
+        return http.StatusOK, map[string]interface{}{}
+    }
+    return http.StatusOK, map[string]interface{}{
+        "UnprocessedItems": map[string]interface{}{table: items[:n]},
+    }
+}
+
+// UnprocessedItems 재시도 백오프를 짧게 줄임
+func useFastBatchRetry(t *testing.T) {
+    t.Helper()
+    prev := dynamoRetryBaseDelay
+    dynamoRetryBaseDelay = time.Millisecond
+    t.Cleanup(func() { dynamoRetryBaseDelay = prev })
+}
+
+func batchOrders(n int) []map[string]interface{} {
+    orders := make([]map[string]interface{}, n)
+    for i := range orders {
+        orders[i] = map[string]interface{}{
+            "id":         fmt.Sprintf("o%d", i),
+            "customerid": "alice",
+            "productid":  "p1",
+            "quantity":   1,
+        }
+    }
+    return orders
+}
+
+func decodeBatchResults(t *testing.T, body []byte) []batchItemResult {
+    t.Helper()
+    var resp struct {
+        Results []batchItemResult `json:"results"`
+    }
+    if err := json.Unmarshal(body, &resp); err != nil {
+        t.Fatalf("decode response: %v (%s)", err, body)
+    }
+    return resp.Results
+}
+
+func checkBatchStatuses(t *testing.T, body []byte, want []int) {
+    t.Helper()
+    results := decodeBatchResults(t, body)
+    if len(results) != len(want) {
+        t.Fatalf("got %d results, want %d", len(results), len(want))
+    }
+    for i, result := range results {
+        if result.Status != want[i] {
+            t.Errorf("item %d: status %d, want %d (%s)", i, result.Status, want[i], result.Error)
+        }
+    }
+}
+
+// 60건은 dynamoBatchSize(25)씩 25, 25, 10건의 BatchWriteItem 세 번으로 나눠 씀
+func TestBatchWritesInChunks(t *testing.T) {
+    fake := newFakeDynamo(t, nil)
+
+    w := doRequest(newRouter(), http.MethodPost, "/v1/orders/batch", batchOrders(60), nil)
+    if w.Code != http.StatusMultiStatus {
+        t.Fatalf("status %d, want 207 (%s)", w.Code, w.Body)
+    }
+    for i, result := range decodeBatchResults(t, w.Body.Bytes()) {
+        if result.Status != http.StatusCreated {
+            t.Errorf("item %d: status %d, want 201 (%s)", i, result.Status, result.Error)
+        }
+    }
+
+    calls := fake.callsTo("BatchWriteItem")
+    var orderCalls []dynamoCall
+    for _, call := range calls {
+        if len(batchPutIDs(call.Body)) > 0 {
+            orderCalls = append(orderCalls, call)
+        }
+    }
+    if len(orderCalls) != 3 {
+        t.Fatalf("BatchWriteItem called %d times for orders, want 3", len(orderCalls))
+    }
+    seen := make(map[string]bool)
+    for i, want := range []int{25, 25, 10} {
+        ids := batchPutIDs(orderCalls[i].Body)
+        if len(ids) != want {
+            t.Errorf("batch %d has %d orders, want %d", i, len(ids), want)
+        }
+        for _, id := range ids {
+            seen[id] = true
+        }
+    }
+    if len(seen) != 60 {
+        t.Errorf("wrote %d distinct orders, want 60", len(seen))
+    }
+}
+
+// UnprocessedItems로 돌아온 주문은 다시 보내 결국 201
+func TestBatchRetriesUnprocessedItems(t *testing.T) {
+    useFastBatchRetry(t)
+    writes := 0
+    fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        if op != "BatchWriteItem" || len(batchPutIDs(body)) == 0 {
+            return http.StatusOK, map[string]interface{}{}
+        }
+        writes++
+        if writes == 1 {
+            return unprocessedResponse(body, orderTable, 2)
+        }
+        return http.StatusOK, map[string]interface{}{}
+    })
+
+    w := doRequest(newRouter(), http.MethodPost, "/v1/orders/batch", batchOrders(5), nil)
+    if w.Code != http.StatusMultiStatus {
+        t.Fatalf("status %d, want 207 (%s)", w.Code, w.Body)
+    }
+    checkBatchStatuses(t, w.Body.Bytes(), []int{201, 201, 201, 201, 201})
+
+    var ids [][]string
+    for _, call := range fake.callsTo("BatchWriteItem") {
+        if got := batchPutIDs(call.Body); len(got) > 0 {
+            ids = append(ids, got)
+        }
+    }
+    if len(ids) != 2 || len(ids[0]) != 5 || len(ids[1]) != 2 || ids[1][0] != "o0" || ids[1][1] != "o1" {
+        t.Errorf("BatchWriteItem order ids %v, want all five then [o0 o1]", ids)
+    }
+}
+
+// 재시도를 다 써도 남은 주문은 503으로 알리고 나머지는 201
+func TestBatchReportsItemsStillUnprocessed(t *testing.T) {
+    useFastBatchRetry(t)
+    fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        if op != "BatchWriteItem" || len(batchPutIDs(body)) == 0 {
+            return http.StatusOK, map[string]interface{}{}
+        }
+        return unprocessedResponse(body, orderTable, 1)
+    })
+
+    w := doRequest(newRouter(), http.MethodPost, "/v1/orders/batch", batchOrders(3), nil)
+    if w.Code != http.StatusMultiStatus {
+        t.Fatalf("status %d, want 207 (%s)", w.Code, w.Body)
+    }
+    checkBatchStatuses(t, w.Body.Bytes(), []int{http.StatusServiceUnavailable, 201, 201})
+
+    writes := 0
+    for _, call := range fake.callsTo("BatchWriteItem") {
+        if len(batchPutIDs(call.Body)) > 0 {
+            writes++
+        }
+    }
+    if writes != dynamoMaxRetries+1 {
+        t.Errorf("BatchWriteItem called %d times for orders, want %d", writes, dynamoMaxRetries+1)
+    }
+}
+
+// 이미 있는 id는 BatchGetItem으로 찾아 쓰지 않고 그 항목만 409, 나머지는 201
+func TestBatchDoesNotOverwriteExistingOrder(t *testing.T) {
+    fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        if op == "BatchGetItem" {
+            return http.StatusOK, map[string]interface{}{
+                "Responses": map[string]interface{}{orderTable: []interface{}{dynamoItem(map[string]interface{}{"id": "o1"})}},
+            }
+        }
+        return http.StatusOK, map[string]interface{}{}
+    })
+
+    w := doRequest(newRouter(), http.MethodPost, "/v1/orders/batch", batchOrders(3), nil)
+    if w.Code != http.StatusMultiStatus {
+        t.Fatalf("status %d, want 207 (%s)", w.Code, w.Body)
+    }
+    checkBatchStatuses(t, w.Body.Bytes(), []int{http.StatusCreated, http.StatusConflict, http.StatusCreated})
+
+    gets := fake.callsTo("BatchGetItem")
+    if len(gets) != 1 {
+        t.Fatalf("BatchGetItem called %d times, want 1", len(gets))
+    }
+    var ids []string
+    for _, call := range fake.callsTo("BatchWriteItem") {
+        ids = append(ids, batchPutIDs(call.Body)...)
+    }
+    if len(ids) != 2 || ids[0] != "o0" || ids[1] != "o2" {
+        t.Errorf("wrote %v, want [o0 o2]", ids)
+    }
+}
+
+// 서버가 id를 만들면 겹칠 수 없으므로 BatchGetItem으로 확인하지 않음
+func TestBatchSkipsExistenceCheckForServerIDs(t *testing.T) {
+    prev := orderIDStrategy
+    orderIDStrategy = orderIDUUID
+    t.Cleanup(func() { orderIDStrategy = prev })
+    fake := newFakeDynamo(t, nil)
+
+    w := doRequest(newRouter(), http.MethodPost, "/v1/orders/batch", batchOrders(3), nil)
+    if w.Code != http.StatusMultiStatus {
+        t.Fatalf("status %d, want 207 (%s)", w.Code, w.Body)
+    }
+    checkBatchStatuses(t, w.Body.Bytes(), []int{201, 201, 201})
+    if gets := fake.callsTo("BatchGetItem"); len(gets) != 0 {
+        t.Errorf("BatchGetItem called %d times, want 0", len(gets))
+    }
+}
+
+// INVENTORY_TABLE이 있으면 주문마다 재고를 차감하고 남은 재고를 넘는 주문은 409
+func TestBatchRefusesOrdersOverAvailableStock(t *testing.T) {
+    useInventoryTable(t)
+
+    stock := 2
+    fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        if op != "TransactWriteItems" {
+            return http.StatusOK, map[string]interface{}{}
+        }
+        if stock < 1 {
+            return transactionCanceled("None", "ConditionalCheckFailed")
+        }
+        stock--
+        return http.StatusOK, map[string]interface{}{}
+    })
+
+    w := doRequest(newRouter(), http.MethodPost, "/v1/orders/batch", batchOrders(3), nil)
+    if w.Code != http.StatusMultiStatus {
+        t.Fatalf("status %d, want 207 (%s)", w.Code, w.Body)
+    }
+    want := []int{http.StatusCreated, http.StatusCreated, http.StatusConflict}
+    for i, result := range decodeBatchResults(t, w.Body.Bytes()) {
+        if result.Status != want[i] {
+            t.Errorf("item %d: status %d, want %d (%s)", i, result.Status, want[i], result.Error)
+        }
+    }
+
+    calls := fake.callsTo("TransactWriteItems")
+    if len(calls) != 3 {
+        t.Fatalf("TransactWriteItems called %d times, want one per order", len(calls))
+    }
+    for _, call := range calls {
+        items := call.Body["TransactItems"].([]interface{})
+        if len(items) != 2 {
+            t.Fatalf("transaction has %d items, want order put and inventory update", len(items))
+        }
+        update, _ := items[1].(map[string]interface{})["Update"].(map[string]interface{})
+        if update["TableName"] != "inventory" || attrS(update, "Key", "productid") != "p1" {
+            t.Errorf("inventory update %v, want inventory table keyed by productid p1", update)
+        }
+    }
+}
+
+// 배치로 만든 주문도 한 건씩 만든 주문처럼 order.created 이벤트를 보내고, 실패한 항목은 보내지 않음
+func TestBatchPublishesEventPerCreatedOrder(t *testing.T) {
+    var published []string
+    prev := publishEvent
+    publishEvent = func(ctx context.Context, body string) error {
+        var event orderEvent
+        if err := json.Unmarshal([]byte(body), &event); err != nil {
+            t.Errorf("decode event: %v", err)
+            return nil
+        }
+        if event.Type != eventOrderCreated {
+            t.Errorf("event type %q, want %q", event.Type, eventOrderCreated)
+        }
+        published = append(published, event.Order.ID)
+        return nil
+    }
+    t.Cleanup(func() { publishEvent = prev })
+    newFakeDynamo(t, nil)
+
+    orders := batchOrders(3)
+    delete(orders[1], "productid")
+    w := doRequest(newRouter(), http.MethodPost, "/v1/orders/batch", orders, nil)
+    if w.Code != http.StatusMultiStatus {
+        t.Fatalf("status %d, want 207 (%s)", w.Code, w.Body)
+    }
+    if len(published) != 2 || published[0] != "o0" || published[1] != "o2" {
+        t.Errorf("published events for %v, want [o0 o2]", published)
+    }
+}
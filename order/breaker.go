@@ -0,0 +1,89 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "net/http"
+    "time"
+
+    awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+    "github.com/aws/aws-sdk-go-v2/aws/retry"
+    "github.com/aws/smithy-go"
+    "github.com/aws/smithy-go/middleware"
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/sony/gobreaker/v2"
+)
+
+// DynamoDB가 느려지면 요청마다 백엔드 타임아웃까지 기다리다 쌓이므로, API 호출이 연속
+// DYNAMODB_BREAKER_FAILURES번 실패하면 DYNAMODB_BREAKER_OPEN_SECONDS 동안 호출하지 않고 503을 반환함.
+// 그 뒤 한 번의 시험 호출이 성공하면 다시 닫힘. 재시도는 차단기 안쪽에서 일어나므로
+// 재시도를 포함한 한 번의 API 호출을 한 번으로 셈. 0이면 차단기를 쓰지 않음
+var (
+    dynamoBreakerFailures = envInt("DYNAMODB_BREAKER_FAILURES", 5)
+    dynamoBreakerOpen     = time.Duration(envInt("DYNAMODB_BREAKER_OPEN_SECONDS", 30)) * time.Second
+)
+
+// backend.Status가 503으로 바꾸도록 backend.ErrUnavailable을 감쌈
+var errDynamoUnavailable = fmt.Errorf("dynamodb calls suspended by circuit breaker: %w", backend.ErrUnavailable)
+
+// API 호출마다 스택을 새로 만들어 addDynamoBreaker를 부르므로 차단기는 미리 하나만 만들어 둠
+var dynamoBreaker = newDynamoBreaker()
+
+func newDynamoBreaker() *gobreaker.CircuitBreaker[middleware.InitializeOutput] {
+    if dynamoBreakerFailures == 0 {
+        return nil
+    }
+    failures := uint32(dynamoBreakerFailures)
+    return gobreaker.NewCircuitBreaker[middleware.InitializeOutput](gobreaker.Settings{
+        Name:    "dynamodb",
+        Timeout: dynamoBreakerOpen,
+        ReadyToTrip: func(counts gobreaker.Counts) bool {
+            return counts.ConsecutiveFailures >= failures
+        },
+        IsSuccessful: dynamoCallSucceeded,
+        OnStateChange: func(name string, from, to gobreaker.State) {
+            logger.Warn("DynamoDB circuit breaker state changed", "from", from.String(), "to", to.String())
+        },
+    })
+}
+
+// dynamodb.Options.APIOptions에 넣어 모든 DynamoDB 호출(주문, 감사 테이블)에 적용함
+func addDynamoBreaker(stack *middleware.Stack) error {
+    if dynamoBreaker == nil {
+        return nil
+    }
+    return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("DynamoBreaker",
+        func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+            var metadata middleware.Metadata
+            out, err := dynamoBreaker.Execute(func() (middleware.InitializeOutput, error) {
+                out, md, err := next.HandleInitialize(ctx, in)
+                metadata = md
+                return out, err
+            })
+            if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+                return out, metadata, errDynamoUnavailable
+            }
+            return out, metadata, err
+        }), middleware.Before)
+}
+
+// 조건식 실패나 검증 오류처럼 DynamoDB가 정상적으로 거절한 경우와 호출한 쪽의 취소는 실패로 세지 않음.
+// 5xx, 스로틀링, 타임아웃과 네트워크 오류만 셈
+func dynamoCallSucceeded(err error) bool {
+    if err == nil || errors.Is(err, context.Canceled) {
+        return true
+    }
+    var respErr *awshttp.ResponseError
+    if errors.As(err, &respErr) && respErr.HTTPStatusCode() >= http.StatusInternalServerError {
+        return false
+    }
+    var apiErr smithy.APIError
+    if errors.As(err, &apiErr) {
+        if _, throttle := retry.DefaultThrottleErrorCodes[apiErr.ErrorCode()]; throttle {
+            return false
+        }
+        return apiErr.ErrorFault() != smithy.FaultServer
+    }
+    return false
+}
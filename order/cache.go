@@ -0,0 +1,119 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "os"
+    "strconv"
+    "time"
+
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/cacheaside"
+    "github.com/gmstcl/eCommerce-System/internal/chaos"
+    "github.com/gmstcl/eCommerce-System/internal/healthz"
+    "github.com/gmstcl/eCommerce-System/internal/redisconn"
+    "github.com/gmstcl/eCommerce-System/internal/tracing"
+    "github.com/go-redis/redis/v8"
+)
+
+// REDIS_HOST가 없으면 캐시 없이 DynamoDB만 사용함
+var (
+    redisClient *redis.Client
+    cacheTTL    = 300 * time.Second
+)
+
+func initCache() {
+    redisAddr := os.Getenv("REDIS_HOST")
+    if redisAddr == "" {
+        logger.Info("REDIS_HOST not set, order cache disabled")
+        return
+    }
+
+    if v := os.Getenv("CACHE_TTL_SECONDS"); v != "" {
+        seconds, err := strconv.Atoi(v)
+        if err != nil || seconds <= 0 {
+            log.Fatalf("invalid CACHE_TTL_SECONDS %q", v)
+        }
+        cacheTTL = time.Duration(seconds) * time.Second
+    }
+
+    redisDB := 0
+    if v := os.Getenv("REDIS_DB"); v != "" {
+        var err error
+        redisDB, err = strconv.Atoi(v)
+        if err != nil || redisDB < 0 {
+            log.Fatalf("invalid REDIS_DB %q (want a non-negative integer)", v)
+        }
+    }
+
+    opts := &redis.Options{
+        Addr:      fmt.Sprintf("%s:%s", redisAddr, os.Getenv("REDIS_PORT")),
+        DB:        redisDB,
+        TLSConfig: redisconn.TLSConfig(),
+    }
+    redisconn.ApplyPoolOptions(opts)
+    redisClient = redis.NewClient(opts)
+
+    if _, err := redisClient.Ping(context.Background()).Result(); err != nil {
+        logger.Error("Redis connection error", "error", err)
+    } else {
+        logger.Info("Connected to Redis successfully")
+    }
+
+    // 캐시, 멱등성 키, 요청 제한이 Redis 없이도 동작하므로 실패해도 degraded로만 보고함
+    healthChecks["redis"] = healthz.Check{Run: func(ctx context.Context) error {
+        return redisClient.Ping(ctx).Err()
+    }}
+}
+
+// 캐시가 꺼져 있으면 빈 문자열을 반환함
+func redisPoolSummary() string {
+    if redisClient == nil {
+        return ""
+    }
+    opts := redisClient.Options()
+    return fmt.Sprintf("size=%d dial_timeout=%s read_timeout=%s", opts.PoolSize, opts.DialTimeout, opts.ReadTimeout)
+}
+
+func orderCache() *cacheaside.Cache {
+    return &cacheaside.Cache{
+        Store:  cacheaside.RedisStore(func() *redis.Client { return redisClient }),
+        TTL:    cacheTTL,
+        IDKey:  "order_id",
+        // 고객/상품 서비스와 같은 Redis를 쓰더라도 키가 겹치지 않도록 접두사를 붙임
+        Prefix: "order:",
+        Logger: logger,
+        Begin: func(ctx context.Context, op, key string) (context.Context, func()) {
+            ctx, span := tracing.Start(ctx, op, "order_id", key)
+            ctx, cancel := backend.WithTimeout(ctx)
+            return ctx, func() {
+                cancel()
+                span.End()
+            }
+        },
+        Fail: func() error { return chaos.Inject(chaosCacheFailRate) },
+    }
+}
+
+// 캐시가 꺼져 있으면 항상 미스임
+func getFromCache(ctx context.Context, orderID string) (*Order, error) {
+    if redisClient == nil {
+        return nil, nil
+    }
+    return cacheaside.Get[Order](ctx, orderCache(), orderID)
+}
+
+func saveToCache(ctx context.Context, order *Order) {
+    if redisClient == nil {
+        return
+    }
+    orderCache().Set(ctx, order.ID, order)
+}
+
+func deleteFromCache(ctx context.Context, orderID string) {
+    if redisClient == nil {
+        return
+    }
+    orderCache().Delete(ctx, orderID)
+}
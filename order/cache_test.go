@@ -0,0 +1,33 @@
+package main
+
+import (
+    "net/http"
+    "strings"
+    "testing"
+)
+
+// 두 번째 GET은 Redis에서 응답하고 DynamoDB를 다시 읽지 않음
+func TestSecondGetIsServedFromCache(t *testing.T) {
+    mr := useMiniredis(t)
+    fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        if op == "GetItem" {
+            return http.StatusOK, map[string]interface{}{"Item": orderItem("o1", "alice", "p1", 2)}
+        }
+        return http.StatusOK, map[string]interface{}{}
+    })
+    router := newRouter()
+
+    for i := 0; i < 2; i++ {
+        w := doRequest(router, http.MethodGet, "/v1/order?id=o1", nil, nil)
+        if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"id":"o1"`) {
+            t.Fatalf("GET %d: status %d %s", i+1, w.Code, w.Body)
+        }
+    }
+
+    if n := len(fake.callsTo("GetItem")); n != 1 {
+        t.Errorf("GetItem called %d times, want 1", n)
+    }
+    if ttl := mr.TTL("order:o1"); ttl != cacheTTL {
+        t.Errorf("cache TTL %v, want %v", ttl, cacheTTL)
+    }
+}
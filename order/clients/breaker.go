@@ -0,0 +1,99 @@
+package clients
+
+import (
+    "sync"
+    "time"
+)
+
+// 연속 실패가 failures번이면 열리고 openTimeout이 지나면 시험 요청 하나만 통과시킴.
+// 시험 요청이 성공하면 닫히고 실패하면 다시 열림. 시간은 now로 읽어 테스트에서 시계를 바꿀 수 있음
+type breaker struct {
+    name          string
+    failures      int
+    openTimeout   time.Duration
+    now           func() time.Time
+    onStateChange func(name, from, to string)
+
+    mu          sync.Mutex
+    state       string
+    consecutive int
+    openedAt    time.Time
+    probing     bool
+}
+
+const (
+    stateClosed   = "closed"
+    stateOpen     = "open"
+    stateHalfOpen = "half-open"
+)
+
+func newBreaker(cfg Config) *breaker {
+    now := cfg.Now
+    if now == nil {
+        now = time.Now
+    }
+    return &breaker{
+        name:          cfg.BaseURL,
+        failures:      cfg.BreakerFailures,
+        openTimeout:   cfg.BreakerOpenTimeout,
+        now:           now,
+        onStateChange: cfg.OnBreakerStateChange,
+        state:         stateClosed,
+    }
+}
+
+// 요청을 보내도 되는지 확인함. 반쯤 열린 상태에서는 시험 요청 하나만 허용함
+func (b *breaker) allow() bool {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+
+    if b.state == stateOpen && !b.now().Before(b.openedAt.Add(b.openTimeout)) {
+        b.setState(stateHalfOpen)
+    }
+    switch b.state {
+    case stateOpen:
+        return false
+    case stateHalfOpen:
+        if b.probing {
+            return false
+        }
+        b.probing = true
+    }
+    return true
+}
+
+// allow가 허용한 요청의 결과를 기록함
+func (b *breaker) record(success bool) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+
+    // 열린 뒤에 끝난, 열리기 전에 보낸 요청의 결과는 무시함
+    if b.state == stateOpen {
+        return
+    }
+    b.probing = false
+    if success {
+        b.consecutive = 0
+        if b.state != stateClosed {
+            b.setState(stateClosed)
+        }
+        return
+    }
+
+    b.consecutive++
+    if b.state == stateHalfOpen || b.consecutive >= b.failures {
+        b.openedAt = b.now()
+        b.setState(stateOpen)
+    }
+}
+
+func (b *breaker) setState(to string) {
+    from := b.state
+    b.state = to
+    if to != stateOpen {
+        b.consecutive = 0
+    }
+    if b.onStateChange != nil && from != to {
+        b.onStateChange(b.name, from, to)
+    }
+}
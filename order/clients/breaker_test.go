@@ -0,0 +1,118 @@
+package clients
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+)
+
+// 연속 실패가 기준에 닿으면 열려 요청을 보내지 않고, 열림 시간이 지난 뒤 시험 요청이 성공하면 닫힘
+func TestBreakerTripsAndRecovers(t *testing.T) {
+    var hits atomic.Int32
+    var healthy atomic.Bool
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        hits.Add(1)
+        if !healthy.Load() {
+            w.WriteHeader(http.StatusServiceUnavailable)
+            return
+        }
+        w.Write([]byte(`{"id":"c1"}`))
+    }))
+    t.Cleanup(srv.Close)
+
+    clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+    var transitions []string
+    c := NewCustomerClient(Config{
+        BaseURL:            srv.URL,
+        Timeout:            time.Second,
+        BreakerFailures:    3,
+        BreakerOpenTimeout: 30 * time.Second,
+        OnBreakerStateChange: func(name, from, to string) {
+            transitions = append(transitions, from+"->"+to)
+        },
+        Now: clk.Now,
+    })
+    ctx := context.Background()
+
+    for i := 0; i < 3; i++ {
+        if _, err := c.GetCustomer(ctx, "c1"); err == nil || errors.Is(err, ErrCircuitOpen) {
+            t.Fatalf("call %d: err = %v, want upstream failure", i, err)
+        }
+    }
+    if _, err := c.GetCustomer(ctx, "c1"); !errors.Is(err, ErrCircuitOpen) {
+        t.Fatalf("after threshold: err = %v, want ErrCircuitOpen", err)
+    }
+    if n := hits.Load(); n != 3 {
+        t.Errorf("server hit %d times while open, want 3", n)
+    }
+
+    // 열림 시간이 지나기 전에는 계속 막음
+    clk.Advance(29 * time.Second)
+    if _, err := c.GetCustomer(ctx, "c1"); !errors.Is(err, ErrCircuitOpen) {
+        t.Fatalf("before open timeout: err = %v, want ErrCircuitOpen", err)
+    }
+
+    healthy.Store(true)
+    clk.Advance(time.Second)
+    if _, err := c.GetCustomer(ctx, "c1"); err != nil {
+        t.Fatalf("probe after open timeout: %v", err)
+    }
+    if _, err := c.GetCustomer(ctx, "c1"); err != nil {
+        t.Fatalf("after recovery: %v", err)
+    }
+
+    want := []string{"closed->open", "open->half-open", "half-open->closed"}
+    if len(transitions) != len(want) {
+        t.Fatalf("transitions %v, want %v", transitions, want)
+    }
+    for i := range want {
+        if transitions[i] != want[i] {
+            t.Errorf("transition %d = %q, want %q", i, transitions[i], want[i])
+        }
+    }
+}
+
+// 시험 요청이 실패하면 다시 열림 시간만큼 막음
+func TestBreakerReopensWhenProbeFails(t *testing.T) {
+    srv := newFlakyServer(t, 100, http.StatusBadGateway)
+    clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+    c := NewProductClient(Config{
+        BaseURL:            srv.URL,
+        Timeout:            time.Second,
+        BreakerFailures:    2,
+        BreakerOpenTimeout: 10 * time.Second,
+        Now:                clk.Now,
+    })
+    ctx := context.Background()
+
+    c.GetProduct(ctx, "p1")
+    c.GetProduct(ctx, "p1")
+    clk.Advance(10 * time.Second)
+    if _, err := c.GetProduct(ctx, "p1"); err == nil || errors.Is(err, ErrCircuitOpen) {
+        t.Fatalf("probe: err = %v, want upstream failure", err)
+    }
+    if _, err := c.GetProduct(ctx, "p1"); !errors.Is(err, ErrCircuitOpen) {
+        t.Fatalf("after failed probe: err = %v, want ErrCircuitOpen", err)
+    }
+    if n := srv.hits.Load(); n != 3 {
+        t.Errorf("server hit %d times, want 3", n)
+    }
+}
+
+// 404처럼 상대 서비스가 정상적으로 답한 경우는 실패로 세지 않음
+func TestBreakerIgnoresNotFound(t *testing.T) {
+    srv := newFlakyServer(t, 100, http.StatusNotFound)
+    c := NewProductClient(Config{BaseURL: srv.URL, Timeout: time.Second, BreakerFailures: 1, BreakerOpenTimeout: time.Minute})
+
+    for i := 0; i < 3; i++ {
+        if _, err := c.GetProduct(context.Background(), "p1"); !errors.Is(err, ErrNotFound) {
+            t.Fatalf("call %d: err = %v, want ErrNotFound", i, err)
+        }
+    }
+}
@@ -0,0 +1,203 @@
+package clients
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net/http"
+    "net/url"
+    "time"
+
+    "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// ErrNotFound는 상대 서비스가 404를 돌려준 경우
+var ErrNotFound = errors.New("not found")
+
+// ErrCircuitOpen은 최근 연속 실패로 차단기가 열려 요청을 보내지 않은 경우
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// 200/404 외의 응답 상태
+type statusError struct {
+    path string
+    code int
+}
+
+func (e *statusError) Error() string {
+    return fmt.Sprintf("GET %s: unexpected status %d", e.path, e.code)
+}
+
+type Customer struct {
+    ID     string `json:"id"`
+    Name   string `json:"name"`
+    Gender string `json:"gender"`
+}
+
+type Product struct {
+    ID       string `json:"id"`
+    Name     string `json:"name"`
+    Category string `json:"category"`
+}
+
+type Config struct {
+    BaseURL string
+    Timeout time.Duration
+    Retries int
+    // 호출한 요청의 ID를 X-Request-ID 헤더로 전달해 서비스 간 로그를 연결함
+    RequestID func(context.Context) string
+    // 상대 서비스가 API_KEYS로 보호되어 있을 때 X-API-Key로 보냄
+    APIKey string
+    // 재시도까지 실패한 호출이 연속 BreakerFailures번이면 BreakerOpenTimeout 동안 요청을 보내지 않고
+    // ErrCircuitOpen을 반환함. 그 뒤 한 번의 시험 요청이 성공하면 다시 닫힘. 0이면 차단기를 쓰지 않음
+    BreakerFailures    int
+    BreakerOpenTimeout time.Duration
+    // 차단기 상태가 바뀔 때 호출됨
+    OnBreakerStateChange func(name, from, to string)
+    // 차단기가 현재 시각을 읽는 함수. nil이면 time.Now
+    Now func() time.Time
+}
+
+type client struct {
+    baseURL    string
+    httpClient *http.Client
+    retries    int
+    requestID  func(context.Context) string
+    apiKey     string
+    breaker    *breaker
+}
+
+func newClient(cfg Config) client {
+    c := client{
+        baseURL:    cfg.BaseURL,
+        // 호출한 요청의 트레이스 컨텍스트를 traceparent 헤더로 전달함
+        httpClient: &http.Client{Timeout: cfg.Timeout, Transport: otelhttp.NewTransport(http.DefaultTransport)},
+        retries:    cfg.Retries,
+        requestID:  cfg.RequestID,
+        apiKey:     cfg.APIKey,
+    }
+
+    if cfg.BreakerFailures > 0 {
+        c.breaker = newBreaker(cfg)
+    }
+    return c
+}
+
+// 상대 서비스가 정상적으로 답한 경우(404, 4xx 포함)와 호출한 쪽이 취소한 경우는 실패로 세지 않음
+func countsAsSuccess(err error) bool {
+    var statusErr *statusError
+    switch {
+    case err == nil, errors.Is(err, ErrNotFound), errors.Is(err, context.Canceled):
+        return true
+    case errors.As(err, &statusErr):
+        return statusErr.code < 500
+    }
+    return false
+}
+
+func (c client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+    if c.breaker == nil {
+        return c.getWithRetries(ctx, path, query, out)
+    }
+
+    if !c.breaker.allow() {
+        return ErrCircuitOpen
+    }
+    err := c.getWithRetries(ctx, path, query, out)
+    c.breaker.record(countsAsSuccess(err))
+    return err
+}
+
+// 네트워크 오류와 5xx 응답에 대해서만 재시도함
+func (c client) getWithRetries(ctx context.Context, path string, query url.Values, out interface{}) error {
+    endpoint := c.baseURL + path
+    if len(query) > 0 {
+        endpoint += "?" + query.Encode()
+    }
+
+    var lastErr error
+    for attempt := 0; attempt <= c.retries; attempt++ {
+        if attempt > 0 {
+            select {
+            case <-ctx.Done():
+                return ctx.Err()
+            case <-time.After(time.Duration(attempt) * 100 * time.Millisecond):
+            }
+        }
+
+        req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+        if err != nil {
+            return err
+        }
+        if c.requestID != nil {
+            if id := c.requestID(ctx); id != "" {
+                req.Header.Set("X-Request-ID", id)
+            }
+        }
+        if c.apiKey != "" {
+            req.Header.Set("X-API-Key", c.apiKey)
+        }
+
+        resp, err := c.httpClient.Do(req)
+        if err != nil {
+            lastErr = err
+            continue
+        }
+
+        switch {
+        case resp.StatusCode == http.StatusNotFound:
+            resp.Body.Close()
+            return ErrNotFound
+        case resp.StatusCode >= 500:
+            resp.Body.Close()
+            lastErr = &statusError{path, resp.StatusCode}
+            continue
+        case resp.StatusCode != http.StatusOK:
+            resp.Body.Close()
+            return &statusError{path, resp.StatusCode}
+        }
+
+        err = json.NewDecoder(resp.Body).Decode(out)
+        resp.Body.Close()
+        return err
+    }
+
+    return lastErr
+}
+
+// 기본 URL이 설정되지 않은 클라이언트는 비활성 상태로 봄
+func (c client) Enabled() bool {
+    return c.baseURL != ""
+}
+
+type CustomerClient struct {
+    client
+}
+
+func NewCustomerClient(cfg Config) *CustomerClient {
+    return &CustomerClient{newClient(cfg)}
+}
+
+func (c *CustomerClient) GetCustomer(ctx context.Context, customerID string) (*Customer, error) {
+    var customer Customer
+    if err := c.get(ctx, "/v1/customer", url.Values{"id": {customerID}}, &customer); err != nil {
+        return nil, err
+    }
+    return &customer, nil
+}
+
+type ProductClient struct {
+    client
+}
+
+func NewProductClient(cfg Config) *ProductClient {
+    return &ProductClient{newClient(cfg)}
+}
+
+func (c *ProductClient) GetProduct(ctx context.Context, productID string) (*Product, error) {
+    var product Product
+    if err := c.get(ctx, "/v1/product", url.Values{"id": {productID}}, &product); err != nil {
+        return nil, err
+    }
+    return &product, nil
+}
@@ -0,0 +1,99 @@
+package clients
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+// 처음 failures번은 status로 실패하고 그 뒤에는 상품을 돌려주는 서버
+type flakyServer struct {
+    *httptest.Server
+    hits atomic.Int32
+}
+
+func newFlakyServer(t *testing.T, failures int, status int) *flakyServer {
+    t.Helper()
+    s := &flakyServer{}
+    s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if int(s.hits.Add(1)) <= failures {
+            w.WriteHeader(status)
+            return
+        }
+        w.Write([]byte(`{"id":"` + r.URL.Query().Get("id") + `","name":"Pen","category":"office"}`))
+    }))
+    t.Cleanup(s.Close)
+    return s
+}
+
+func TestRetriesUntilSuccess(t *testing.T) {
+    srv := newFlakyServer(t, 2, http.StatusServiceUnavailable)
+    c := NewProductClient(Config{BaseURL: srv.URL, Timeout: time.Second, Retries: 2})
+
+    product, err := c.GetProduct(context.Background(), "p1")
+    if err != nil {
+        t.Fatalf("GetProduct: %v", err)
+    }
+    if product.ID != "p1" || product.Name != "Pen" {
+        t.Errorf("product %+v, want p1", product)
+    }
+    if n := srv.hits.Load(); n != 3 {
+        t.Errorf("server hit %d times, want 3", n)
+    }
+}
+
+func TestRetriesExhausted(t *testing.T) {
+    srv := newFlakyServer(t, 3, http.StatusInternalServerError)
+    c := NewProductClient(Config{BaseURL: srv.URL, Timeout: time.Second, Retries: 2})
+
+    _, err := c.GetProduct(context.Background(), "p1")
+    var statusErr *statusError
+    if !errors.As(err, &statusErr) || statusErr.code != http.StatusInternalServerError {
+        t.Fatalf("err = %v, want status 500", err)
+    }
+    if n := srv.hits.Load(); n != 3 {
+        t.Errorf("server hit %d times, want 3", n)
+    }
+}
+
+// 404와 5xx가 아닌 응답은 다시 보내지 않음
+func TestNoRetryOnClientErrors(t *testing.T) {
+    for _, tt := range []struct {
+        status int
+        want   error
+    }{
+        {http.StatusNotFound, ErrNotFound},
+        {http.StatusBadRequest, nil},
+    } {
+        srv := newFlakyServer(t, 1, tt.status)
+        c := NewCustomerClient(Config{BaseURL: srv.URL, Timeout: time.Second, Retries: 2})
+
+        _, err := c.GetCustomer(context.Background(), "c1")
+        if err == nil || (tt.want != nil && !errors.Is(err, tt.want)) {
+            t.Errorf("status %d: err = %v, want %v", tt.status, err, tt.want)
+        }
+        if n := srv.hits.Load(); n != 1 {
+            t.Errorf("status %d: server hit %d times, want 1", tt.status, n)
+        }
+    }
+}
+
+// 네트워크 오류도 재시도함
+func TestRetriesNetworkErrors(t *testing.T) {
+    srv := httptest.NewServer(http.NotFoundHandler())
+    url := srv.URL
+    srv.Close()
+    c := NewCustomerClient(Config{BaseURL: url, Timeout: time.Second, Retries: 1})
+
+    start := time.Now()
+    if _, err := c.GetCustomer(context.Background(), "c1"); err == nil {
+        t.Fatal("GetCustomer succeeded against a closed server")
+    }
+    if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+        t.Errorf("returned after %s, want one retry after backoff", elapsed)
+    }
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+    "log"
+    "net/url"
+    "os"
+)
+
+// 로컬 개발용. DynamoDB Local이나 LocalStack 주소(예: http://localhost:4566)를 주면 AWS 대신 그쪽으로 보냄.
+// 자격 증명은 여전히 필요하므로 AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY에 아무 값이나 넣음.
+// LocalStack은 S3 액세스 포인트를 지원하지 않으므로 S3_ACCESS_POINT_ARN에 버킷 이름을 넣음
+var (
+    dynamoEndpoint = endpointEnv("DYNAMODB_ENDPOINT")
+    s3Endpoint     = endpointEnv("S3_ENDPOINT")
+)
+
+func endpointEnv(name string) string {
+    v := os.Getenv(name)
+    if v == "" {
+        return ""
+    }
+    u, err := url.Parse(v)
+    if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+        log.Fatalf("invalid %s %q (want an http or https URL)", name, v)
+    }
+    return v
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "sync"
+    "testing"
+
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/credentials"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+    "github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// 받은 요청의 메서드, 경로, DynamoDB 작업 이름을 남기는 서버
+type endpointRecorder struct {
+    mu       sync.Mutex
+    requests []string
+}
+
+func (r *endpointRecorder) serve(w http.ResponseWriter, req *http.Request) {
+    r.mu.Lock()
+    r.requests = append(r.requests, strings.TrimSpace(req.Method+" "+req.URL.Path+" "+req.Header.Get("X-Amz-Target")))
+    r.mu.Unlock()
+    w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+    w.Write([]byte(`{}`))
+}
+
+func useEndpoints(t *testing.T, dynamo, s3URL string) {
+    t.Helper()
+    prevDynamo, prevS3 := dynamoEndpoint, s3Endpoint
+    dynamoEndpoint, s3Endpoint = dynamo, s3URL
+    t.Cleanup(func() { dynamoEndpoint, s3Endpoint = prevDynamo, prevS3 })
+}
+
+func testAWSConfig() aws.Config {
+    return aws.Config{
+        Region:      "us-east-1",
+        Credentials: credentials.NewStaticCredentialsProvider("test", "test", ""),
+    }
+}
+
+func TestClientsUseConfiguredEndpoints(t *testing.T) {
+    rec := &endpointRecorder{}
+    srv := httptest.NewServer(http.HandlerFunc(rec.serve))
+    t.Cleanup(srv.Close)
+    useEndpoints(t, srv.URL, srv.URL)
+    cfg := testAWSConfig()
+    ctx := context.Background()
+
+    if _, err := newDynamoClient(cfg).GetItem(ctx, &dynamodb.GetItemInput{
+        TableName: aws.String("orders"),
+        Key:       orderToItem(&Order{ID: "o1"}),
+    }); err != nil {
+        t.Fatalf("GetItem: %v", err)
+    }
+    // 경로 방식이므로 버킷 이름이 호스트가 아니라 경로에 들어감
+    if _, err := newS3Client(cfg).PutObject(ctx, &s3.PutObjectInput{
+        Bucket: aws.String("exports"),
+        Key:    aws.String("orders.json"),
+        Body:   strings.NewReader("{}"),
+    }); err != nil {
+        t.Fatalf("PutObject: %v", err)
+    }
+
+    want := []string{
+        "POST / DynamoDB_20120810.GetItem",
+        "PUT /exports/orders.json",
+    }
+    rec.mu.Lock()
+    defer rec.mu.Unlock()
+    if len(rec.requests) != len(want) {
+        t.Fatalf("requests %q, want %q", rec.requests, want)
+    }
+    for i := range want {
+        if rec.requests[i] != want[i] {
+            t.Errorf("request %d = %q, want %q", i, rec.requests[i], want[i])
+        }
+    }
+}
+
+// 엔드포인트가 없으면 AWS 기본 주소를 씀
+func TestClientsDefaultToAWS(t *testing.T) {
+    useEndpoints(t, "", "")
+    cfg := testAWSConfig()
+
+    if o := newDynamoClient(cfg).Options(); o.BaseEndpoint != nil {
+        t.Errorf("DynamoDB BaseEndpoint = %q, want unset", *o.BaseEndpoint)
+    }
+    if o := newS3Client(cfg).Options(); o.BaseEndpoint != nil || o.UsePathStyle {
+        t.Errorf("S3 BaseEndpoint = %v, UsePathStyle = %v, want unset", o.BaseEndpoint, o.UsePathStyle)
+    }
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "strings"
+    "time"
+
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// 응답 본문과 같은 JSON의 해시라 필드가 바뀌면 ETag도 바뀜
+func orderETag(order *Order) string {
+    data, err := json.Marshal(order)
+    if err != nil {
+        return ""
+    }
+    sum := sha256.Sum256(data)
+    return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// If-Match는 강한 비교를 쓰므로 W/ 태그는 맞지 않는 것으로 봄. *는 주문이 있으면 맞음
+func ifMatchSatisfied(header, etag string) bool {
+    for _, candidate := range strings.Split(header, ",") {
+        candidate = strings.TrimSpace(candidate)
+        if candidate == "*" || (etag != "" && candidate == etag) {
+            return true
+        }
+    }
+    return false
+}
+
+// 읽은 뒤 다른 요청이 주문을 바꾸지 않았는지 확인하는 조건. 쓰기마다 updatedat이 바뀌므로 버전으로 씀.
+// 타임스탬프가 생기기 전의 항목에는 updatedat이 없음
+func orderVersionCondition(existing *Order, values map[string]types.AttributeValue) string {
+    if existing.UpdatedAt.IsZero() {
+        return "attribute_not_exists(updatedat)"
+    }
+    values[":oldupdatedat"] = &types.AttributeValueMemberS{Value: existing.UpdatedAt.Format(time.RFC3339Nano)}
+    return "updatedat = :oldupdatedat"
+}
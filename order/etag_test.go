@@ -0,0 +1,140 @@
+package main
+
+import (
+    "net/http"
+    "strings"
+    "testing"
+
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+)
+
+const orderVersion = "2024-01-01T00:00:00Z"
+
+func versionedOrderItem() map[string]interface{} {
+    item := orderItem("o1", "alice", "p1", 1)
+    item["updatedat"] = map[string]string{"S": orderVersion}
+    return item
+}
+
+// 조건식 실패 응답. item이 있으면 ALL_OLD로 돌려받은 항목
+func conditionFailed(item map[string]interface{}) (int, interface{}) {
+    status, body := dynamoError("ConditionalCheckFailedException", "The conditional request failed")
+    if item != nil {
+        body.(map[string]interface{})["Item"] = item
+    }
+    return status, body
+}
+
+func TestUpdateOrderIfMatch(t *testing.T) {
+    useMiniredis(t)
+    fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        switch op {
+        case "GetItem":
+            return http.StatusOK, map[string]interface{}{"Item": versionedOrderItem()}
+        case "UpdateItem":
+            return http.StatusOK, map[string]interface{}{"Attributes": orderItem("o1", "alice", "p1", 2)}
+        }
+        return http.StatusOK, map[string]interface{}{}
+    })
+    router := newRouter()
+
+    w := doRequest(router, http.MethodGet, "/v1/order?id=o1", nil, nil)
+    etag := w.Header().Get("ETag")
+    if w.Code != http.StatusOK || etag == "" {
+        t.Fatalf("GET: %d, ETag %q (%s)", w.Code, etag, w.Body)
+    }
+
+    update := map[string]interface{}{"customerid": "alice", "productid": "p1", "quantity": 2}
+    for _, stale := range []string{`"0123456789abcdef"`, "W/" + etag} {
+        w = doRequest(router, http.MethodPut, "/v1/order/o1", update, map[string]string{"If-Match": stale})
+        if w.Code != http.StatusPreconditionFailed || !strings.Contains(w.Body.String(), respond.CodePreconditionFailed) {
+            t.Errorf("If-Match %s: %d %s, want 412", stale, w.Code, w.Body)
+        }
+    }
+    if n := len(fake.callsTo("UpdateItem")); n != 0 {
+        t.Fatalf("UpdateItem called %d times for stale If-Match, want 0", n)
+    }
+
+    w = doRequest(router, http.MethodPut, "/v1/order/o1", update, map[string]string{"If-Match": etag})
+    if w.Code != http.StatusOK {
+        t.Fatalf("matching If-Match: %d %s, want 200", w.Code, w.Body)
+    }
+    if got := w.Header().Get("ETag"); got == "" || got == etag {
+        t.Errorf("ETag after update %q, want a new value (was %q)", got, etag)
+    }
+
+    // 읽은 버전에 조건을 걸어 쓰므로 그 사이의 변경도 덮어쓰지 않음
+    call := fake.callsTo("UpdateItem")[0].Body
+    if cond, _ := call["ConditionExpression"].(string); !strings.Contains(cond, "updatedat = :oldupdatedat") {
+        t.Errorf("ConditionExpression %q, want version check", cond)
+    }
+    if got := attrS(call, "ExpressionAttributeValues", ":oldupdatedat"); got != orderVersion {
+        t.Errorf(":oldupdatedat = %q, want %q", got, orderVersion)
+    }
+}
+
+// 확인과 쓰기 사이에 다른 요청이 주문을 바꾸면 If-Match가 있을 때 412, 없을 때 409
+func TestUpdateOrderChangedAfterRead(t *testing.T) {
+    newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        switch op {
+        case "GetItem":
+            return http.StatusOK, map[string]interface{}{"Item": versionedOrderItem()}
+        case "UpdateItem":
+            return conditionFailed(orderItem("o1", "alice", "p1", 5))
+        }
+        return http.StatusOK, map[string]interface{}{}
+    })
+    router := newRouter()
+    update := map[string]interface{}{"customerid": "alice", "productid": "p1", "quantity": 2}
+
+    w := doRequest(router, http.MethodPut, "/v1/order/o1", update, map[string]string{"If-Match": "*"})
+    if w.Code != http.StatusPreconditionFailed {
+        t.Errorf("with If-Match: %d %s, want 412", w.Code, w.Body)
+    }
+    w = doRequest(router, http.MethodPut, "/v1/order/o1", update, nil)
+    if w.Code != http.StatusConflict {
+        t.Errorf("without If-Match: %d %s, want 409", w.Code, w.Body)
+    }
+}
+
+// 확인한 뒤 주문이 지워졌으면 404
+func TestUpdateOrderDeletedAfterRead(t *testing.T) {
+    newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        switch op {
+        case "GetItem":
+            return http.StatusOK, map[string]interface{}{"Item": versionedOrderItem()}
+        case "UpdateItem":
+            return conditionFailed(nil)
+        }
+        return http.StatusOK, map[string]interface{}{}
+    })
+
+    update := map[string]interface{}{"customerid": "alice", "productid": "p1", "quantity": 2}
+    w := doRequest(newRouter(), http.MethodPut, "/v1/order/o1", update, nil)
+    if w.Code != http.StatusNotFound {
+        t.Errorf("status %d, want 404 (%s)", w.Code, w.Body)
+    }
+}
+
+// 타임스탬프가 생기기 전의 항목은 updatedat이 없는 것을 조건으로 씀
+func TestUpdateOrderLegacyItemVersion(t *testing.T) {
+    fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        switch op {
+        case "GetItem":
+            return http.StatusOK, map[string]interface{}{"Item": orderItem("o1", "alice", "p1", 1)}
+        case "UpdateItem":
+            return http.StatusOK, map[string]interface{}{"Attributes": orderItem("o1", "alice", "p1", 2)}
+        }
+        return http.StatusOK, map[string]interface{}{}
+    })
+
+    update := map[string]interface{}{"customerid": "alice", "productid": "p1", "quantity": 2}
+    w := doRequest(newRouter(), http.MethodPut, "/v1/order/o1", update, nil)
+    if w.Code != http.StatusOK {
+        t.Fatalf("status %d, want 200 (%s)", w.Code, w.Body)
+    }
+    cond, _ := fake.callsTo("UpdateItem")[0].Body["ConditionExpression"].(string)
+    if !strings.Contains(cond, "attribute_not_exists(updatedat)") {
+        t.Errorf("ConditionExpression %q, want attribute_not_exists(updatedat)", cond)
+    }
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "log"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/aws/arn"
+    "github.com/aws/aws-sdk-go-v2/service/sns"
+    "github.com/aws/aws-sdk-go-v2/service/sqs"
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+    "github.com/gmstcl/eCommerce-System/internal/tracing"
+)
+
+const eventOrderCreated = "order.created"
+
+// ORDER_EVENTS_ARN에 SNS 토픽이나 SQS 큐의 ARN을 주면 주문이 저장된 뒤 이벤트를 보냄. 없으면 아무것도 하지 않음.
+// 발행 실패는 로그만 남기고 주문 생성 응답에는 영향을 주지 않음
+var (
+    orderEventsARN = os.Getenv("ORDER_EVENTS_ARN")
+    publishEvent   func(ctx context.Context, body string) error
+)
+
+type orderEvent struct {
+    Type      string    `json:"type"`
+    Timestamp time.Time `json:"timestamp"`
+    Order     *Order    `json:"order"`
+}
+
+func initOrderEvents(cfg aws.Config) {
+    if orderEventsARN == "" {
+        return
+    }
+    parsed, err := arn.Parse(orderEventsARN)
+    if err != nil {
+        log.Fatalf("invalid ORDER_EVENTS_ARN %q: %v", orderEventsARN, err)
+    }
+
+    switch parsed.Service {
+    case "sns":
+        client := sns.NewFromConfig(cfg, func(o *sns.Options) {
+            o.Region = parsed.Region
+        })
+        publishEvent = func(ctx context.Context, body string) error {
+            _, err := client.Publish(ctx, &sns.PublishInput{
+                TopicArn: aws.String(orderEventsARN),
+                Message:  aws.String(body),
+            })
+            return err
+        }
+    case "sqs":
+        client := sqs.NewFromConfig(cfg, func(o *sqs.Options) {
+            o.Region = parsed.Region
+        })
+        // SendMessage는 ARN이 아닌 큐 URL을 받으므로 ARN의 리전, 계정, 이름으로 만듦
+        queueURL := aws.String(fmt.Sprintf("https://sqs.%s.amazonaws.com/%s/%s", parsed.Region, parsed.AccountID, parsed.Resource))
+        publishEvent = func(ctx context.Context, body string) error {
+            _, err := client.SendMessage(ctx, &sqs.SendMessageInput{
+                QueueUrl:    queueURL,
+                MessageBody: aws.String(body),
+            })
+            return err
+        }
+    default:
+        log.Fatalf("ORDER_EVENTS_ARN must be an SNS topic or SQS queue ARN, got service %q", parsed.Service)
+    }
+    logger.Info("Order events enabled", "target", strings.ToUpper(parsed.Service), "arn", orderEventsARN)
+}
+
+func publishOrderCreated(ctx context.Context, order *Order) {
+    if publishEvent == nil {
+        return
+    }
+    ctx, span := tracing.Start(ctx, "publishOrderCreated", "order_id", order.ID)
+    defer span.End()
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    body, err := json.Marshal(orderEvent{Type: eventOrderCreated, Timestamp: clock.Now().UTC(), Order: order})
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to marshal order event", "order_id", order.ID, "error", err)
+        return
+    }
+    if err := publishEvent(ctx, string(body)); err != nil {
+        logger.ErrorContext(ctx, "Failed to publish order event", "order_id", order.ID, "event", eventOrderCreated, "error", err)
+        return
+    }
+    logger.InfoContext(ctx, "Published order event", "order_id", order.ID, "event", eventOrderCreated)
+}
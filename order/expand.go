@@ -0,0 +1,149 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "strings"
+    "sync"
+
+    "github.com/gin-gonic/gin"
+
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+    "github.com/gmstcl/eCommerce-System/order/clients"
+)
+
+const (
+    expandCustomer = "customer"
+    expandProduct  = "product"
+)
+
+// expand로 요청한 참조를 함께 담은 응답. 가져오지 못한 참조는 비워 두고 expand_errors에 이유를 남김
+type expandedOrder struct {
+    *Order
+    Customer     *clients.Customer `json:"customer,omitempty"`
+    Product      *clients.Product  `json:"product,omitempty"`
+    ExpandErrors map[string]string `json:"expand_errors,omitempty"`
+}
+
+func getOrderByID(c *gin.Context) {
+    serveOrder(c, c.Param("id"))
+}
+
+// expand=customer,product이면 다른 서비스에서 고객과 상품을 동시에 가져와 붙임.
+// 참조 조회가 실패해도 주문 자체는 200으로 반환함
+func serveOrder(c *gin.Context, orderID string) {
+    ctx := c.Request.Context()
+
+    expand, ok := parseExpand(c.Query("expand"))
+    if !ok {
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "expand must be a comma-separated list of customer, product")
+        return
+    }
+
+    orderData, err := loadOrder(ctx, orderID)
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to fetch order", "order_id", orderID, "error", err)
+        respond.BackendError(c, err, "failed to fetch order")
+        return
+    }
+    if orderData == nil {
+        respond.Error(c, http.StatusNotFound, respond.CodeNotFound, "order not found")
+        return
+    }
+    if !authorizeOrderRead(c, orderData) {
+        return
+    }
+
+    if len(expand) == 0 {
+        c.Header("ETag", orderETag(orderData))
+        respond.JSON(c, http.StatusOK, orderData)
+        return
+    }
+    respond.JSON(c, http.StatusOK, expandOrder(ctx, orderData, expand))
+}
+
+func parseExpand(v string) (map[string]bool, bool) {
+    expand := make(map[string]bool)
+    if v == "" {
+        return expand, true
+    }
+    for _, name := range strings.Split(v, ",") {
+        switch name = strings.TrimSpace(name); name {
+        case expandCustomer, expandProduct:
+            expand[name] = true
+        default:
+            return nil, false
+        }
+    }
+    return expand, true
+}
+
+func expandOrder(ctx context.Context, order *Order, expand map[string]bool) *expandedOrder {
+    out := &expandedOrder{Order: order}
+    var mu sync.Mutex
+    var wg sync.WaitGroup
+    fail := func(name string, err error) {
+        logger.WarnContext(ctx, "Failed to expand order reference", "order_id", order.ID, "expand", name, "error", err)
+        mu.Lock()
+        defer mu.Unlock()
+        if out.ExpandErrors == nil {
+            out.ExpandErrors = make(map[string]string)
+        }
+        out.ExpandErrors[name] = expandErrorMessage(name, err)
+    }
+
+    if expand[expandCustomer] {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            if !customerClient.Enabled() {
+                fail(expandCustomer, errExpandDisabled)
+                return
+            }
+            customer, err := customerClient.GetCustomer(ctx, order.CustomerID)
+            if err != nil {
+                fail(expandCustomer, err)
+                return
+            }
+            mu.Lock()
+            out.Customer = customer
+            mu.Unlock()
+        }()
+    }
+    if expand[expandProduct] {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            if !productClient.Enabled() {
+                fail(expandProduct, errExpandDisabled)
+                return
+            }
+            product, err := productClient.GetProduct(ctx, order.ProductID)
+            if err != nil {
+                fail(expandProduct, err)
+                return
+            }
+            mu.Lock()
+            out.Product = product
+            mu.Unlock()
+        }()
+    }
+    wg.Wait()
+    return out
+}
+
+var errExpandDisabled = errors.New("service URL not configured")
+
+func expandErrorMessage(name string, err error) string {
+    switch {
+    case errors.Is(err, errExpandDisabled):
+        return name + " service is not configured"
+    case errors.Is(err, clients.ErrNotFound):
+        return name + " not found"
+    case errors.Is(err, clients.ErrCircuitOpen):
+        return name + " service is temporarily unavailable"
+    default:
+        return "failed to fetch " + name
+    }
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/gmstcl/eCommerce-System/internal/requestid"
+    "github.com/gmstcl/eCommerce-System/order/clients"
+)
+
+// CUSTOMER_SERVICE_URL, PRODUCT_SERVICE_URL과 같은 방식으로 클라이언트를 주어진 주소에 연결함. 빈 주소면 비활성
+func useServiceClients(t *testing.T, customerURL, productURL string) {
+    t.Helper()
+    prevCustomer, prevProduct := customerClient, productClient
+    customerClient = clients.NewCustomerClient(clients.Config{BaseURL: customerURL, Timeout: time.Second, RequestID: requestid.From})
+    productClient = clients.NewProductClient(clients.Config{BaseURL: productURL, Timeout: time.Second, RequestID: requestid.From})
+    t.Cleanup(func() { customerClient, productClient = prevCustomer, prevProduct })
+}
+
+// known에 있는 id면 그 값을, 없으면 404를 돌려주는 서비스. 받은 X-Request-ID를 남김
+func newFakeService(t *testing.T, known map[string]interface{}, requestIDs *[]string) string {
+    t.Helper()
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        *requestIDs = append(*requestIDs, r.Header.Get(requestid.Header))
+        value, ok := known[r.URL.Query().Get("id")]
+        if !ok {
+            http.Error(w, `{"code":"not_found"}`, http.StatusNotFound)
+            return
+        }
+        json.NewEncoder(w).Encode(value)
+    }))
+    t.Cleanup(srv.Close)
+    return srv.URL
+}
+
+type expandResponse struct {
+    ID           string            `json:"id"`
+    Customer     *clients.Customer `json:"customer"`
+    Product      *clients.Product  `json:"product"`
+    ExpandErrors map[string]string `json:"expand_errors"`
+}
+
+func getExpanded(t *testing.T, path string) expandResponse {
+    t.Helper()
+    w := doRequest(newRouter(), http.MethodGet, path, nil, map[string]string{requestid.Header: "req-expand"})
+    if w.Code != http.StatusOK {
+        t.Fatalf("GET %s: status %d, want 200 (%s)", path, w.Code, w.Body)
+    }
+    var resp expandResponse
+    if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+        t.Fatal(err)
+    }
+    return resp
+}
+
+// 고객과 상품을 각 서비스에서 가져와 붙이고, 요청 ID를 그대로 넘김
+func TestExpandOrder(t *testing.T) {
+    newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        return http.StatusOK, map[string]interface{}{"Item": orderItem("o1", "alice", "p1", 1)}
+    })
+    var customerIDs, productIDs []string
+    useServiceClients(t,
+        newFakeService(t, map[string]interface{}{"alice": clients.Customer{ID: "alice", Name: "Alice", Gender: "female"}}, &customerIDs),
+        newFakeService(t, map[string]interface{}{"p1": clients.Product{ID: "p1", Name: "lamp", Category: "home"}}, &productIDs),
+    )
+
+    resp := getExpanded(t, "/v1/order/o1?expand=customer,product")
+    if resp.ID != "o1" || resp.Customer == nil || resp.Customer.Name != "Alice" || resp.Product == nil || resp.Product.Name != "lamp" || resp.ExpandErrors != nil {
+        t.Errorf("got %+v, want order o1 with Alice and lamp", resp)
+    }
+    if len(customerIDs) != 1 || customerIDs[0] != "req-expand" || len(productIDs) != 1 || productIDs[0] != "req-expand" {
+        t.Errorf("forwarded request ids customer %v product %v, want req-expand once each", customerIDs, productIDs)
+    }
+
+    customerIDs, productIDs = nil, nil
+    resp = getExpanded(t, "/v1/order/o1?expand=product")
+    if resp.Customer != nil || resp.Product == nil || len(customerIDs) != 0 {
+        t.Errorf("expand=product: got %+v and %d customer calls, want only the product", resp, len(customerIDs))
+    }
+}
+
+// 참조를 가져오지 못해도 주문은 200이고 이유를 expand_errors에 남김
+func TestExpandOrderPartialFailure(t *testing.T) {
+    newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        return http.StatusOK, map[string]interface{}{"Item": orderItem("o1", "alice", "gone", 1)}
+    })
+    var productIDs []string
+    useServiceClients(t, "", newFakeService(t, map[string]interface{}{}, &productIDs))
+
+    resp := getExpanded(t, "/v1/order/o1?expand=customer,product")
+    if resp.ID != "o1" || resp.Customer != nil || resp.Product != nil {
+        t.Errorf("got %+v, want the order without references", resp)
+    }
+    if resp.ExpandErrors["customer"] != "customer service is not configured" || resp.ExpandErrors["product"] != "product not found" {
+        t.Errorf("expand_errors %v, want customer not configured and product not found", resp.ExpandErrors)
+    }
+}
+
+func TestExpandOrderRejectsUnknownReference(t *testing.T) {
+    fake := newFakeDynamo(t, nil)
+
+    if w := doRequest(newRouter(), http.MethodGet, "/v1/order/o1?expand=customer,invoice", nil, nil); w.Code != http.StatusBadRequest {
+        t.Errorf("status %d, want 400 (%s)", w.Code, w.Body)
+    }
+    if calls := fake.callsTo("GetItem"); len(calls) != 0 {
+        t.Errorf("GetItem called %d times for a rejected expand", len(calls))
+    }
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "log"
+    "os"
+    "regexp"
+    "strings"
+    "time"
+
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/service/s3"
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+)
+
+// 내보내기마다 새 객체를 만들어 이전 결과를 보존함. 키는 접두사 + UTC 시각이라
+// 사전순 정렬이 곧 시간순이 되므로 가장 최근 내보내기를 목록에서 찾을 수 있음
+const exportKeyTimeFormat = "2006-01-02T15-04-05Z"
+
+// ORDERS_EXPORT_PREFIX로 바꿀 수 있고, 요청마다 ?prefix=로도 지정할 수 있음
+var ordersExportPrefix = "orders/"
+
+// 내보내기 직후 돌려주는 다운로드 URL의 유효 기간. SigV4 서명은 최대 7일까지 허용함
+var exportURLExpiry = 15 * time.Minute
+
+const maxExportURLExpiry = 7 * 24 * time.Hour
+
+func initExportURLExpiry() {
+    seconds := envInt("EXPORT_URL_EXPIRY_SECONDS", int(exportURLExpiry/time.Second))
+    expiry := time.Duration(seconds) * time.Second
+    if expiry <= 0 || expiry > maxExportURLExpiry {
+        log.Fatalf("invalid EXPORT_URL_EXPIRY_SECONDS %d (want 1 to %d)", seconds, int(maxExportURLExpiry/time.Second))
+    }
+    exportURLExpiry = expiry
+}
+
+var exportPrefixPattern = regexp.MustCompile(`^[A-Za-z0-9_.\-/]*$`)
+
+var errInvalidExportPrefix = errors.New("prefix may only contain letters, digits, '_', '-', '.' and '/', and must not start with '/' or contain '..'")
+
+func validateExportPrefix(prefix string) error {
+    if !exportPrefixPattern.MatchString(prefix) || strings.HasPrefix(prefix, "/") || strings.Contains(prefix, "..") {
+        return errInvalidExportPrefix
+    }
+    return nil
+}
+
+func newExportKey(prefix string) string {
+    return prefix + clock.Now().UTC().Format(exportKeyTimeFormat) + ".ndjson"
+}
+
+// 접두사 아래에 내보내기가 없으면 빈 문자열을 반환함
+func latestExportKey(ctx context.Context, prefix string) (string, error) {
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    latest := ""
+    paginator := s3.NewListObjectsV2Paginator(s3Client, &s3.ListObjectsV2Input{
+        Bucket: aws.String(s3AccessPointARN),
+        Prefix: aws.String(prefix),
+    })
+    for paginator.HasMorePages() {
+        page, err := paginator.NextPage(ctx)
+        if err != nil {
+            return "", err
+        }
+        for _, object := range page.Contents {
+            if key := aws.ToString(object.Key); strings.HasSuffix(key, ".ndjson") && key > latest {
+                latest = key
+            }
+        }
+    }
+    return latest, nil
+}
+
+// Bucket에 액세스 포인트 ARN을 넣으면 SDK가 액세스 포인트 호스트로 서명함.
+// ARN의 리전이 클라이언트 리전과 달라도 서명되도록 UseARNRegion을 켬
+func presignExportURL(ctx context.Context, objectKey string) (string, error) {
+    presigner := s3.NewPresignClient(s3Client, s3.WithPresignClientFromClientOptions(func(o *s3.Options) {
+        o.UseARNRegion = true
+    }))
+
+    req, err := presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+        Bucket: aws.String(s3AccessPointARN),
+        Key:    aws.String(objectKey),
+    }, s3.WithPresignExpires(exportURLExpiry))
+    if err != nil {
+        return "", err
+    }
+    return req.URL, nil
+}
+
+// EXPORT_INTERVAL(예: 1h)이 있으면 POST /v1/s3/order와 같은 경로로 주기적으로 내보냄.
+// 복제본마다 따로 돌기 때문에 여러 복제본이면 한 곳에서만 켜야 함.
+// 반환한 함수는 진행 중인 내보내기를 취소하고 고루틴이 끝날 때까지 기다림
+func startExportScheduler() func() {
+    v := os.Getenv("EXPORT_INTERVAL")
+    if v == "" {
+        return func() {}
+    }
+    interval, err := time.ParseDuration(v)
+    if err != nil || interval <= 0 {
+        log.Fatalf("invalid EXPORT_INTERVAL %q (want a positive duration such as 1h)", v)
+    }
+    logger.Info("Scheduled order export enabled", "interval", interval.String())
+
+    ctx, cancel := context.WithCancel(context.Background())
+    done := make(chan struct{})
+    go func() {
+        defer close(done)
+        ticker := time.NewTicker(interval)
+        defer ticker.Stop()
+        for {
+            select {
+            case <-ctx.Done():
+                return
+            case <-ticker.C:
+                runScheduledExport(ctx)
+            }
+        }
+    }()
+
+    return func() {
+        cancel()
+        <-done
+    }
+}
+
+func runScheduledExport(ctx context.Context) {
+    objectKey := newExportKey(ordersExportPrefix)
+    start := time.Now()
+    count, err := exportOrders(ctx, objectKey)
+    if err != nil {
+        logger.Error("Scheduled order export failed", "key", objectKey, "error", err)
+        return
+    }
+    logger.Info("Scheduled order export finished", "key", objectKey, "count", count, "duration", time.Since(start).String())
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+    "encoding/json"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "net/url"
+    "strings"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+)
+
+// 경로 방식 S3 요청 중 PutObject만 흉내 내고 받은 객체를 키별로 남김
+type fakeS3 struct {
+    mu      sync.Mutex
+    objects map[string]s3Object
+}
+
+type s3Object struct {
+    ContentType string
+    Body        string
+}
+
+// S3_ENDPOINT와 같은 방식으로 s3Client와 s3Uploader를 httptest 서버에 연결함. 버킷은 exports
+func newFakeS3(t *testing.T) *fakeS3 {
+    t.Helper()
+    f := &fakeS3{objects: map[string]s3Object{}}
+    srv := httptest.NewServer(http.HandlerFunc(f.serve))
+    t.Cleanup(srv.Close)
+
+    useEndpoints(t, dynamoEndpoint, srv.URL)
+    prevClient, prevUploader, prevBucket := s3Client, s3Uploader, s3AccessPointARN
+    s3Client = newS3Client(testAWSConfig())
+    s3Uploader = manager.NewUploader(s3Client)
+    s3AccessPointARN = "exports"
+    t.Cleanup(func() { s3Client, s3Uploader, s3AccessPointARN = prevClient, prevUploader, prevBucket })
+    return f
+}
+
+func (f *fakeS3) serve(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPut {
+        http.Error(w, "unsupported", http.StatusNotImplemented)
+        return
+    }
+    body, _ := io.ReadAll(r.Body)
+    f.mu.Lock()
+    f.objects[strings.TrimPrefix(r.URL.Path, "/exports/")] = s3Object{ContentType: r.Header.Get("Content-Type"), Body: string(body)}
+    f.mu.Unlock()
+    w.Header().Set("ETag", `"etag"`)
+}
+
+func (f *fakeS3) keys() []string {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    var keys []string
+    for key := range f.objects {
+        keys = append(keys, key)
+    }
+    return keys
+}
+
+// 스캔 페이지를 이어 한 줄에 주문 하나씩 쓰고, 줄마다 주문 하나로 다시 읽힘
+func TestExportWritesNDJSON(t *testing.T) {
+    newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        if attrS(body, "ExclusiveStartKey", "id") == "" {
+            return http.StatusOK, map[string]interface{}{
+                "Items":            []interface{}{orderItem("o1", "alice", "p1", 1), orderItem("o2", "bob", "p2", 2)},
+                "LastEvaluatedKey": dynamoItem(map[string]interface{}{"id": "o2"}),
+            }
+        }
+        return http.StatusOK, map[string]interface{}{"Items": []interface{}{orderItem("o3", "carol", "p3", 3)}}
+    })
+    s3 := newFakeS3(t)
+
+    w := doRequest(newRouter(), http.MethodPost, "/v1/s3/order", nil, nil)
+    if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"count":3`) {
+        t.Fatalf("status %d body %s, want 200 with count 3", w.Code, w.Body)
+    }
+    keys := s3.keys()
+    if len(keys) != 1 {
+        t.Fatalf("uploaded %v, want one object", keys)
+    }
+    object := s3.objects[keys[0]]
+    if object.ContentType != "application/x-ndjson" {
+        t.Errorf("Content-Type %q, want application/x-ndjson", object.ContentType)
+    }
+
+    lines := strings.Split(strings.TrimSuffix(object.Body, "\n"), "\n")
+    if len(lines) != 3 {
+        t.Fatalf("body has %d lines, want 3:\n%s", len(lines), object.Body)
+    }
+    for i, line := range lines {
+        var order Order
+        if err := json.Unmarshal([]byte(line), &order); err != nil || order.ID != []string{"o1", "o2", "o3"}[i] {
+            t.Errorf("line %d = %s (%v), want order o%d", i+1, line, err, i+1)
+        }
+    }
+
+    orders, err := decodeOrdersExport(strings.NewReader(object.Body))
+    if err != nil || len(orders) != 3 {
+        t.Errorf("decode = %d orders, %v, want 3", len(orders), err)
+    }
+}
+
+// 예전 형식(JSON 배열 하나)의 내보내기도 같은 주문 목록으로 읽힘
+func TestDecodeOrdersExportReadsLegacyArray(t *testing.T) {
+    orders, err := decodeOrdersExport(strings.NewReader(` [{"id":"o1","quantity":1},{"id":"o2","quantity":2}]`))
+    if err != nil || len(orders) != 2 || orders[1].ID != "o2" {
+        t.Errorf("decode = %+v, %v, want o1 and o2", orders, err)
+    }
+}
+
+// 키는 접두사 + UTC 시각 + .ndjson이고, ?prefix=가 설정된 접두사보다 우선함
+func TestExportKeyFormat(t *testing.T) {
+    newFakeDynamo(t, nil)
+    s3 := newFakeS3(t)
+    t.Cleanup(clock.Set(clock.NewFake(time.Date(2024, 3, 5, 14, 7, 9, 0, time.FixedZone("KST", 9*60*60)))))
+    prev := ordersExportPrefix
+    ordersExportPrefix = "exports/orders/"
+    t.Cleanup(func() { ordersExportPrefix = prev })
+    router := newRouter()
+
+    tests := []struct {
+        query, key string
+    }{
+        {"", "exports/orders/2024-03-05T05-07-09Z.ndjson"},
+        {"?prefix=nightly/", "nightly/2024-03-05T05-07-09Z.ndjson"},
+    }
+    for _, tt := range tests {
+        w := doRequest(router, http.MethodPost, "/v1/s3/order"+tt.query, nil, nil)
+        if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"key":"`+tt.key+`"`) {
+            t.Errorf("POST %q: status %d body %s, want key %s", tt.query, w.Code, w.Body, tt.key)
+        }
+        if _, ok := s3.objects[tt.key]; !ok {
+            t.Errorf("POST %q: uploaded %v, want %s", tt.query, s3.keys(), tt.key)
+        }
+    }
+}
+
+// 절대 경로, 상위 경로, 허용하지 않는 문자가 든 접두사는 업로드 전에 400
+func TestExportRejectsInvalidPrefix(t *testing.T) {
+    fake := newFakeDynamo(t, nil)
+    s3 := newFakeS3(t)
+    router := newRouter()
+
+    for _, prefix := range []string{"/abs", "a/../b", "a b", "a?b"} {
+        w := doRequest(router, http.MethodPost, "/v1/s3/order?prefix="+url.QueryEscape(prefix), nil, nil)
+        if w.Code != http.StatusBadRequest {
+            t.Errorf("prefix %q: status %d, want 400 (%s)", prefix, w.Code, w.Body)
+        }
+    }
+    if calls := fake.callsTo("Scan"); len(calls) != 0 || len(s3.keys()) != 0 {
+        t.Errorf("Scan called %d times and uploaded %v for rejected prefixes", len(calls), s3.keys())
+    }
+}
+
+// 업로드한 객체를 가리키는 GetObject URL을 EXPORT_URL_EXPIRY_SECONDS 동안 유효하게 서명함
+func TestExportReturnsPresignedURL(t *testing.T) {
+    newFakeDynamo(t, nil)
+    newFakeS3(t)
+    now := time.Date(2024, 3, 5, 14, 7, 9, 0, time.UTC)
+    t.Cleanup(clock.Set(clock.NewFake(now)))
+    prev := exportURLExpiry
+    t.Cleanup(func() { exportURLExpiry = prev })
+    t.Setenv("EXPORT_URL_EXPIRY_SECONDS", "600")
+    initExportURLExpiry()
+
+    w := doRequest(newRouter(), http.MethodPost, "/v1/s3/order", nil, nil)
+    if w.Code != http.StatusOK {
+        t.Fatalf("status %d, want 200 (%s)", w.Code, w.Body)
+    }
+    var resp struct {
+        Key         string    `json:"key"`
+        DownloadURL string    `json:"download_url"`
+        ExpiresAt   time.Time `json:"expires_at"`
+    }
+    if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+        t.Fatal(err)
+    }
+
+    u, err := url.Parse(resp.DownloadURL)
+    if err != nil {
+        t.Fatalf("download_url %q: %v", resp.DownloadURL, err)
+    }
+    q := u.Query()
+    if u.Path != "/exports/"+resp.Key || q.Get("x-id") != "GetObject" || q.Get("X-Amz-Expires") != "600" || q.Get("X-Amz-Signature") == "" {
+        t.Errorf("download_url %s, want signed GetObject of %s valid for 600s", resp.DownloadURL, resp.Key)
+    }
+    if !resp.ExpiresAt.Equal(now.Add(10 * time.Minute)) {
+        t.Errorf("expires_at %v, want %v", resp.ExpiresAt, now.Add(10*time.Minute))
+    }
+}
+
+// EXPORT_INTERVAL마다 내보내고, 멈춘 뒤에는 더 내보내지 않음
+func TestExportSchedulerRunsOnTicker(t *testing.T) {
+    fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        return http.StatusOK, map[string]interface{}{"Items": []interface{}{orderItem("o1", "alice", "p1", 1)}}
+    })
+    s3 := newFakeS3(t)
+    t.Setenv("EXPORT_INTERVAL", "10ms")
+
+    stop := startExportScheduler()
+    deadline := time.Now().Add(5 * time.Second)
+    for len(fake.callsTo("Scan")) < 2 {
+        if time.Now().After(deadline) {
+            stop()
+            t.Fatalf("Scan called %d times within 5s, want at least 2", len(fake.callsTo("Scan")))
+        }
+        time.Sleep(5 * time.Millisecond)
+    }
+    stop()
+
+    scans := len(fake.callsTo("Scan"))
+    time.Sleep(50 * time.Millisecond)
+    if got := len(fake.callsTo("Scan")); got != scans {
+        t.Errorf("Scan called %d more times after stop", got-scans)
+    }
+    for _, key := range s3.keys() {
+        if !strings.HasPrefix(key, ordersExportPrefix) || !strings.HasSuffix(key, ".ndjson") {
+            t.Errorf("scheduled export uploaded %s, want %s*.ndjson", key, ordersExportPrefix)
+        }
+    }
+    if len(s3.keys()) == 0 {
+        t.Error("scheduled export uploaded nothing")
+    }
+}
+
+// EXPORT_INTERVAL이 없으면 아무것도 시작하지 않음
+func TestExportSchedulerDisabled(t *testing.T) {
+    fake := newFakeDynamo(t, nil)
+    t.Setenv("EXPORT_INTERVAL", "")
+
+    stop := startExportScheduler()
+    time.Sleep(20 * time.Millisecond)
+    stop()
+    if calls := fake.callsTo(""); len(calls) != 0 {
+        t.Errorf("DynamoDB called %d times with the scheduler disabled", len(calls))
+    }
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "strings"
+    "testing"
+
+    "github.com/gmstcl/eCommerce-System/internal/healthz"
+)
+
+type healthResponse struct {
+    Status       string            `json:"status"`
+    Dependencies map[string]string `json:"dependencies"`
+}
+
+func TestHealthz(t *testing.T) {
+    tests := []struct {
+        name       string
+        dynamoDown bool
+        redisDown  bool
+        code       int
+        status     string
+    }{
+        {"all up", false, false, http.StatusOK, "ok"},
+        {"redis down", false, true, http.StatusOK, "degraded"},
+        {"dynamodb down", true, false, http.StatusServiceUnavailable, "unavailable"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+                if tt.dynamoDown {
+                    return dynamoError("ResourceNotFoundException", "Requested resource not found: Table: orders-internal-name")
+                }
+                return http.StatusOK, map[string]interface{}{"Table": map[string]interface{}{}}
+            })
+            mr := useMiniredis(t)
+            prev, hadRedis := healthChecks["redis"]
+            healthChecks["redis"] = healthz.Check{Run: func(ctx context.Context) error {
+                return redisClient.Ping(ctx).Err()
+            }}
+            t.Cleanup(func() {
+                if hadRedis {
+                    healthChecks["redis"] = prev
+                } else {
+                    delete(healthChecks, "redis")
+                }
+            })
+            redisAddr := mr.Addr()
+            if tt.redisDown {
+                mr.Close()
+            }
+
+            w := doRequest(newRouter(), http.MethodGet, "/healthz", nil, nil)
+            if w.Code != tt.code {
+                t.Fatalf("status %d, want %d (%s)", w.Code, tt.code, w.Body)
+            }
+            var resp healthResponse
+            if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+                t.Fatal(err)
+            }
+            if resp.Status != tt.status {
+                t.Errorf("status %q, want %q", resp.Status, tt.status)
+            }
+            for name, status := range resp.Dependencies {
+                if status != "ok" && status != "error" {
+                    t.Errorf("dependency %s reported %q, want ok or error", name, status)
+                }
+            }
+            if strings.Contains(w.Body.String(), "orders-internal-name") || strings.Contains(w.Body.String(), redisAddr) {
+                t.Errorf("response exposes backend error details: %s", w.Body)
+            }
+        })
+    }
+}
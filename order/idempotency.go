@@ -0,0 +1,108 @@
+package main
+
+import (
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "net/http"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+    "github.com/go-redis/redis/v8"
+)
+
+const (
+    idempotencyHeader = "Idempotency-Key"
+    maxIdempotencyKey = 255
+    // 처리 중 표시의 유효 시간. 처리 도중 프로세스가 죽어도 이 시간이 지나면 같은 키로 다시 보낼 수 있음
+    idempotencyPendingTTL = 30 * time.Second
+    idempotencyPending    = "pending"
+)
+
+// 같은 Idempotency-Key로 다시 보낸 생성 요청에는 처음 만든 주문의 결과를 그대로 돌려줌.
+// 키는 호출자(auditActor)별로 구분하며 캐시(Redis)가 꺼져 있으면 헤더를 무시함
+var idempotencyTTL = time.Duration(envInt("IDEMPOTENCY_TTL_SECONDS", 86400)) * time.Second
+
+type idempotentRequest struct {
+    key  string
+    done bool
+}
+
+// 새 요청이면 처리 중으로 표시하고 반환함. 이미 끝난 요청이면 저장된 결과를, 처리 중이면 409를
+// 응답한 뒤 nil, false를 반환함. 헤더가 없거나 캐시가 꺼져 있으면 nil, true
+func beginIdempotentRequest(c *gin.Context) (*idempotentRequest, bool) {
+    header := c.GetHeader(idempotencyHeader)
+    if header == "" || redisClient == nil {
+        return nil, true
+    }
+    if len(header) > maxIdempotencyKey {
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "Idempotency-Key must be at most 255 characters")
+        return nil, false
+    }
+
+    ctx, cancel := backend.WithTimeout(c.Request.Context())
+    defer cancel()
+
+    key := idempotencyCacheKey(c, header)
+    acquired, err := redisClient.SetNX(ctx, key, idempotencyPending, idempotencyPendingTTL).Result()
+    if err != nil {
+        // Redis 장애로 생성 자체를 막지는 않음
+        logger.WarnContext(ctx, "Idempotency check failed, processing without it", "error", err)
+        return nil, true
+    }
+    if acquired {
+        return &idempotentRequest{key: key}, true
+    }
+
+    orderID, err := redisClient.Get(ctx, key).Result()
+    switch {
+    case err == redis.Nil:
+        // 처리 중 표시가 방금 만료되었거나 실패로 지워진 경우. 클라이언트가 다시 보내면 새로 처리됨
+        respond.Error(c, http.StatusConflict, respond.CodeConflict, "previous attempt with this Idempotency-Key failed, retry the request")
+    case err != nil:
+        logger.ErrorContext(ctx, "Failed to read idempotency record", "error", err)
+        respond.BackendError(c, err, "failed to check Idempotency-Key")
+    case orderID == idempotencyPending:
+        respond.Error(c, http.StatusConflict, respond.CodeConflict, "request with this Idempotency-Key is still in progress")
+    default:
+        c.Header("Idempotent-Replayed", "true")
+        respond.JSON(c, http.StatusCreated, gin.H{"message": "Order created successfully", "id": orderID})
+    }
+    return nil, false
+}
+
+// 생성에 성공하면 주문 id를 결과로 저장함
+func (r *idempotentRequest) complete(ctx context.Context, orderID string) {
+    if r == nil {
+        return
+    }
+    r.done = true
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := redisClient.Set(ctx, r.key, orderID, idempotencyTTL).Err(); err != nil {
+        logger.ErrorContext(ctx, "Failed to save idempotency record", "order_id", orderID, "error", err)
+    }
+}
+
+// 생성하지 못했으면 처리 중 표시를 지워 같은 키로 다시 시도할 수 있게 함. defer로 호출함
+func (r *idempotentRequest) release(ctx context.Context) {
+    if r == nil || r.done {
+        return
+    }
+    ctx, cancel := backend.WithTimeout(context.WithoutCancel(ctx))
+    defer cancel()
+
+    if err := redisClient.Del(ctx, r.key).Err(); err != nil {
+        logger.ErrorContext(ctx, "Failed to release idempotency key", "error", err)
+    }
+}
+
+// 헤더 값은 그대로 키에 쓰지 않고 해시함
+func idempotencyCacheKey(c *gin.Context, header string) string {
+    scope := auditActor(c)
+    sum := sha256.Sum256([]byte(header))
+    return "idempotency:" + scope + ":" + hex.EncodeToString(sum[:16])
+}
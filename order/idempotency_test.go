@@ -0,0 +1,107 @@
+package main
+
+import (
+    "encoding/json"
+    "net/http"
+    "sync"
+    "testing"
+    "time"
+)
+
+func orderRequest() map[string]interface{} {
+    return map[string]interface{}{"customerid": "alice", "productid": "p1", "quantity": 1}
+}
+
+func createdOrderID(t *testing.T, body []byte) string {
+    t.Helper()
+    var resp struct {
+        ID string `json:"id"`
+    }
+    if err := json.Unmarshal(body, &resp); err != nil {
+        t.Fatal(err)
+    }
+    return resp.ID
+}
+
+// 감사 기록을 뺀 주문 테이블 PutItem 수
+func orderPuts(fake *fakeDynamo) int {
+    n := 0
+    for _, call := range fake.callsTo("PutItem") {
+        if call.Body["TableName"] == orderTable {
+            n++
+        }
+    }
+    return n
+}
+
+// 같은 키로 다시 보내면 새 주문을 만들지 않고 처음 만든 주문 id를 돌려줌
+func TestIdempotencyKeyReplay(t *testing.T) {
+    useMiniredis(t)
+    prev := orderIDStrategy
+    orderIDStrategy = orderIDUUID
+    t.Cleanup(func() { orderIDStrategy = prev })
+    fake := newFakeDynamo(t, nil)
+    router := newRouter()
+    headers := map[string]string{idempotencyHeader: "key-1"}
+
+    first := doRequest(router, http.MethodPost, "/v1/order", orderRequest(), headers)
+    if first.Code != http.StatusCreated {
+        t.Fatalf("first: status %d, want 201 (%s)", first.Code, first.Body)
+    }
+    replay := doRequest(router, http.MethodPost, "/v1/order", orderRequest(), headers)
+    if replay.Code != http.StatusCreated || replay.Header().Get("Idempotent-Replayed") != "true" {
+        t.Fatalf("replay: status %d, replayed %q", replay.Code, replay.Header().Get("Idempotent-Replayed"))
+    }
+    if a, b := createdOrderID(t, first.Body.Bytes()), createdOrderID(t, replay.Body.Bytes()); a == "" || a != b {
+        t.Errorf("replay returned id %q, want %q", b, a)
+    }
+    if n := orderPuts(fake); n != 1 {
+        t.Errorf("PutItem called %d times, want 1", n)
+    }
+
+    other := doRequest(router, http.MethodPost, "/v1/order", orderRequest(), map[string]string{idempotencyHeader: "key-2"})
+    if other.Code != http.StatusCreated || createdOrderID(t, other.Body.Bytes()) == createdOrderID(t, first.Body.Bytes()) {
+        t.Errorf("different key: status %d %s, want a new order", other.Code, other.Body)
+    }
+}
+
+// 같은 키로 동시에 보내면 하나만 주문을 만들고 나머지는 처리 중이라는 409
+func TestIdempotencyKeyConcurrentSubmission(t *testing.T) {
+    useMiniredis(t)
+    fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        if op == "PutItem" {
+            time.Sleep(100 * time.Millisecond)
+        }
+        return http.StatusOK, map[string]interface{}{}
+    })
+    router := newRouter()
+    body := orderRequest()
+    body["id"] = "o1"
+
+    var wg sync.WaitGroup
+    codes := make([]int, 5)
+    for i := range codes {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            codes[i] = doRequest(router, http.MethodPost, "/v1/order", body, map[string]string{idempotencyHeader: "key-1"}).Code
+        }(i)
+    }
+    wg.Wait()
+
+    created, conflicts := 0, 0
+    for _, code := range codes {
+        switch code {
+        case http.StatusCreated:
+            created++
+        case http.StatusConflict:
+            conflicts++
+        }
+    }
+    if created != 1 || conflicts != 4 {
+        t.Errorf("statuses %v, want one 201 and four 409", codes)
+    }
+    if n := orderPuts(fake); n != 1 {
+        t.Errorf("PutItem called %d times, want 1", n)
+    }
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "os"
+    "strconv"
+    "time"
+
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// 상품 데이터는 MySQL에 있어 DynamoDB 트랜잭션에 넣을 수 없으므로 재고는 별도 DynamoDB 테이블에 둠.
+// 이 테이블이 재고의 유일한 원본이며 product 서비스도 같은 테이블을 읽고 조정함(product/inventory.go).
+// 파티션 키는 productid(S), 재고 수량은 inventory(N). INVENTORY_TABLE이 없으면 재고를 차감하지 않음.
+// 주문 생성, 변경, 삭제가 모두 주문 쓰기와 재고 조정을 한 트랜잭션으로 처리함
+var inventoryTable = os.Getenv("INVENTORY_TABLE")
+
+var (
+    errOutOfStock = errors.New("insufficient inventory")
+    // 읽은 뒤 다른 요청이 주문의 상품이나 수량을 바꿨거나 주문을 지움
+    errOrderChanged = errors.New("order changed concurrently")
+)
+
+// 주문 저장과 재고 차감을 TransactWriteItems 한 번으로 처리해 하나라도 조건에 걸리면 둘 다 취소됨.
+// 재고 항목이 없는 상품도 재고 부족으로 봄
+func putOrderWithInventory(ctx context.Context, order *Order) error {
+    _, err := dynamoClient.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+        TransactItems: []types.TransactWriteItem{
+            {
+                Put: &types.Put{
+                    TableName:           aws.String(orderTable),
+                    Item:                orderToItem(order),
+                    ConditionExpression: aws.String("attribute_not_exists(id)"),
+                },
+            },
+            takeInventory(order.ProductID, order.Quantity),
+        },
+    })
+
+    var canceledErr *types.TransactionCanceledException
+    if errors.As(err, &canceledErr) {
+        // CancellationReasons는 TransactItems와 같은 순서로 옴
+        reasons := canceledErr.CancellationReasons
+        if len(reasons) > 0 && aws.ToString(reasons[0].Code) == "ConditionalCheckFailed" {
+            return errOrderExists
+        }
+        if inventoryConditionFailed(reasons) {
+            logger.InfoContext(ctx, "Order rejected for insufficient inventory", "order_id", order.ID, "product_id", order.ProductID, "quantity", order.Quantity)
+            return errOutOfStock
+        }
+    }
+    return err
+}
+
+// 기존 주문과의 차이만큼 재고를 조정함. 수량을 늘리면 늘린 만큼 차감하고, 줄이면 돌려주고,
+// 상품을 바꾸면 이전 상품에 전량을 돌려주고 새 상품에서 전량을 차감함.
+// 주문 쪽에는 읽은 상품, 수량, 버전이 그대로인지 조건을 걸어 차이를 잘못 계산하지 않게 함
+func updateOrderWithInventory(ctx context.Context, existing, order *Order) error {
+    update := orderUpdate(order)
+    update.ConditionExpression = aws.String("productid = :oldproductid AND quantity = :oldquantity AND " +
+        orderVersionCondition(existing, update.ExpressionAttributeValues))
+    update.ExpressionAttributeValues[":oldproductid"] = &types.AttributeValueMemberS{Value: existing.ProductID}
+    update.ExpressionAttributeValues[":oldquantity"] = &types.AttributeValueMemberN{Value: strconv.Itoa(existing.Quantity)}
+
+    items := []types.TransactWriteItem{{Update: update}}
+    switch delta := order.Quantity - existing.Quantity; {
+    case order.ProductID != existing.ProductID:
+        items = append(items, returnInventory(existing.ProductID, existing.Quantity), takeInventory(order.ProductID, order.Quantity))
+    case delta > 0:
+        items = append(items, takeInventory(order.ProductID, delta))
+    case delta < 0:
+        items = append(items, returnInventory(order.ProductID, -delta))
+    }
+
+    err := transactOrderChange(ctx, items)
+    if errors.Is(err, errOutOfStock) {
+        logger.InfoContext(ctx, "Order update rejected for insufficient inventory", "order_id", order.ID, "product_id", order.ProductID, "quantity", order.Quantity)
+    }
+    return err
+}
+
+// 주문을 지우고 수량을 재고에 돌려줌
+func deleteOrderWithInventory(ctx context.Context, existing *Order) error {
+    return transactOrderChange(ctx, []types.TransactWriteItem{
+        {
+            Delete: &types.Delete{
+                TableName:           aws.String(orderTable),
+                Key:                 orderKey(existing.ID),
+                ConditionExpression: aws.String("productid = :productid AND quantity = :quantity"),
+                ExpressionAttributeValues: map[string]types.AttributeValue{
+                    ":productid": &types.AttributeValueMemberS{Value: existing.ProductID},
+                    ":quantity":  &types.AttributeValueMemberN{Value: strconv.Itoa(existing.Quantity)},
+                },
+            },
+        },
+        returnInventory(existing.ProductID, existing.Quantity),
+    })
+}
+
+// 첫 항목이 주문 쓰기인 트랜잭션을 실행하고 취소 이유를 주문 오류로 바꿈
+func transactOrderChange(ctx context.Context, items []types.TransactWriteItem) error {
+    _, err := dynamoClient.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+        TransactItems: items,
+    })
+
+    var canceledErr *types.TransactionCanceledException
+    if errors.As(err, &canceledErr) {
+        reasons := canceledErr.CancellationReasons
+        if len(reasons) > 0 && aws.ToString(reasons[0].Code) == "ConditionalCheckFailed" {
+            return errOrderChanged
+        }
+        if inventoryConditionFailed(reasons) {
+            return errOutOfStock
+        }
+    }
+    return err
+}
+
+// 재고 항목 중 조건이 있는 것은 차감뿐이므로 주문 뒤의 항목이 조건에 걸렸으면 재고 부족임
+func inventoryConditionFailed(reasons []types.CancellationReason) bool {
+    for i := 1; i < len(reasons); i++ {
+        if aws.ToString(reasons[i].Code) == "ConditionalCheckFailed" {
+            return true
+        }
+    }
+    return false
+}
+
+func takeInventory(productID string, quantity int) types.TransactWriteItem {
+    return types.TransactWriteItem{
+        Update: &types.Update{
+            TableName:           aws.String(inventoryTable),
+            Key:                 inventoryKey(productID),
+            ConditionExpression: aws.String("inventory >= :quantity"),
+            UpdateExpression:    aws.String("SET inventory = inventory - :quantity"),
+            ExpressionAttributeValues: map[string]types.AttributeValue{
+                ":quantity": &types.AttributeValueMemberN{Value: strconv.Itoa(quantity)},
+            },
+        },
+    }
+}
+
+// ADD는 항목이 없으면 만들어 주므로 그 사이 재고 항목이 지워졌어도 돌려준 수량을 잃지 않음
+func returnInventory(productID string, quantity int) types.TransactWriteItem {
+    return types.TransactWriteItem{
+        Update: &types.Update{
+            TableName:        aws.String(inventoryTable),
+            Key:              inventoryKey(productID),
+            UpdateExpression: aws.String("ADD inventory :quantity"),
+            ExpressionAttributeValues: map[string]types.AttributeValue{
+                ":quantity": &types.AttributeValueMemberN{Value: strconv.Itoa(quantity)},
+            },
+        },
+    }
+}
+
+func inventoryKey(productID string) map[string]types.AttributeValue {
+    return map[string]types.AttributeValue{
+        "productid": &types.AttributeValueMemberS{Value: productID},
+    }
+}
+
+// 주문 변경 요청. 존재 확인 같은 조건은 호출하는 쪽이 붙임
+func orderUpdate(order *Order) *types.Update {
+    return &types.Update{
+        TableName:        aws.String(orderTable),
+        Key:              orderKey(order.ID),
+        UpdateExpression: aws.String("SET customerid = :customerid, productid = :productid, quantity = :quantity, unitprice = :unitprice, updatedat = :updatedat"),
+        ExpressionAttributeValues: map[string]types.AttributeValue{
+            ":customerid": &types.AttributeValueMemberS{Value: order.CustomerID},
+            ":productid":  &types.AttributeValueMemberS{Value: order.ProductID},
+            ":quantity":   &types.AttributeValueMemberN{Value: strconv.Itoa(order.Quantity)},
+            ":unitprice":  &types.AttributeValueMemberN{Value: strconv.FormatFloat(order.UnitPrice, 'f', -1, 64)},
+            ":updatedat":  &types.AttributeValueMemberS{Value: order.UpdatedAt.Format(time.RFC3339Nano)},
+        },
+    }
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+    "net/http"
+    "testing"
+)
+
+func useInventoryTable(t *testing.T) {
+    t.Helper()
+    prev := inventoryTable
+    inventoryTable = "inventory"
+    t.Cleanup(func() { inventoryTable = prev })
+}
+
+// 트랜잭션의 재고 조정을 "상품:식" 형태로 요약함. 예: "p1:-2", "p2:+1"
+func inventoryChanges(t *testing.T, body map[string]interface{}) []string {
+    t.Helper()
+    var changes []string
+    items, _ := body["TransactItems"].([]interface{})
+    for _, item := range items {
+        update, _ := item.(map[string]interface{})["Update"].(map[string]interface{})
+        if update == nil || update["TableName"] != "inventory" {
+            continue
+        }
+        values, _ := update["ExpressionAttributeValues"].(map[string]interface{})
+        quantity, _ := values[":quantity"].(map[string]interface{})["N"].(string)
+        sign := "+"
+        if update["ConditionExpression"] == "inventory >= :quantity" {
+            sign = "-"
+        }
+        changes = append(changes, attrS(update, "Key", "productid")+":"+sign+quantity)
+    }
+    return changes
+}
+
+func equalStrings(a, b []string) bool {
+    if len(a) != len(b) {
+        return false
+    }
+    for i := range a {
+        if a[i] != b[i] {
+            return false
+        }
+    }
+    return true
+}
+
+// 재고가 모자라면 주문 저장도 함께 취소되어 409이며 캐시, 감사 기록, 주문 PutItem이 남지 않음
+func TestCreateOrderOutOfStockRollsBack(t *testing.T) {
+    useInventoryTable(t)
+    mr := useMiniredis(t)
+    fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        if op == "TransactWriteItems" {
+            return transactionCanceled("None", "ConditionalCheckFailed")
+        }
+        return http.StatusOK, map[string]interface{}{}
+    })
+
+    body := orderRequest()
+    body["id"] = "o1"
+    w := doRequest(newRouter(), http.MethodPost, "/v1/order", body, nil)
+    if w.Code != http.StatusConflict {
+        t.Fatalf("status %d, want 409 (%s)", w.Code, w.Body)
+    }
+
+    calls := fake.callsTo("TransactWriteItems")
+    if len(calls) != 1 {
+        t.Fatalf("TransactWriteItems called %d times, want 1", len(calls))
+    }
+    if got := inventoryChanges(t, calls[0].Body); !equalStrings(got, []string{"p1:-1"}) {
+        t.Errorf("inventory changes %v, want [p1:-1]", got)
+    }
+    if calls := fake.callsTo("PutItem"); len(calls) != 0 {
+        t.Errorf("PutItem called %d times after a rolled back order", len(calls))
+    }
+    if keys := mr.Keys(); len(keys) != 0 {
+        t.Errorf("cache has %v after a rolled back order", keys)
+    }
+}
+
+// 변경은 기존 주문과의 차이만큼만 재고를 조정함
+func TestUpdateOrderAdjustsInventory(t *testing.T) {
+    useInventoryTable(t)
+    tests := []struct {
+        name      string
+        productID string
+        quantity  int
+        want      []string
+    }{
+        {"more", "p1", 5, []string{"p1:-3"}},
+        {"fewer", "p1", 1, []string{"p1:+1"}},
+        {"same", "p1", 2, nil},
+        {"other product", "p2", 4, []string{"p1:+2", "p2:-4"}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+                if op == "GetItem" {
+                    return http.StatusOK, map[string]interface{}{"Item": orderItem("o1", "alice", "p1", 2)}
+                }
+                return http.StatusOK, map[string]interface{}{}
+            })
+
+            update := map[string]interface{}{"customerid": "alice", "productid": tt.productID, "quantity": tt.quantity}
+            w := doRequest(newRouter(), http.MethodPut, "/v1/order/o1", update, nil)
+            if w.Code != http.StatusOK {
+                t.Fatalf("status %d, want 200 (%s)", w.Code, w.Body)
+            }
+
+            calls := fake.callsTo("TransactWriteItems")
+            if len(calls) != 1 {
+                t.Fatalf("TransactWriteItems called %d times, want 1", len(calls))
+            }
+            if got := inventoryChanges(t, calls[0].Body); !equalStrings(got, tt.want) {
+                t.Errorf("inventory changes %v, want %v", got, tt.want)
+            }
+            if calls := fake.callsTo("UpdateItem"); len(calls) != 0 {
+                t.Errorf("UpdateItem called outside the transaction")
+            }
+        })
+    }
+}
+
+func TestUpdateOrderOutOfStock(t *testing.T) {
+    useInventoryTable(t)
+    newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        switch op {
+        case "GetItem":
+            return http.StatusOK, map[string]interface{}{"Item": orderItem("o1", "alice", "p1", 1)}
+        case "TransactWriteItems":
+            return transactionCanceled("None", "ConditionalCheckFailed")
+        }
+        return http.StatusOK, map[string]interface{}{}
+    })
+
+    update := map[string]interface{}{"customerid": "alice", "productid": "p1", "quantity": 1000}
+    w := doRequest(newRouter(), http.MethodPut, "/v1/order/o1", update, nil)
+    if w.Code != http.StatusConflict {
+        t.Errorf("status %d, want 409 (%s)", w.Code, w.Body)
+    }
+}
+
+// 읽은 뒤 다른 요청이 주문을 바꿨으면 잘못된 차이로 재고를 조정하지 않고 409
+func TestUpdateOrderChangedConcurrently(t *testing.T) {
+    useInventoryTable(t)
+    newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        switch op {
+        case "GetItem":
+            return http.StatusOK, map[string]interface{}{"Item": orderItem("o1", "alice", "p1", 1)}
+        case "TransactWriteItems":
+            return transactionCanceled("ConditionalCheckFailed", "None")
+        }
+        return http.StatusOK, map[string]interface{}{}
+    })
+
+    update := map[string]interface{}{"customerid": "alice", "productid": "p1", "quantity": 2}
+    w := doRequest(newRouter(), http.MethodPut, "/v1/order/o1", update, nil)
+    if w.Code != http.StatusConflict {
+        t.Errorf("status %d, want 409 (%s)", w.Code, w.Body)
+    }
+}
+
+// 삭제하면 주문 수량을 재고에 돌려줌
+func TestDeleteOrderReturnsInventory(t *testing.T) {
+    useInventoryTable(t)
+    fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        if op == "GetItem" {
+            return http.StatusOK, map[string]interface{}{"Item": orderItem("o1", "alice", "p1", 3)}
+        }
+        return http.StatusOK, map[string]interface{}{}
+    })
+
+    w := doRequest(newRouter(), http.MethodDelete, "/v1/order?id=o1", nil, nil)
+    if w.Code != http.StatusNoContent {
+        t.Fatalf("status %d, want 204 (%s)", w.Code, w.Body)
+    }
+    calls := fake.callsTo("TransactWriteItems")
+    if len(calls) != 1 {
+        t.Fatalf("TransactWriteItems called %d times, want 1", len(calls))
+    }
+    items := calls[0].Body["TransactItems"].([]interface{})
+    if attrS(items[0].(map[string]interface{}), "Delete", "Key", "id") != "o1" {
+        t.Errorf("first item %v, want delete of o1", items[0])
+    }
+    if got := inventoryChanges(t, calls[0].Body); !equalStrings(got, []string{"p1:+3"}) {
+        t.Errorf("inventory changes %v, want [p1:+3]", got)
+    }
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+    "net/http"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/gmstcl/eCommerce-System/internal/jwtauth"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+    "github.com/golang-jwt/jwt/v5"
+)
+
+// 토큰의 서명을 한 글자 바꿈
+func tamperSignature(token string) string {
+    last := token[len(token)-2]
+    replacement := byte('A')
+    if last == 'A' {
+        replacement = 'B'
+    }
+    return token[:len(token)-2] + string(replacement) + token[len(token)-1:]
+}
+
+// 서명은 그대로 두고 페이로드만 다른 토큰의 것으로 바꿈
+func tamperPayload(t *testing.T, token string) string {
+    t.Helper()
+    parts := strings.Split(token, ".")
+    forged := strings.Split(signToken(t, "bob", jwtAdminScope, time.Hour), ".")
+    return parts[0] + "." + forged[1] + "." + parts[2]
+}
+
+func TestJWTMiddleware(t *testing.T) {
+    useJWTSecret(t)
+    newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        if op == "GetItem" {
+            return http.StatusOK, map[string]interface{}{"Item": orderItem("o1", "alice", "p1", 1)}
+        }
+        return http.StatusOK, map[string]interface{}{}
+    })
+    router := newRouter()
+
+    valid := signToken(t, "alice", "", time.Hour)
+    unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{
+        "sub": "alice",
+        "exp": time.Now().Add(time.Hour).Unix(),
+    }).SignedString(jwt.UnsafeAllowNoneSignatureType)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    tests := []struct {
+        name    string
+        headers map[string]string
+        status  int
+    }{
+        {"valid", bearer(valid), http.StatusOK},
+        {"expired", bearer(signToken(t, "alice", "", -time.Hour)), http.StatusUnauthorized},
+        {"within leeway", bearer(signToken(t, "alice", "", -jwtauth.Leeway/2)), http.StatusOK},
+        {"tampered signature", bearer(tamperSignature(valid)), http.StatusUnauthorized},
+        {"tampered payload", bearer(tamperPayload(t, valid)), http.StatusUnauthorized},
+        {"alg none", bearer(unsigned), http.StatusUnauthorized},
+        {"garbage", bearer("not-a-jwt"), http.StatusUnauthorized},
+        {"missing", nil, http.StatusUnauthorized},
+    }
+    for _, tt := range tests {
+        w := doRequest(router, http.MethodGet, "/v1/order/o1", nil, tt.headers)
+        if w.Code != tt.status {
+            t.Errorf("%s: status %d, want %d (%s)", tt.name, w.Code, tt.status, w.Body)
+        }
+        if tt.status == http.StatusUnauthorized && !strings.Contains(w.Body.String(), respond.CodeUnauthorized) {
+            t.Errorf("%s: body %s, want code %s", tt.name, w.Body, respond.CodeUnauthorized)
+        }
+    }
+}
+
+// 검증된 토큰의 sub가 감사 기록의 actor가 됨
+func TestJWTSubjectIsAuditActor(t *testing.T) {
+    useJWTSecret(t)
+    prev := orderIDStrategy
+    orderIDStrategy = orderIDUUID
+    t.Cleanup(func() { orderIDStrategy = prev })
+    fake := newFakeDynamo(t, nil)
+    router := newRouter()
+
+    w := doRequest(router, http.MethodPost, "/v1/order", orderRequest(), bearer(signToken(t, "alice", "", time.Hour)))
+    if w.Code != http.StatusCreated {
+        t.Fatalf("status %d, want 201 (%s)", w.Code, w.Body)
+    }
+
+    var actors []string
+    for _, call := range fake.callsTo("PutItem") {
+        if call.Body["TableName"] == orderAuditTable {
+            actors = append(actors, attrS(call.Body, "Item", "actor"))
+        }
+    }
+    if len(actors) != 1 || actors[0] != "sub:alice" {
+        t.Errorf("audit actors %v, want [sub:alice]", actors)
+    }
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+    "github.com/gmstcl/eCommerce-System/internal/keystyle"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+)
+
+func useKeyStyle(t *testing.T, style string) {
+    t.Helper()
+    prev := respond.KeyStyle
+    respond.KeyStyle = style
+    t.Cleanup(func() { respond.KeyStyle = prev })
+}
+
+// 이력, 배치, 오류 응답도 JSON_KEY_STYLE을 따름
+func TestResponsesFollowKeyStyle(t *testing.T) {
+    useKeyStyle(t, keystyle.Camel)
+    newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        if op == "Query" {
+            return http.StatusOK, map[string]interface{}{
+                "Items": []interface{}{dynamoItem(map[string]interface{}{
+                    "order_id": "o1", "ts": 1, "action": auditCreate, "actor": "anonymous",
+                })},
+            }
+        }
+        return http.StatusOK, map[string]interface{}{}
+    })
+    router := newRouter()
+
+    w := doRequest(router, http.MethodGet, "/v1/order/o1/history", nil, nil)
+    if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"orderId":"o1"`) {
+        t.Errorf("history: %d %s, want orderId key", w.Code, w.Body)
+    }
+
+    w = doRequest(router, http.MethodPost, "/v1/orders/batch", "not json", nil)
+    if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"requestId"`) || strings.Contains(w.Body.String(), "request_id") {
+        t.Errorf("error: %d %s, want requestId key", w.Code, w.Body)
+    }
+}
+
+// 나중에 추가된 snake_case 키도 camelCase로 바뀜
+func TestExportResponseFollowsKeyStyle(t *testing.T) {
+    useKeyStyle(t, keystyle.Camel)
+    newFakeDynamo(t, nil)
+    rec := &endpointRecorder{}
+    srv := httptest.NewServer(http.HandlerFunc(rec.serve))
+    t.Cleanup(srv.Close)
+    useEndpoints(t, srv.URL, srv.URL)
+    prevClient, prevUploader, prevBucket := s3Client, s3Uploader, s3AccessPointARN
+    s3Client = newS3Client(testAWSConfig())
+    s3Uploader = manager.NewUploader(s3Client)
+    s3AccessPointARN = "exports"
+    t.Cleanup(func() { s3Client, s3Uploader, s3AccessPointARN = prevClient, prevUploader, prevBucket })
+
+    w := doRequest(newRouter(), http.MethodPost, "/v1/s3/order", nil, nil)
+    if w.Code != http.StatusOK {
+        t.Fatalf("status %d, want 200 (%s)", w.Code, w.Body)
+    }
+    body := w.Body.String()
+    for _, key := range []string{`"downloadUrl"`, `"expiresAt"`} {
+        if !strings.Contains(body, key) {
+            t.Errorf("body %s, want key %s", body, key)
+        }
+    }
+    if strings.Contains(body, "download_url") || strings.Contains(body, "expires_at") {
+        t.Errorf("body %s still has snake_case keys", body)
+    }
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "os"
+    "testing"
+    "time"
+
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// LocalStack에 실제로 붙여 보는 통합 테스트. LOCALSTACK_ENDPOINT가 없으면 건너뜀.
+//
+//	docker run --rm -p 4566:4566 localstack/localstack
+//	LOCALSTACK_ENDPOINT=http://localhost:4566 go test -run LocalStack ./order
+//
+// 매번 새 테이블과 버킷을 만들고 끝나면 지움
+func TestLocalStack(t *testing.T) {
+    endpoint := os.Getenv("LOCALSTACK_ENDPOINT")
+    if endpoint == "" {
+        t.Skip("LOCALSTACK_ENDPOINT not set")
+    }
+    useEndpoints(t, endpoint, endpoint)
+    ctx := context.Background()
+    suffix := fmt.Sprint(time.Now().UnixNano())
+
+    prevDynamo, prevS3 := dynamoClient, s3Client
+    prevTable, prevBucket := orderTable, s3AccessPointARN
+    dynamoClient, s3Client = newDynamoClient(testAWSConfig()), newS3Client(testAWSConfig())
+    orderTable, s3AccessPointARN = "orders-"+suffix, "orders-"+suffix
+    t.Cleanup(func() {
+        dynamoClient, s3Client = prevDynamo, prevS3
+        orderTable, s3AccessPointARN = prevTable, prevBucket
+    })
+
+    _, err := dynamoClient.CreateTable(ctx, &dynamodb.CreateTableInput{
+        TableName:            aws.String(orderTable),
+        AttributeDefinitions: []types.AttributeDefinition{{AttributeName: aws.String("id"), AttributeType: types.ScalarAttributeTypeS}},
+        KeySchema:            []types.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: types.KeyTypeHash}},
+        BillingMode:          types.BillingModePayPerRequest,
+    })
+    if err != nil {
+        t.Fatalf("CreateTable: %v", err)
+    }
+    t.Cleanup(func() {
+        dynamoClient.DeleteTable(context.Background(), &dynamodb.DeleteTableInput{TableName: aws.String(orderTable)})
+    })
+    if _, err := s3Client.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String(s3AccessPointARN)}); err != nil {
+        t.Fatalf("CreateBucket: %v", err)
+    }
+    t.Cleanup(func() {
+        s3Client.DeleteBucket(context.Background(), &s3.DeleteBucketInput{Bucket: aws.String(s3AccessPointARN)})
+    })
+
+    if err := selfTestDynamoDB(ctx, "selftest-"+suffix); err != nil {
+        t.Errorf("dynamodb round-trip: %v", err)
+    }
+    if err := selfTestS3(ctx, "selftest-"+suffix); err != nil {
+        t.Errorf("s3 round-trip: %v", err)
+    }
+
+    router := newRouter()
+    body := orderRequest()
+    body["id"] = "o-" + suffix
+    if w := doRequest(router, http.MethodPost, "/v1/order", body, nil); w.Code != http.StatusCreated {
+        t.Fatalf("create: status %d, want 201 (%s)", w.Code, w.Body)
+    }
+    if w := doRequest(router, http.MethodGet, "/v1/order/o-"+suffix, nil, nil); w.Code != http.StatusOK {
+        t.Errorf("get: status %d, want 200 (%s)", w.Code, w.Body)
+    }
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+    "bytes"
+    "encoding/json"
+    "io"
+    "log/slog"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "strconv"
+    "strings"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/alicebob/miniredis/v2"
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/credentials"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/jwtauth"
+    "github.com/go-redis/redis/v8"
+    "github.com/golang-jwt/jwt/v5"
+)
+
+const testJWTSecret = "test-secret"
+
+func TestMain(m *testing.M) {
+    gin.SetMode(gin.TestMode)
+    gin.DefaultWriter = io.Discard
+    logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+    slog.SetDefault(logger)
+    initServiceClients()
+    os.Exit(m.Run())
+}
+
+// 가짜 DynamoDB로 받은 요청 하나. Body는 요청 JSON을 그대로 디코딩한 값
+type dynamoCall struct {
+    Op   string
+    Body map[string]interface{}
+}
+
+// DYNAMODB_ENDPOINT와 같은 방식으로 SDK 클라이언트를 httptest 서버에 연결함.
+// handle은 작업 이름(PutItem 등)과 요청 본문을 받아 상태 코드와 응답 본문을 돌려줌
+type fakeDynamo struct {
+    mu     sync.Mutex
+    calls  []dynamoCall
+    handle func(op string, body map[string]interface{}) (int, interface{})
+}
+
+func newFakeDynamo(t *testing.T, handle func(op string, body map[string]interface{}) (int, interface{})) *fakeDynamo {
+    t.Helper()
+    f := &fakeDynamo{handle: handle}
+    srv := httptest.NewServer(http.HandlerFunc(f.serve))
+    t.Cleanup(srv.Close)
+
+    prev := dynamoClient
+    dynamoClient = dynamodb.New(dynamodb.Options{
+        Region:       "us-east-1",
+        BaseEndpoint: aws.String(srv.URL),
+        Credentials:  credentials.NewStaticCredentialsProvider("test", "test", ""),
+        Retryer:      aws.NopRetryer{},
+    })
+    t.Cleanup(func() { dynamoClient = prev })
+    return f
+}
+
+func (f *fakeDynamo) serve(w http.ResponseWriter, r *http.Request) {
+    _, op, _ := strings.Cut(r.Header.Get("X-Amz-Target"), ".")
+    var body map[string]interface{}
+    json.NewDecoder(r.Body).Decode(&body)
+
+    f.mu.Lock()
+    f.calls = append(f.calls, dynamoCall{Op: op, Body: body})
+    f.mu.Unlock()
+
+    status, resp := http.StatusOK, interface{}(map[string]interface{}{})
+    if f.handle != nil {
+        status, resp = f.handle(op, body)
+    }
+    w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+    w.WriteHeader(status)
+    json.NewEncoder(w).Encode(resp)
+}
+
+// op 작업으로 받은 요청들. op가 빈 문자열이면 전부
+func (f *fakeDynamo) callsTo(op string) []dynamoCall {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    var calls []dynamoCall
+    for _, call := range f.calls {
+        if op == "" || call.Op == op {
+            calls = append(calls, call)
+        }
+    }
+    return calls
+}
+
+func dynamoError(errType, message string) (int, interface{}) {
+    return http.StatusBadRequest, map[string]interface{}{
+        "__type":  "com.amazonaws.dynamodb.v20120810#" + errType,
+        "message": message,
+    }
+}
+
+// TransactWriteItems 취소 응답. codes는 TransactItems 순서의 취소 이유(None, ConditionalCheckFailed 등)
+func transactionCanceled(codes ...string) (int, interface{}) {
+    reasons := make([]map[string]string, len(codes))
+    for i, code := range codes {
+        reasons[i] = map[string]string{"Code": code}
+    }
+    return http.StatusBadRequest, map[string]interface{}{
+        "__type":              "com.amazonaws.dynamodb.v20120810#TransactionCanceledException",
+        "message":             "Transaction cancelled",
+        "CancellationReasons": reasons,
+    }
+}
+
+// DynamoDB JSON 항목. 값이 string이면 S, int면 N으로 씀
+func dynamoItem(attrs map[string]interface{}) map[string]interface{} {
+    item := make(map[string]interface{}, len(attrs))
+    for k, v := range attrs {
+        switch val := v.(type) {
+        case string:
+            item[k] = map[string]string{"S": val}
+        case int:
+            item[k] = map[string]string{"N": strconv.Itoa(val)}
+        }
+    }
+    return item
+}
+
+func orderItem(id, customerID, productID string, quantity int) map[string]interface{} {
+    return dynamoItem(map[string]interface{}{
+        "id":         id,
+        "customerid": customerID,
+        "productid":  productID,
+        "quantity":   quantity,
+        "unitprice":  1,
+    })
+}
+
+// 요청 본문에서 path를 따라 내려가며 S 값을 꺼냄. 예: attrS(body, "Key", "id")
+func attrS(body map[string]interface{}, path ...string) string {
+    var cur interface{} = body
+    for _, key := range path {
+        m, ok := cur.(map[string]interface{})
+        if !ok {
+            return ""
+        }
+        cur = m[key]
+    }
+    m, _ := cur.(map[string]interface{})
+    s, _ := m["S"].(string)
+    return s
+}
+
+func useMiniredis(t *testing.T) *miniredis.Miniredis {
+    t.Helper()
+    mr := miniredis.RunT(t)
+    prev := redisClient
+    redisClient = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+    t.Cleanup(func() {
+        redisClient.Close()
+        redisClient = prev
+    })
+    return mr
+}
+
+// JWT_SECRET이 설정된 것과 같게 HS256 검증을 켬
+func useJWTSecret(t *testing.T) {
+    t.Helper()
+    t.Cleanup(jwtauth.SetSecret(testJWTSecret))
+}
+
+func signToken(t *testing.T, subject, scope string, expiresIn time.Duration) string {
+    t.Helper()
+    claims := jwt.MapClaims{"sub": subject, "exp": time.Now().Add(expiresIn).Unix()}
+    if scope != "" {
+        claims["scope"] = scope
+    }
+    token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJWTSecret))
+    if err != nil {
+        t.Fatal(err)
+    }
+    return token
+}
+
+func doRequest(router http.Handler, method, path string, body interface{}, headers map[string]string) *httptest.ResponseRecorder {
+    var reader io.Reader
+    switch b := body.(type) {
+    case nil:
+    case string:
+        reader = strings.NewReader(b)
+    default:
+        data, _ := json.Marshal(b)
+        reader = bytes.NewReader(data)
+    }
+    req := httptest.NewRequest(method, path, reader)
+    if body != nil {
+        req.Header.Set("Content-Type", "application/json")
+    }
+    for k, v := range headers {
+        req.Header.Set(k, v)
+    }
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+    return w
+}
+
+func bearer(token string) map[string]string {
+    return map[string]string{"Authorization": "Bearer " + token}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+    "bufio"
+    "net/http"
+    "strconv"
+    "strings"
+    "testing"
+
+    "github.com/gmstcl/eCommerce-System/internal/apikey"
+)
+
+// /metrics를 읽어 name{labels} 줄의 값을 반환함. 아직 없는 시계열은 0
+func scrapeMetric(t *testing.T, router http.Handler, series string) float64 {
+    t.Helper()
+    w := doRequest(router, http.MethodGet, "/metrics", nil, nil)
+    if w.Code != http.StatusOK {
+        t.Fatalf("scrape: status %d, want 200 (%s)", w.Code, w.Body)
+    }
+    scanner := bufio.NewScanner(w.Body)
+    for scanner.Scan() {
+        if value, ok := strings.CutPrefix(scanner.Text(), series+" "); ok {
+            v, err := strconv.ParseFloat(value, 64)
+            if err != nil {
+                t.Fatalf("parse %q: %v", scanner.Text(), err)
+            }
+            return v
+        }
+    }
+    return 0
+}
+
+// API 키, JWT, 속도 제한이 모두 켜져 있어도 /metrics는 자격 증명 없이 읽히고 요청 수가 늘어남
+func TestMetricsScrape(t *testing.T) {
+    useMiniredis(t)
+    useJWTSecret(t)
+    prevKeys, prevLimit := apiKeys, rateLimitRequests
+    apiKeys, rateLimitRequests = apikey.Parse("k1"), 1
+    t.Cleanup(func() { apiKeys, rateLimitRequests = prevKeys, prevLimit })
+    router := newRouter()
+
+    series := `http_requests_total{method="GET",route="/v1/order/exists",status="400"}`
+    before := scrapeMetric(t, router, series)
+    if w := doRequest(router, http.MethodGet, "/v1/order/exists", nil, map[string]string{apikey.Header: "k1"}); w.Code != http.StatusBadRequest {
+        t.Fatalf("request: status %d, want 400 (%s)", w.Code, w.Body)
+    }
+    if after := scrapeMetric(t, router, series); after != before+1 {
+        t.Errorf("%s = %v, want %v", series, after, before+1)
+    }
+}
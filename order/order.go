@@ -1,117 +1,763 @@
 package main
 
 import (
-    "bytes"
+    "bufio"
     "context"
     "encoding/json"
+    "errors"
+    "flag"
+    "fmt"
+    "io"
     "log"
+    "math"
     "net/http"
     "os"
+    "sort"
+    "strconv"
+    "time"
 
     "github.com/aws/aws-sdk-go-v2/aws"
     "github.com/aws/aws-sdk-go-v2/config"
+    "github.com/aws/aws-sdk-go-v2/feature/s3/manager"
     "github.com/aws/aws-sdk-go-v2/service/dynamodb"
     "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
     "github.com/aws/aws-sdk-go-v2/service/s3"
+    s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
     "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/apikey"
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/bodylimit"
+    "github.com/gmstcl/eCommerce-System/internal/chaos"
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+    "github.com/gmstcl/eCommerce-System/internal/cors"
+    "github.com/gmstcl/eCommerce-System/internal/env"
+    "github.com/gmstcl/eCommerce-System/internal/healthz"
+    "github.com/gmstcl/eCommerce-System/internal/jwtauth"
+    "github.com/gmstcl/eCommerce-System/internal/logging"
+    "github.com/gmstcl/eCommerce-System/internal/metrics"
+    "github.com/gmstcl/eCommerce-System/internal/requestid"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+    "github.com/gmstcl/eCommerce-System/internal/server"
+    "github.com/gmstcl/eCommerce-System/internal/tracing"
+    "go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+    "github.com/prometheus/client_golang/prometheus/promhttp"
+
+    "github.com/gmstcl/eCommerce-System/order/clients"
 )
 
 var (
-    region           = os.Getenv("AWS_REGION")
+    region           string
     dynamoClient     *dynamodb.Client
     s3Client         *s3.Client
+    s3Uploader       *manager.Uploader
     s3AccessPointARN = os.Getenv("S3_ACCESS_POINT_ARN") 
-    ctx              = context.Background()
+    maxOrderQuantity = 0
+    orderTable       = "order"
+    customerIndex    = "customerid-index"
+    customerClient   *clients.CustomerClient
+    productClient    *clients.ProductClient
+)
+
+const exportPageSize = 1000
+
+const serviceName = "order"
+
+var logger = logging.New(serviceName)
+
+// 게임데이용 장애 주입. 환경 변수가 없거나 0이면 아무 동작도 하지 않음
+var (
+    chaosDBFailRate    = chaos.Rate(logger, "CHAOS_DB_FAIL_RATE")
+    chaosCacheFailRate = chaos.Rate(logger, "CHAOS_CACHE_FAIL_RATE")
 )
 
+// Redis와 다른 서비스 URL은 선택 사항이라 여기에 넣지 않음
+var requiredEnv = []string{"S3_ACCESS_POINT_ARN"}
+
+// API_KEYS가 비어 있으면 인증을 하지 않음 (로컬 개발용)
+var apiKeys = apikey.Parse(os.Getenv("API_KEYS"))
+
+// /healthz가 확인하는 의존성. Critical이 아닌 의존성은 실패해도 degraded로만 보고함
+var healthChecks = map[string]healthz.Check{
+    "dynamodb": {Run: func(ctx context.Context) error {
+        _, err := dynamoClient.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+            TableName: aws.String(orderTable),
+        })
+        return err
+    }, Critical: true},
+}
+
+var (
+    errOrderNotFound = errors.New("order not found")
+    errOrderExists   = errors.New("order already exists")
+)
+
+// createdat/updatedat은 서버가 쓰기 시점에 채우며 요청 본문의 값은 무시함
 type Order struct {
-    ID        string `json:"id"`
-    CustomerID string `json:"customerid"`
-    ProductID  string `json:"productid"`
+    ID         string    `json:"id"`
+    CustomerID string    `json:"customerid" binding:"required"`
+    ProductID  string    `json:"productid" binding:"required"`
+    Quantity   int       `json:"quantity"`
+    UnitPrice  float64   `json:"unitprice"`
+    CreatedAt  time.Time `json:"createdat"`
+    UpdatedAt  time.Time `json:"updatedat"`
 }
 
-func init() {
-    cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+// 테스트가 환경 변수와 AWS 자격 증명 없이 패키지를 불러올 수 있도록 init 대신 main에서 호출함
+func initService() {
+    env.Require(requiredEnv)
+    region = env.Region(logger)
+
+    cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
     if err != nil {
         log.Fatalf("unable to load SDK config, %v", err)
     }
-    dynamoClient = dynamodb.NewFromConfig(cfg)
-    s3Client = s3.NewFromConfig(cfg)
+    checkAWSCredentials(cfg)
+    dynamoClient = newDynamoClient(cfg)
+    s3Client = newS3Client(cfg)
+    s3Uploader = manager.NewUploader(s3Client)
+    initOrderEvents(cfg)
+
+    initExportURLExpiry()
+    if v := os.Getenv("ORDERS_EXPORT_PREFIX"); v != "" {
+        if err := validateExportPrefix(v); err != nil {
+            log.Fatalf("invalid ORDERS_EXPORT_PREFIX %q: %v", v, err)
+        }
+        ordersExportPrefix = v
+    }
+
+    // MAX_ORDER_QUANTITY가 없으면 수량 제한을 두지 않음
+    if v := os.Getenv("MAX_ORDER_QUANTITY"); v != "" {
+        maxOrderQuantity, err = strconv.Atoi(v)
+        if err != nil || maxOrderQuantity < 0 {
+            log.Fatalf("invalid MAX_ORDER_QUANTITY %q", v)
+        }
+    }
+
+    // 환경별 테이블 이름. 변수가 있는데 비어 있으면 설정 실수로 보고 종료함
+    if v, ok := os.LookupEnv("ORDER_TABLE_NAME"); ok {
+        if v == "" {
+            log.Fatalf("ORDER_TABLE_NAME is set but empty")
+        }
+        orderTable = v
+    }
+
+    // customerid를 파티션 키로 하는 GSI 이름. GET /v1/orders가 주문 전체를 돌려주므로
+    // 프로젝션은 ALL이어야 함
+    if v := os.Getenv("ORDER_CUSTOMER_INDEX"); v != "" {
+        customerIndex = v
+    }
+
+    initCache()
+    initOrderAudit()
+    initServiceClients()
+    respond.InitKeyStyle()
+    initOrderIDStrategy()
+    logEffectiveConfig()
+}
+
+// DYNAMODB_ENDPOINT가 있으면 그 주소로 보냄
+func newDynamoClient(cfg aws.Config) *dynamodb.Client {
+    return dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+        o.Retryer = newDynamoRetryer()
+        o.APIOptions = append(o.APIOptions, addDynamoBreaker)
+        if dynamoEndpoint != "" {
+            o.BaseEndpoint = aws.String(dynamoEndpoint)
+        }
+    })
+}
+
+func newS3Client(cfg aws.Config) *s3.Client {
+    return s3.NewFromConfig(cfg, func(o *s3.Options) {
+        if s3Endpoint != "" {
+            o.BaseEndpoint = aws.String(s3Endpoint)
+            // LocalStack은 버킷 이름을 호스트에 넣는 가상 호스트 방식을 기본으로 처리하지 못함
+            o.UsePathStyle = true
+        }
+    })
+}
+
+func logEffectiveConfig() {
+    logger.Info("effective config",
+        "aws_region", region,
+        "dynamodb_endpoint", dynamoEndpoint,
+        "s3_endpoint", s3Endpoint,
+        "order_table", orderTable,
+        "customer_index", customerIndex,
+        "order_audit_table", orderAuditTable,
+        "inventory_table", inventoryTable,
+        "s3_access_point", s3AccessPointARN,
+        "orders_export_prefix", ordersExportPrefix,
+        "export_url_expiry", exportURLExpiry.String(),
+        "max_order_quantity", maxOrderQuantity,
+        "customer_service", os.Getenv("CUSTOMER_SERVICE_URL"),
+        "product_service", os.Getenv("PRODUCT_SERVICE_URL"),
+        "json_key_style", respond.KeyStyle,
+        "order_id_strategy", orderIDStrategy,
+        "cache", redisClient != nil,
+        "cache_ttl", cacheTTL.String(),
+        "idempotency_ttl", idempotencyTTL.String(),
+        "order_events_arn", orderEventsARN,
+        "redis_pool", redisPoolSummary(),
+        "backend_timeout", backend.Timeout.String(),
+        "api_key_auth", len(apiKeys) > 0,
+        "cors_origins", cors.AllowedOrigins,
+        "max_body_bytes", bodylimit.Max,
+        "max_batch_body_bytes", bodylimit.MaxBatch,
+        "jwt_auth", jwtauth.Enabled(),
+        "jwt_admin_scope", jwtAdminScope,
+        "rate_limit", fmt.Sprintf("%d/%s", rateLimitRequests, rateLimitWindow),
+        "dynamodb_max_retries", dynamoMaxRetries,
+        "dynamodb_retry_base_delay", dynamoRetryBaseDelay.String(),
+        "dynamodb_breaker", fmt.Sprintf("%d failures/%s", dynamoBreakerFailures, dynamoBreakerOpen),
+    )
+}
+
+func initServiceClients() {
+    timeout := 2 * time.Second
+    if v := os.Getenv("SERVICE_CLIENT_TIMEOUT_MS"); v != "" {
+        ms, err := strconv.Atoi(v)
+        if err != nil || ms <= 0 {
+            log.Fatalf("invalid SERVICE_CLIENT_TIMEOUT_MS %q", v)
+        }
+        timeout = time.Duration(ms) * time.Millisecond
+    }
+
+    retries := 2
+    if v := os.Getenv("SERVICE_CLIENT_RETRIES"); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil || n < 0 {
+            log.Fatalf("invalid SERVICE_CLIENT_RETRIES %q", v)
+        }
+        retries = n
+    }
+
+    breakerFailures := 5
+    if v := os.Getenv("SERVICE_CLIENT_BREAKER_FAILURES"); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil || n < 0 {
+            log.Fatalf("invalid SERVICE_CLIENT_BREAKER_FAILURES %q", v)
+        }
+        breakerFailures = n
+    }
+
+    breakerOpen := 30 * time.Second
+    if v := os.Getenv("SERVICE_CLIENT_BREAKER_OPEN_SECONDS"); v != "" {
+        seconds, err := strconv.Atoi(v)
+        if err != nil || seconds <= 0 {
+            log.Fatalf("invalid SERVICE_CLIENT_BREAKER_OPEN_SECONDS %q", v)
+        }
+        breakerOpen = time.Duration(seconds) * time.Second
+    }
+
+    onStateChange := func(name, from, to string) {
+        logger.Warn("Service client circuit breaker state changed", "service", name, "from", from, "to", to)
+    }
+
+    customerClient = clients.NewCustomerClient(clients.Config{
+        BaseURL:   os.Getenv("CUSTOMER_SERVICE_URL"),
+        Timeout:   timeout,
+        Retries:   retries,
+        RequestID: requestid.From,
+        APIKey:    os.Getenv("SERVICE_API_KEY"),
+
+        BreakerFailures:      breakerFailures,
+        BreakerOpenTimeout:   breakerOpen,
+        OnBreakerStateChange: onStateChange,
+        Now:                  clock.Now,
+    })
+    productClient = clients.NewProductClient(clients.Config{
+        BaseURL:   os.Getenv("PRODUCT_SERVICE_URL"),
+        Timeout:   timeout,
+        Retries:   retries,
+        RequestID: requestid.From,
+        APIKey:    os.Getenv("SERVICE_API_KEY"),
+
+        BreakerFailures:      breakerFailures,
+        BreakerOpenTimeout:   breakerOpen,
+        OnBreakerStateChange: onStateChange,
+        Now:                  clock.Now,
+    })
+}
+
+// LoadDefaultConfig는 자격 증명이 없어도 성공하므로 시작 시점에 한 번 확인함.
+// AWS_CREDENTIALS_REQUIRED=true이면 경고 대신 종료함
+func checkAWSCredentials(cfg aws.Config) {
+    credCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+
+    if _, err := cfg.Credentials.Retrieve(credCtx); err != nil {
+        if os.Getenv("AWS_CREDENTIALS_REQUIRED") == "true" {
+            log.Fatalf("no AWS credentials could be resolved: %v", err)
+        }
+        logger.Warn("no AWS credentials could be resolved, AWS calls will fail", "error", err)
+    }
 }
 
 func main() {
+    initService()
+    flag.Parse()
+    if selfTestRequested() {
+        os.Exit(runSelfTest())
+    }
+
+    stopTracing := tracing.Init(serviceName, logger)
+    stopExports := startExportScheduler()
+    stopWebhooks := startWebhookWorker()
+
+    server.Run(newRouter(), func() {
+        stopExports()
+        stopWebhooks()
+        if redisClient != nil {
+            if err := redisClient.Close(); err != nil {
+                logger.Error("Failed to close Redis client", "error", err)
+            }
+        }
+        stopTracing()
+    })
+}
+
+func newRouter() *gin.Engine {
     router := gin.Default()
+    router.Use(otelgin.Middleware(serviceName))
+    router.Use(requestid.Middleware())
+    router.Use(cors.Middleware())
+    router.Use(bodylimit.Middleware())
+    // gin은 등록 시점까지의 미들웨어만 붙이므로 스크레이퍼가 키나 토큰 없이 읽도록 인증보다 먼저 등록함
+    router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+    router.Use(metrics.Middleware())
+    router.Use(jwtauth.Middleware(len(apiKeys) > 0))
+    router.Use(apikey.Middleware(apiKeys, func(c *gin.Context) bool { return jwtauth.Subject(c) != "" }))
+    router.Use(rateLimitMiddleware())
 
     router.GET("/v1/order", getOrder)
     router.POST("/v1/order", createOrder)
-    router.POST("/v1/s3/order", saveOrdersToS3)
-
-    router.Run(":8080")
+    router.PUT("/v1/order/:id", updateOrder)
+    router.GET("/v1/order/:id", getOrderByID)
+    router.GET("/v1/order/:id/history", getOrderHistory)
+    router.DELETE("/v1/order", deleteOrder)
+    router.POST("/v1/orders/batch", createOrdersBatch)
+    router.GET("/v1/order/exists", orderExists)
+    router.GET("/v1/orders", listOrdersByCustomer)
+    router.GET("/healthz", healthz.Handler(healthChecks))
+    router.POST("/v1/s3/order", requireAdminScope, saveOrdersToS3)
+    router.GET("/v1/s3/order/diff", requireAdminScope, diffOrdersWithS3)
+    return router
 }
 
 func getOrder(c *gin.Context) {
-    orderID := c.DefaultQuery("id", "")
+    serveOrder(c, c.DefaultQuery("id", ""))
+}
 
-    orderData, err := getOrderFromDynamoDB(orderID)
+// 캐시를 먼저 보고 없으면 DynamoDB에서 읽어 캐시를 채움. 주문이 없으면 nil, nil
+func loadOrder(ctx context.Context, orderID string) (*Order, error) {
+    orderData, err := getFromCache(ctx, orderID)
     if err != nil {
-        log.Printf("Failed to fetch order from DynamoDB for orderID %s: %v", orderID, err)
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch order"})
+        return nil, err
+    }
+    if orderData != nil {
+        return orderData, nil
+    }
+
+    orderData, err = getOrderFromDynamoDB(ctx, orderID)
+    if err != nil || orderData == nil {
+        return nil, err
+    }
+
+    saveToCache(ctx, orderData)
+    return orderData, nil
+}
+
+func createOrder(c *gin.Context) {
+    ctx := c.Request.Context()
+    idem, ok := beginIdempotentRequest(c)
+    if !ok {
+        return
+    }
+    defer idem.release(ctx)
+
+    var order Order
+    if !bindOrder(c, &order, orderIDStrategy == orderIDClient) {
+        return
+    }
+
+    if field, err := checkOrderAmounts(&order); err != nil {
+        metrics.RecordValidationField(c, field)
+        respond.Error(c, http.StatusUnprocessableEntity, respond.CodeUnprocessable, err.Error())
         return
     }
 
-    if orderData == nil {
-        c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+    if !validateOrderReferences(c, &order) {
         return
     }
 
-    c.JSON(http.StatusOK, orderData)
+    if orderIDStrategy != orderIDClient {
+        id, err := newOrderID()
+        if err != nil {
+            logger.ErrorContext(ctx, "Failed to generate order id", "error", err)
+            respond.Error(c, http.StatusInternalServerError, respond.CodeInternal, "failed to generate order id")
+            return
+        }
+        order.ID = id
+    }
+
+    err := saveOrderToDynamoDB(ctx, &order)
+    if errors.Is(err, errOrderExists) {
+        respond.Error(c, http.StatusConflict, respond.CodeConflict, "order already exists")
+        return
+    }
+    if errors.Is(err, errOutOfStock) {
+        respond.Error(c, http.StatusConflict, respond.CodeConflict, "insufficient inventory for product")
+        return
+    }
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to save order to DynamoDB", "order_id", order.ID, "error", err)
+        respond.BackendError(c, err, "failed to save order")
+        return
+    }
+
+    saveToCache(ctx, &order)
+    recordOrderAudit(ctx, c, order.ID, auditCreate)
+    idem.complete(ctx, order.ID)
+    enqueueOrderWebhook(ctx, &order)
+    publishOrderCreated(ctx, &order)
+
+    respond.JSON(c, http.StatusCreated, gin.H{"message": "Order created successfully", "id": order.ID})
 }
 
-func createOrder(c *gin.Context) {
+func updateOrder(c *gin.Context) {
+    ctx := c.Request.Context()
     var order Order
-    if err := c.ShouldBindJSON(&order); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+    if !bindOrder(c, &order, false) {
+        return
+    }
+    order.ID = c.Param("id")
+
+    if field, err := checkOrderAmounts(&order); err != nil {
+        metrics.RecordValidationField(c, field)
+        respond.Error(c, http.StatusUnprocessableEntity, respond.CodeUnprocessable, err.Error())
+        return
+    }
+
+    existing, ok := loadOrderForWrite(c, order.ID)
+    if !ok {
+        return
+    }
+    if !authorizeOrderWrite(c, existing, order.CustomerID) {
+        return
+    }
+    // If-Match가 있으면 클라이언트가 읽은 뒤 주문이 바뀌었을 때 412로 거절함
+    ifMatch := c.GetHeader("If-Match")
+    if ifMatch != "" && !ifMatchSatisfied(ifMatch, orderETag(existing)) {
+        respond.Error(c, http.StatusPreconditionFailed, respond.CodePreconditionFailed, "order was changed since it was read")
+        return
+    }
+
+    if !validateOrderReferences(c, &order) {
+        return
+    }
+
+    updated, err := updateOrderInDynamoDB(ctx, existing, &order)
+    if errors.Is(err, errOrderNotFound) {
+        respond.Error(c, http.StatusNotFound, respond.CodeNotFound, "order not found")
+        return
+    }
+    if errors.Is(err, errOutOfStock) {
+        respond.Error(c, http.StatusConflict, respond.CodeConflict, "insufficient inventory for product")
+        return
+    }
+    if errors.Is(err, errOrderChanged) && ifMatch != "" {
+        respond.Error(c, http.StatusPreconditionFailed, respond.CodePreconditionFailed, "order was changed since it was read")
+        return
+    }
+    if errors.Is(err, errOrderChanged) {
+        respond.Error(c, http.StatusConflict, respond.CodeConflict, "order was changed by another request, retry")
+        return
+    }
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to update order in DynamoDB", "order_id", order.ID, "error", err)
+        respond.BackendError(c, err, "failed to update order")
+        return
+    }
+
+    saveToCache(ctx, updated)
+    recordOrderAudit(ctx, c, updated.ID, auditUpdate)
+
+    c.Header("ETag", orderETag(updated))
+    respond.JSON(c, http.StatusOK, updated)
+}
+
+func deleteOrder(c *gin.Context) {
+    ctx := c.Request.Context()
+    orderID := c.Query("id")
+    if orderID == "" {
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "id is required")
         return
     }
 
-    if err := saveOrderToDynamoDB(&order); 
-    err != nil {
-        log.Printf("Failed to save order to DynamoDB for orderID %s: %v", order.ID, err)
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save order"})
+    existing, ok := loadOrderForWrite(c, orderID)
+    if !ok {
+        return
+    }
+    if !authorizeOrderWrite(c, existing, existing.CustomerID) {
+        return
+    }
+
+    err := deleteOrderFromDynamoDB(ctx, existing)
+    if errors.Is(err, errOrderNotFound) {
+        respond.Error(c, http.StatusNotFound, respond.CodeNotFound, "order not found")
+        return
+    }
+    if errors.Is(err, errOrderChanged) {
+        respond.Error(c, http.StatusConflict, respond.CodeConflict, "order was changed by another request, retry")
+        return
+    }
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to delete order from DynamoDB", "order_id", orderID, "error", err)
+        respond.BackendError(c, err, "failed to delete order")
+        return
+    }
+
+    deleteFromCache(ctx, orderID)
+    recordOrderAudit(ctx, c, orderID, auditDelete)
+
+    c.Status(http.StatusNoContent)
+}
+
+// 변경 전 소유자 확인용. 캐시가 늦을 수 있으므로 DynamoDB에서 직접 읽음.
+// 주문이 없거나 읽지 못하면 응답을 쓰고 false를 반환함
+func loadOrderForWrite(c *gin.Context, orderID string) (*Order, bool) {
+    ctx := c.Request.Context()
+    existing, err := getOrderFromDynamoDB(ctx, orderID)
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to fetch order", "order_id", orderID, "error", err)
+        respond.BackendError(c, err, "failed to fetch order")
+        return nil, false
+    }
+    if existing == nil {
+        respond.Error(c, http.StatusNotFound, respond.CodeNotFound, "order not found")
+        return nil, false
+    }
+    return existing, true
+}
+
+func orderExists(c *gin.Context) {
+    ctx := c.Request.Context()
+    customerID := c.Query("customerid")
+    productID := c.Query("productid")
+    if customerID == "" || productID == "" {
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "customerid and productid are required")
+        return
+    }
+    // 찾은 주문의 customerid는 요청한 값과 같으므로 주문을 읽기 전에 확인함
+    if !authorizeCustomerRead(c, customerID, "product_id", productID) {
+        return
+    }
+
+    orderID, err := findOrderByCustomerAndProduct(ctx, customerID, productID)
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to look up order", "customer_id", customerID, "product_id", productID, "error", err)
+        respond.BackendError(c, err, "failed to look up order")
+        return
+    }
+
+    if orderID == "" {
+        respond.JSON(c, http.StatusOK, gin.H{"exists": false})
+        return
+    }
+
+    respond.JSON(c, http.StatusOK, gin.H{"exists": true, "id": orderID})
+}
+
+func listOrdersByCustomer(c *gin.Context) {
+    ctx := c.Request.Context()
+    customerID := c.Query("customerid")
+    if customerID == "" {
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "customerid is required")
+        return
+    }
+    if !authorizeCustomerRead(c, customerID) {
         return
     }
 
-    c.JSON(http.StatusCreated, gin.H{"message": "Order created successfully"})
+    orders, err := getOrdersByCustomer(ctx, customerID)
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to list orders", "customer_id", customerID, "error", err)
+        respond.BackendError(c, err, "failed to list orders")
+        return
+    }
+
+    respond.JSON(c, http.StatusOK, orders)
 }
 
 func saveOrdersToS3(c *gin.Context) {
-    orders, err := getAllOrdersFromDynamoDB()
+    ctx := c.Request.Context()
+    prefix := c.DefaultQuery("prefix", ordersExportPrefix)
+    if err := validateExportPrefix(prefix); err != nil {
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, err.Error())
+        return
+    }
+
+    objectKey := newExportKey(prefix)
+    count, err := exportOrders(ctx, objectKey)
     if err != nil {
-        log.Printf("Failed to fetch orders from DynamoDB: %v", err)
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch orders"})
+        logger.ErrorContext(ctx, "Failed to export orders to S3", "key", objectKey, "error", err)
+        respond.BackendError(c, err, "failed to save data to S3")
         return
     }
 
-    data, err := json.Marshal(orders)
+    // 업로드는 이미 끝났으므로 서명 실패는 URL 없이 성공으로 응답함
+    response := gin.H{"message": "Orders saved to S3 successfully", "key": objectKey, "count": count}
+    if url, err := presignExportURL(ctx, objectKey); err != nil {
+        logger.ErrorContext(ctx, "Failed to presign export URL", "key", objectKey, "error", err)
+    } else {
+        response["download_url"] = url
+        response["expires_at"] = clock.Now().Add(exportURLExpiry).UTC()
+    }
+
+    respond.JSON(c, http.StatusOK, response)
+}
+
+// 한 줄에 주문 하나(NDJSON)씩 파이프로 업로더에 흘려보내므로 테이블 크기와 관계없이
+// 메모리에는 스캔 한 페이지와 업로드 파트 버퍼만 올라감
+func exportOrders(ctx context.Context, objectKey string) (int, error) {
+    ctx, span := tracing.Start(ctx, "exportOrders", "s3_key", objectKey)
+    defer span.End()
+    pr, pw := io.Pipe()
+    counted := make(chan int, 1)
+    go func() {
+        count, err := writeOrdersNDJSON(ctx, pw)
+        counted <- count
+        pw.CloseWithError(err)
+    }()
+
+    err := uploadToS3(ctx, objectKey, pr)
+    // 업로드가 먼저 실패해도 쓰는 쪽 고루틴이 막히지 않도록 읽는 쪽을 닫음
+    pr.CloseWithError(err)
+    count := <-counted
+    if err != nil {
+        return 0, err
+    }
+    return count, nil
+}
+
+func writeOrdersNDJSON(ctx context.Context, w io.Writer) (int, error) {
+    enc := json.NewEncoder(w)
+    count := 0
+    var startKey map[string]types.AttributeValue
+    for {
+        orders, nextKey, err := scanOrdersPage(ctx, exportPageSize, startKey)
+        if err != nil {
+            return count, err
+        }
+
+        for _, order := range orders {
+            if err := enc.Encode(order); err != nil {
+                return count, err
+            }
+            count++
+        }
+
+        if len(nextKey) == 0 {
+            return count, nil
+        }
+        startKey = nextKey
+    }
+}
+
+// 실패한 필드 이름과 오류를 반환함
+func checkOrderAmounts(order *Order) (string, error) {
+    if order.Quantity <= 0 {
+        return "quantity", fmt.Errorf("quantity must be greater than 0")
+    }
+    if maxOrderQuantity > 0 && order.Quantity > maxOrderQuantity {
+        return "quantity", fmt.Errorf("quantity %d exceeds the maximum of %d per order", order.Quantity, maxOrderQuantity)
+    }
+    if order.UnitPrice < 0 || math.IsNaN(order.UnitPrice) || math.IsInf(order.UnitPrice, 0) {
+        return "unitprice", fmt.Errorf("unitprice must be a non-negative number")
+    }
+    return "", nil
+}
+
+func diffOrdersWithS3(c *gin.Context) {
+    ctx := c.Request.Context()
+    // key가 없으면 기본 접두사 아래의 가장 최근 내보내기와 비교함
+    objectKey := c.Query("key")
+    if objectKey == "" {
+        latest, err := latestExportKey(ctx, ordersExportPrefix)
+        if err != nil {
+            logger.ErrorContext(ctx, "Failed to list exports in S3", "prefix", ordersExportPrefix, "error", err)
+            respond.BackendError(c, err, "failed to list exports in S3")
+            return
+        }
+        if latest == "" {
+            respond.Error(c, http.StatusNotFound, respond.CodeNotFound, "export object not found")
+            return
+        }
+        objectKey = latest
+    }
+
+    exported, err := getOrdersFromS3(ctx, objectKey)
     if err != nil {
-        log.Printf("Failed to marshal orders: %v", err)
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to marshal orders"})
+        var noSuchKey *s3types.NoSuchKey
+        if errors.As(err, &noSuchKey) {
+            respond.Error(c, http.StatusNotFound, respond.CodeNotFound, "export object not found")
+            return
+        }
+        logger.ErrorContext(ctx, "Failed to read export from S3", "key", objectKey, "error", err)
+        respond.BackendError(c, err, "failed to read export from S3")
         return
     }
 
-    err = saveDataToS3(data)
+    orders, err := getAllOrdersFromDynamoDB(ctx)
     if err != nil {
-        log.Printf("Failed to save data to S3: %v", err)
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save data to S3"})
+        logger.ErrorContext(ctx, "Failed to fetch orders from DynamoDB", "error", err)
+        respond.BackendError(c, err, "failed to fetch orders")
         return
     }
 
-    c.JSON(http.StatusOK, gin.H{"message": "Orders saved to S3 successfully"})
+    exportedIDs := make(map[string]bool, len(exported))
+    for _, order := range exported {
+        exportedIDs[order.ID] = true
+    }
+    dbIDs := make(map[string]bool, len(orders))
+    for _, order := range orders {
+        dbIDs[order.ID] = true
+    }
+
+    missingFromExport := []string{}
+    for id := range dbIDs {
+        if !exportedIDs[id] {
+            missingFromExport = append(missingFromExport, id)
+        }
+    }
+    missingFromDB := []string{}
+    for id := range exportedIDs {
+        if !dbIDs[id] {
+            missingFromDB = append(missingFromDB, id)
+        }
+    }
+    sort.Strings(missingFromExport)
+    sort.Strings(missingFromDB)
+
+    respond.JSON(c, http.StatusOK, gin.H{
+        "key":                 objectKey,
+        "missing_from_export": missingFromExport,
+        "missing_from_db":     missingFromDB,
+    })
 }
 
-func getOrderFromDynamoDB(orderID string) (*Order, error) {
+func getOrderFromDynamoDB(ctx context.Context, orderID string) (*Order, error) {
+    ctx, span := tracing.Start(ctx, "getOrderFromDynamoDB", "order_id", orderID)
+    defer span.End()
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosDBFailRate); err != nil {
+        return nil, err
+    }
+
     result, err := dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
-        TableName: aws.String("order"),
+        TableName: aws.String(orderTable),
         Key: map[string]types.AttributeValue{ 
             "id": &types.AttributeValueMemberS{
                 Value: orderID,
@@ -119,7 +765,7 @@ func getOrderFromDynamoDB(orderID string) (*Order, error) {
         },
     })
     if err != nil {
-        log.Printf("Error fetching order from DynamoDB for orderID %s: %v", orderID, err)
+        logger.ErrorContext(ctx, "Error fetching order from DynamoDB", "order_id", orderID, "error", err)
         return nil, err
     }
 
@@ -127,88 +773,402 @@ func getOrderFromDynamoDB(orderID string) (*Order, error) {
         return nil, nil 
     }
 
-    var order Order
-    if id, ok := result.Item["id"].(*types.AttributeValueMemberS); ok {
-        order.ID = id.Value
+    order := orderFromItem(result.Item)
+    return &order, nil
+}
+
+// 필터 조건은 페이지 단위로 적용되므로 일치 항목을 찾을 때까지 다음 페이지를 조회함
+func findOrderByCustomerAndProduct(ctx context.Context, customerID, productID string) (string, error) {
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosDBFailRate); err != nil {
+        return "", err
     }
-    if customerID, ok := result.Item["customerid"].(*types.AttributeValueMemberS); ok {
-        order.CustomerID = customerID.Value
+
+    input := &dynamodb.QueryInput{
+        TableName:              aws.String(orderTable),
+        IndexName:              aws.String(customerIndex),
+        KeyConditionExpression: aws.String("customerid = :customerid"),
+        FilterExpression:       aws.String("productid = :productid"),
+        ProjectionExpression:   aws.String("id"),
+        ExpressionAttributeValues: map[string]types.AttributeValue{
+            ":customerid": &types.AttributeValueMemberS{Value: customerID},
+            ":productid":  &types.AttributeValueMemberS{Value: productID},
+        },
     }
-    if productID, ok := result.Item["productid"].(*types.AttributeValueMemberS); ok {
-        order.ProductID = productID.Value
+
+    for {
+        result, err := dynamoClient.Query(ctx, input)
+        if err != nil {
+            return "", err
+        }
+
+        for _, item := range result.Items {
+            if id, ok := item["id"].(*types.AttributeValueMemberS); ok {
+                return id.Value, nil
+            }
+        }
+
+        if len(result.LastEvaluatedKey) == 0 {
+            return "", nil
+        }
+        input.ExclusiveStartKey = result.LastEvaluatedKey
     }
+}
 
-    return &order, nil
+// 고객 인덱스로 고객의 주문을 모두 조회함. 결과가 없으면 null 대신 빈 배열을 반환함
+func getOrdersByCustomer(ctx context.Context, customerID string) ([]Order, error) {
+    ctx, span := tracing.Start(ctx, "getOrdersByCustomer", "customer_id", customerID)
+    defer span.End()
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosDBFailRate); err != nil {
+        return nil, err
+    }
+
+    input := &dynamodb.QueryInput{
+        TableName:              aws.String(orderTable),
+        IndexName:              aws.String(customerIndex),
+        KeyConditionExpression: aws.String("customerid = :customerid"),
+        ExpressionAttributeValues: map[string]types.AttributeValue{
+            ":customerid": &types.AttributeValueMemberS{Value: customerID},
+        },
+    }
+
+    orders := []Order{}
+    for {
+        result, err := dynamoClient.Query(ctx, input)
+        if err != nil {
+            logger.ErrorContext(ctx, "Error querying orders by customer", "customer_id", customerID, "error", err)
+            return nil, err
+        }
+
+        for _, item := range result.Items {
+            orders = append(orders, orderFromItem(item))
+        }
+
+        if len(result.LastEvaluatedKey) == 0 {
+            return orders, nil
+        }
+        input.ExclusiveStartKey = result.LastEvaluatedKey
+    }
 }
 
 // saveOrderToDynamoDB 함수 추가
-func saveOrderToDynamoDB(order *Order) error {
-    input := &dynamodb.PutItemInput{
-        TableName: aws.String("order"),
-        Item: map[string]types.AttributeValue{
-            "id": &types.AttributeValueMemberS{
-                Value: order.ID,
-            },
-            "customerid": &types.AttributeValueMemberS{
-                Value: order.CustomerID,
-            },
-            "productid": &types.AttributeValueMemberS{
-                Value: order.ProductID,
-            },
-        },
+// 같은 id의 주문이 이미 있으면 덮어쓰지 않고 errOrderExists를 반환함. 변경은 updateOrderInDynamoDB로만 함
+func saveOrderToDynamoDB(ctx context.Context, order *Order) error {
+    ctx, span := tracing.Start(ctx, "saveOrderToDynamoDB", "order_id", order.ID)
+    defer span.End()
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosDBFailRate); err != nil {
+        return err
     }
 
-    _, err := dynamoClient.PutItem(ctx, input)
+    order.CreatedAt = clock.Now().UTC()
+    order.UpdatedAt = order.CreatedAt
+    var err error
+    if inventoryTable != "" {
+        err = putOrderWithInventory(ctx, order)
+    } else {
+        _, err = dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+            TableName:           aws.String(orderTable),
+            Item:                orderToItem(order),
+            ConditionExpression: aws.String("attribute_not_exists(id)"),
+        })
+        var conditionErr *types.ConditionalCheckFailedException
+        if errors.As(err, &conditionErr) {
+            err = errOrderExists
+        }
+    }
     if err != nil {
-        log.Printf("Error saving order to DynamoDB for orderID %s: %v", order.ID, err)
+        if errors.Is(err, errOrderExists) || errors.Is(err, errOutOfStock) {
+            return err
+        }
+        logger.ErrorContext(ctx, "Error saving order to DynamoDB", "order_id", order.ID, "error", err)
         return err
     }
 
-    log.Printf("Successfully saved order to DynamoDB for orderID %s", order.ID)
+    logger.InfoContext(ctx, "Successfully saved order to DynamoDB", "order_id", order.ID)
     return nil
 }
 
-func getAllOrdersFromDynamoDB() ([]Order, error) {
-    var orders []Order
-    result, err := dynamoClient.Scan(ctx, &dynamodb.ScanInput{
-        TableName: aws.String("order"),
+// PutItem과 달리 존재하지 않는 주문은 생성하지 않고 errOrderNotFound를 반환함.
+// existing을 읽은 뒤 다른 요청이 주문을 바꿨으면 errOrderChanged를 반환함. 재고를 쓰면 existing과의 수량 차이를 재고에 반영하며 재고가 모자라면 errOutOfStock을 반환함
+func updateOrderInDynamoDB(ctx context.Context, existing, order *Order) (*Order, error) {
+    ctx, span := tracing.Start(ctx, "updateOrderInDynamoDB", "order_id", order.ID)
+    defer span.End()
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosDBFailRate); err != nil {
+        return nil, err
+    }
+
+    order.UpdatedAt = clock.Now().UTC()
+    if inventoryTable != "" {
+        if err := updateOrderWithInventory(ctx, existing, order); err != nil {
+            if !errors.Is(err, errOutOfStock) && !errors.Is(err, errOrderChanged) {
+                logger.ErrorContext(ctx, "Error updating order in DynamoDB", "order_id", order.ID, "error", err)
+            }
+            return nil, err
+        }
+        logger.InfoContext(ctx, "Successfully updated order in DynamoDB", "order_id", order.ID)
+        updated := *order
+        updated.CreatedAt = existing.CreatedAt
+        return &updated, nil
+    }
+
+    // 조건이 실패하면 ALL_OLD로 돌려받은 항목이 있는지로 사라진 주문과 바뀐 주문을 구분함
+    update := orderUpdate(order)
+    result, err := dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+        TableName:                           update.TableName,
+        Key:                                 update.Key,
+        ConditionExpression:                 aws.String("attribute_exists(id) AND " + orderVersionCondition(existing, update.ExpressionAttributeValues)),
+        UpdateExpression:                    update.UpdateExpression,
+        ExpressionAttributeValues:           update.ExpressionAttributeValues,
+        ReturnValues:                        types.ReturnValueAllNew,
+        ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
     })
     if err != nil {
+        var conditionErr *types.ConditionalCheckFailedException
+        if errors.As(err, &conditionErr) {
+            if len(conditionErr.Item) == 0 {
+                return nil, errOrderNotFound
+            }
+            return nil, errOrderChanged
+        }
+        logger.ErrorContext(ctx, "Error updating order in DynamoDB", "order_id", order.ID, "error", err)
         return nil, err
     }
 
-    for _, item := range result.Items {
-        var order Order
-        if id, ok := item["id"].(*types.AttributeValueMemberS); ok {
-            order.ID = id.Value
+    logger.InfoContext(ctx, "Successfully updated order in DynamoDB", "order_id", order.ID)
+    updated := orderFromItem(result.Attributes)
+    return &updated, nil
+}
+
+// ALL_OLD로 삭제 전 항목을 돌려받아 존재하지 않던 주문을 구분함. 재고를 쓰면 수량을 재고에 돌려줌
+func deleteOrderFromDynamoDB(ctx context.Context, existing *Order) error {
+    ctx, span := tracing.Start(ctx, "deleteOrderFromDynamoDB", "order_id", existing.ID)
+    defer span.End()
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosDBFailRate); err != nil {
+        return err
+    }
+
+    if inventoryTable != "" {
+        err := deleteOrderWithInventory(ctx, existing)
+        if err != nil {
+            if !errors.Is(err, errOrderChanged) {
+                logger.ErrorContext(ctx, "Error deleting order from DynamoDB", "order_id", existing.ID, "error", err)
+            }
+            return err
         }
-        if customerID, ok := item["customerid"].(*types.AttributeValueMemberS); ok {
-            order.CustomerID = customerID.Value
+        logger.InfoContext(ctx, "Successfully deleted order from DynamoDB", "order_id", existing.ID)
+        return nil
+    }
+
+    result, err := dynamoClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+        TableName:    aws.String(orderTable),
+        Key:          orderKey(existing.ID),
+        ReturnValues: types.ReturnValueAllOld,
+    })
+    if err != nil {
+        logger.ErrorContext(ctx, "Error deleting order from DynamoDB", "order_id", existing.ID, "error", err)
+        return err
+    }
+
+    if len(result.Attributes) == 0 {
+        return errOrderNotFound
+    }
+
+    logger.InfoContext(ctx, "Successfully deleted order from DynamoDB", "order_id", existing.ID)
+    return nil
+}
+
+func getAllOrdersFromDynamoDB(ctx context.Context) ([]Order, error) {
+    var orders []Order
+    var startKey map[string]types.AttributeValue
+    for {
+        page, nextKey, err := scanOrdersPage(ctx, 0, startKey)
+        if err != nil {
+            return nil, err
         }
-        if productID, ok := item["productid"].(*types.AttributeValueMemberS); ok {
-            order.ProductID = productID.Value
+        orders = append(orders, page...)
+
+        if len(nextKey) == 0 {
+            return orders, nil
         }
-        orders = append(orders, order)
+        startKey = nextKey
+    }
+}
+
+// Scan 한 페이지를 읽고 다음 페이지의 시작 키를 반환함. 마지막 페이지면 nil.
+// limit이 0이면 DynamoDB 기본값(1MB)까지 읽음
+func scanOrdersPage(ctx context.Context, limit int32, startKey map[string]types.AttributeValue) ([]Order, map[string]types.AttributeValue, error) {
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosDBFailRate); err != nil {
+        return nil, nil, err
+    }
+
+    input := &dynamodb.ScanInput{
+        TableName:         aws.String(orderTable),
+        ExclusiveStartKey: startKey,
+    }
+    if limit > 0 {
+        input.Limit = aws.Int32(limit)
+    }
+
+    result, err := dynamoClient.Scan(ctx, input)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    orders := make([]Order, 0, len(result.Items))
+    for _, item := range result.Items {
+        orders = append(orders, orderFromItem(item))
     }
 
-    return orders, nil
+    return orders, result.LastEvaluatedKey, nil
+}
+
+func orderKey(orderID string) map[string]types.AttributeValue {
+    return map[string]types.AttributeValue{
+        "id": &types.AttributeValueMemberS{Value: orderID},
+    }
+}
+
+func orderToItem(order *Order) map[string]types.AttributeValue {
+    return map[string]types.AttributeValue{
+        "id": &types.AttributeValueMemberS{
+            Value: order.ID,
+        },
+        "customerid": &types.AttributeValueMemberS{
+            Value: order.CustomerID,
+        },
+        "productid": &types.AttributeValueMemberS{
+            Value: order.ProductID,
+        },
+        "quantity": &types.AttributeValueMemberN{
+            Value: strconv.Itoa(order.Quantity),
+        },
+        "unitprice": &types.AttributeValueMemberN{
+            Value: strconv.FormatFloat(order.UnitPrice, 'f', -1, 64),
+        },
+        "createdat": &types.AttributeValueMemberS{
+            Value: order.CreatedAt.Format(time.RFC3339Nano),
+        },
+        "updatedat": &types.AttributeValueMemberS{
+            Value: order.UpdatedAt.Format(time.RFC3339Nano),
+        },
+    }
 }
 
-func saveDataToS3(data []byte) error {
-    objectKey := "orders_data.json"
+func orderFromItem(item map[string]types.AttributeValue) Order {
+    var order Order
+    if id, ok := item["id"].(*types.AttributeValueMemberS); ok {
+        order.ID = id.Value
+    }
+    if customerID, ok := item["customerid"].(*types.AttributeValueMemberS); ok {
+        order.CustomerID = customerID.Value
+    }
+    if productID, ok := item["productid"].(*types.AttributeValueMemberS); ok {
+        order.ProductID = productID.Value
+    }
+    if quantity, ok := item["quantity"].(*types.AttributeValueMemberN); ok {
+        order.Quantity, _ = strconv.Atoi(quantity.Value)
+    }
+    if unitPrice, ok := item["unitprice"].(*types.AttributeValueMemberN); ok {
+        order.UnitPrice, _ = strconv.ParseFloat(unitPrice.Value, 64)
+    }
+    // 타임스탬프가 생기기 전의 항목에는 값이 없음
+    if createdAt, ok := item["createdat"].(*types.AttributeValueMemberS); ok {
+        order.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt.Value)
+    }
+    if updatedAt, ok := item["updatedat"].(*types.AttributeValueMemberS); ok {
+        order.UpdatedAt, _ = time.Parse(time.RFC3339Nano, updatedAt.Value)
+    }
+    return order
+}
 
+// 크기를 모르는 스트림이므로 PutObject 대신 멀티파트 업로더를 씀. 내보내기 전체가
+// 한 번의 호출이라 백엔드 타임아웃 대신 요청 컨텍스트만 따름
+func uploadToS3(ctx context.Context, objectKey string, body io.Reader) error {
     // S3에 데이터를 저장
-    _, err := s3Client.PutObject(ctx, &s3.PutObjectInput{
-        Bucket: aws.String(s3AccessPointARN), // 환경변수에서 가져온 ARN 사용
-        Key:    aws.String(objectKey),
-        Body:   bytes.NewReader(data),
+    _, err := s3Uploader.Upload(ctx, &s3.PutObjectInput{
+        Bucket:      aws.String(s3AccessPointARN), // 환경변수에서 가져온 ARN 사용
+        Key:         aws.String(objectKey),
+        Body:        body,
+        ContentType: aws.String("application/x-ndjson"),
     })
     if err != nil {
-        log.Printf("Error saving data to S3: %v", err)
+        logger.ErrorContext(ctx, "Error saving data to S3", "key", objectKey, "error", err)
         return err
     }
 
-    log.Printf("Successfully saved data to S3")
+    logger.InfoContext(ctx, "Successfully saved data to S3", "key", objectKey)
     return nil
 }
 
+func getOrdersFromS3(ctx context.Context, objectKey string) ([]Order, error) {
+    ctx, span := tracing.Start(ctx, "getOrdersFromS3", "s3_key", objectKey)
+    defer span.End()
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    result, err := s3Client.GetObject(ctx, &s3.GetObjectInput{
+        Bucket: aws.String(s3AccessPointARN),
+        Key:    aws.String(objectKey),
+    })
+    if err != nil {
+        return nil, err
+    }
+    defer result.Body.Close()
+
+    return decodeOrdersExport(result.Body)
+}
+
+// NDJSON을 읽되, 예전 형식(JSON 배열 하나)으로 만든 내보내기도 읽을 수 있게 함
+func decodeOrdersExport(r io.Reader) ([]Order, error) {
+    br := bufio.NewReader(r)
+    dec := json.NewDecoder(br)
+    if first, err := peekNonSpace(br); err == nil && first == '[' {
+        var orders []Order
+        if err := dec.Decode(&orders); err != nil {
+            return nil, err
+        }
+        return orders, nil
+    }
+
+    var orders []Order
+    for {
+        var order Order
+        err := dec.Decode(&order)
+        if err == io.EOF {
+            return orders, nil
+        }
+        if err != nil {
+            return nil, err
+        }
+        orders = append(orders, order)
+    }
+}
+
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+    for {
+        b, err := br.ReadByte()
+        if err != nil {
+            return 0, err
+        }
+        if b != ' ' && b != '\t' && b != '\n' && b != '\r' {
+            return b, br.UnreadByte()
+        }
+    }
+}
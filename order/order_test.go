@@ -0,0 +1,206 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+)
+
+// 시작 키에 따라 세 페이지로 나눠 응답하는 Scan
+func TestGetAllOrdersReadsEveryPage(t *testing.T) {
+    pages := map[string]struct {
+        ids  []string
+        next string
+    }{
+        "":   {[]string{"o1", "o2"}, "o2"},
+        "o2": {[]string{"o3", "o4"}, "o4"},
+        "o4": {[]string{"o5"}, ""},
+    }
+    fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        page := pages[attrS(body, "ExclusiveStartKey", "id")]
+        items := make([]interface{}, len(page.ids))
+        for i, id := range page.ids {
+            items[i] = orderItem(id, "alice", "p1", 1)
+        }
+        resp := map[string]interface{}{"Items": items}
+        if page.next != "" {
+            resp["LastEvaluatedKey"] = dynamoItem(map[string]interface{}{"id": page.next})
+        }
+        return http.StatusOK, resp
+    })
+
+    orders, err := getAllOrdersFromDynamoDB(context.Background())
+    if err != nil {
+        t.Fatal(err)
+    }
+    var ids []string
+    for _, order := range orders {
+        ids = append(ids, order.ID)
+    }
+    if len(ids) != 5 || ids[0] != "o1" || ids[4] != "o5" {
+        t.Errorf("got orders %v, want o1..o5", ids)
+    }
+
+    calls := fake.callsTo("Scan")
+    if len(calls) != 3 {
+        t.Fatalf("Scan called %d times, want 3", len(calls))
+    }
+    for i, want := range []string{"", "o2", "o4"} {
+        if got := attrS(calls[i].Body, "ExclusiveStartKey", "id"); got != want {
+            t.Errorf("page %d started at %q, want %q", i, got, want)
+        }
+    }
+}
+
+// 내보내기가 한 페이지씩 읽을 수 있도록 limit을 Scan에 넘김
+func TestScanOrdersPagePassesLimit(t *testing.T) {
+    fake := newFakeDynamo(t, nil)
+
+    if _, _, err := scanOrdersPage(context.Background(), 100, nil); err != nil {
+        t.Fatal(err)
+    }
+    if limit := fake.callsTo("Scan")[0].Body["Limit"]; limit != float64(100) {
+        t.Errorf("Limit %v, want 100", limit)
+    }
+}
+
+// DeleteItem에 주문 id 키와 ALL_OLD를 넘기고, 지운 항목이 있으면 204
+func TestDeleteOrder(t *testing.T) {
+    fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        switch op {
+        case "GetItem":
+            return http.StatusOK, map[string]interface{}{"Item": orderItem("o1", "alice", "p1", 1)}
+        case "DeleteItem":
+            return http.StatusOK, map[string]interface{}{"Attributes": orderItem("o1", "alice", "p1", 1)}
+        }
+        return http.StatusOK, map[string]interface{}{}
+    })
+
+    w := doRequest(newRouter(), http.MethodDelete, "/v1/order?id=o1", nil, nil)
+    if w.Code != http.StatusNoContent {
+        t.Fatalf("status %d, want 204 (%s)", w.Code, w.Body)
+    }
+    calls := fake.callsTo("DeleteItem")
+    if len(calls) != 1 {
+        t.Fatalf("DeleteItem called %d times, want 1", len(calls))
+    }
+    body := calls[0].Body
+    if body["TableName"] != orderTable || attrS(body, "Key", "id") != "o1" || body["ReturnValues"] != "ALL_OLD" {
+        t.Errorf("DeleteItem request %v, want key id=o1 on %s with ALL_OLD", body, orderTable)
+    }
+}
+
+// 지운 항목이 없으면(Attributes 없음) 404
+func TestDeleteOrderNotFound(t *testing.T) {
+    newFakeDynamo(t, nil)
+
+    w := doRequest(newRouter(), http.MethodDelete, "/v1/order?id=missing", nil, nil)
+    if w.Code != http.StatusNotFound {
+        t.Errorf("status %d, want 404 (%s)", w.Code, w.Body)
+    }
+}
+
+// customerid 인덱스를 Query하고 모든 페이지를 이어 붙임
+func TestListOrdersByCustomer(t *testing.T) {
+    fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        if attrS(body, "ExclusiveStartKey", "id") == "" {
+            return http.StatusOK, map[string]interface{}{
+                "Items":            []interface{}{orderItem("o1", "alice", "p1", 1)},
+                "LastEvaluatedKey": dynamoItem(map[string]interface{}{"id": "o1", "customerid": "alice"}),
+            }
+        }
+        return http.StatusOK, map[string]interface{}{"Items": []interface{}{orderItem("o2", "alice", "p2", 2)}}
+    })
+
+    w := doRequest(newRouter(), http.MethodGet, "/v1/orders?customerid=alice", nil, nil)
+    if w.Code != http.StatusOK {
+        t.Fatalf("status %d, want 200 (%s)", w.Code, w.Body)
+    }
+    var orders []Order
+    if err := json.Unmarshal(w.Body.Bytes(), &orders); err != nil {
+        t.Fatal(err)
+    }
+    if len(orders) != 2 || orders[0].ID != "o1" || orders[1].ID != "o2" {
+        t.Errorf("got %+v, want o1 and o2", orders)
+    }
+
+    calls := fake.callsTo("Query")
+    if len(calls) != 2 {
+        t.Fatalf("Query called %d times, want 2", len(calls))
+    }
+    body := calls[0].Body
+    if body["IndexName"] != customerIndex || attrS(body, "ExpressionAttributeValues", ":customerid") != "alice" {
+        t.Errorf("Query request %v, want customerid=alice on %s", body, customerIndex)
+    }
+}
+
+// 주문이 없으면 null이 아니라 빈 배열이고, customerid가 없으면 조회하지 않고 400
+func TestListOrdersByCustomerEmpty(t *testing.T) {
+    fake := newFakeDynamo(t, nil)
+    router := newRouter()
+
+    w := doRequest(router, http.MethodGet, "/v1/orders?customerid=nobody", nil, nil)
+    if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != "[]" {
+        t.Errorf("no orders: status %d body %s, want 200 []", w.Code, w.Body)
+    }
+
+    w = doRequest(router, http.MethodGet, "/v1/orders", nil, nil)
+    if w.Code != http.StatusBadRequest {
+        t.Errorf("missing customerid: status %d, want 400 (%s)", w.Code, w.Body)
+    }
+    if calls := fake.callsTo("Query"); len(calls) != 1 {
+        t.Errorf("Query called %d times, want 1", len(calls))
+    }
+}
+
+// 수량과 단가는 DynamoDB에 N으로 저장되고 소수 단가도 그대로 읽힘
+func TestOrderNumericRoundTrip(t *testing.T) {
+    fake := newFakeDynamo(t, nil)
+
+    body := map[string]interface{}{"id": "o1", "customerid": "alice", "productid": "p1", "quantity": 3, "unitprice": 19.99}
+    if w := doRequest(newRouter(), http.MethodPost, "/v1/order", body, nil); w.Code != http.StatusCreated {
+        t.Fatalf("status %d, want 201 (%s)", w.Code, w.Body)
+    }
+    var item map[string]interface{}
+    for _, call := range fake.callsTo("PutItem") {
+        if call.Body["TableName"] == orderTable {
+            item, _ = call.Body["Item"].(map[string]interface{})
+        }
+    }
+    quantity, _ := item["quantity"].(map[string]interface{})
+    price, _ := item["unitprice"].(map[string]interface{})
+    if quantity["N"] != "3" || price["N"] != "19.99" {
+        t.Errorf("stored quantity %v unitprice %v, want N 3 and N 19.99", quantity, price)
+    }
+
+    order := orderFromItem(orderToItem(&Order{ID: "o1", Quantity: 3, UnitPrice: 0.1 + 0.2}))
+    if order.Quantity != 3 || order.UnitPrice != 0.1+0.2 {
+        t.Errorf("round trip = %+v, want quantity 3 and unitprice %v", order, 0.1+0.2)
+    }
+}
+
+// 생성 시각을 서버 시계로 채우고 요청 본문의 값은 무시함
+func TestCreateOrderTimestamps(t *testing.T) {
+    fake := newFakeDynamo(t, nil)
+    now := time.Date(2024, 3, 5, 14, 7, 9, 123456789, time.UTC)
+    t.Cleanup(clock.Set(clock.NewFake(now)))
+
+    body := map[string]interface{}{"id": "o1", "customerid": "alice", "productid": "p1", "quantity": 1, "createdat": "2000-01-01T00:00:00Z"}
+    if w := doRequest(newRouter(), http.MethodPost, "/v1/order", body, nil); w.Code != http.StatusCreated {
+        t.Fatalf("status %d, want 201 (%s)", w.Code, w.Body)
+    }
+    want := now.Format(time.RFC3339Nano)
+    for _, call := range fake.callsTo("PutItem") {
+        if call.Body["TableName"] != orderTable {
+            continue
+        }
+        if created, updated := attrS(call.Body, "Item", "createdat"), attrS(call.Body, "Item", "updatedat"); created != want || updated != want {
+            t.Errorf("stored createdat %q updatedat %q, want %q", created, updated, want)
+        }
+    }
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+    "crypto/rand"
+    "log"
+    "os"
+
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+    "github.com/google/uuid"
+    "github.com/oklog/ulid/v2"
+    "github.com/segmentio/ksuid"
+)
+
+// ORDER_ID_STRATEGY
+//   client (기본값): 클라이언트가 보낸 id를 그대로 사용. 기존 동작과 같고 id가 비어 있으면 거부함.
+//   uuid: 무작위 UUIDv4. 충돌 걱정이 없지만 정렬 순서에 의미가 없음.
+//   ulid, ksuid: 생성 시각이 앞쪽에 들어가는 정렬 가능한 id. 최근 주문을 id 범위로 조회할 수 있지만
+//     생성 시각이 id에 노출되고, 같은 파티션 키 범위에 쓰기가 몰릴 수 있음.
+// client가 아닌 전략에서는 요청에 들어온 id를 무시하고 서버에서 생성함.
+const (
+    orderIDClient = "client"
+    orderIDUUID   = "uuid"
+    orderIDULID   = "ulid"
+    orderIDKSUID  = "ksuid"
+)
+
+var orderIDStrategy = orderIDClient
+
+func initOrderIDStrategy() {
+    v := os.Getenv("ORDER_ID_STRATEGY")
+    switch v {
+    case "":
+    case orderIDClient, orderIDUUID, orderIDULID, orderIDKSUID:
+        orderIDStrategy = v
+    default:
+        log.Fatalf("invalid ORDER_ID_STRATEGY %q (want client, uuid, ulid or ksuid)", v)
+    }
+}
+
+// 클라이언트 전략에서는 ""를 반환하며 호출 측에서 요청의 id를 사용함
+func newOrderID() (string, error) {
+    switch orderIDStrategy {
+    case orderIDUUID:
+        return uuid.NewString(), nil
+    case orderIDULID:
+        id, err := ulid.New(ulid.Timestamp(clock.Now()), rand.Reader)
+        if err != nil {
+            return "", err
+        }
+        return id.String(), nil
+    case orderIDKSUID:
+        id, err := ksuid.NewRandomWithTime(clock.Now())
+        if err != nil {
+            return "", err
+        }
+        return id.String(), nil
+    default:
+        return "", nil
+    }
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+    "net/http"
+    "os"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/jwtauth"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+)
+
+// 이 scope가 있는 토큰은 다른 고객의 주문도 조회할 수 있음
+var jwtAdminScope = adminScope()
+
+func adminScope() string {
+    if v := os.Getenv("JWT_ADMIN_SCOPE"); v != "" {
+        return v
+    }
+    return "admin"
+}
+
+// 토큰의 sub가 주문의 CustomerID와 같거나 관리자 scope가 있어야 함.
+// API 키로 들어온 서비스 간 호출과 인증이 꺼진 경우는 검사하지 않음.
+// 거부하면 403 응답을 쓰고 false를 반환함
+func authorizeOrderRead(c *gin.Context, order *Order) bool {
+    return authorizeCustomerRead(c, order.CustomerID, "order_id", order.ID)
+}
+
+// 고객 단위 조회(목록, 존재 여부)용. 주문 하나가 아니라 요청의 customerid로 소유자를 판단함
+func authorizeCustomerRead(c *gin.Context, customerID string, logArgs ...interface{}) bool {
+    return authorizeCustomer(c, "read", customerID, logArgs...)
+}
+
+// 변경과 삭제도 읽기와 같은 소유자 규칙을 따름. customerID는 변경 후의 고객이며
+// 관리자가 아니면 주문을 다른 고객에게 넘길 수 없음
+func authorizeOrderWrite(c *gin.Context, existing *Order, customerID string) bool {
+    if !authorizeCustomer(c, "write", existing.CustomerID, "order_id", existing.ID) {
+        return false
+    }
+    return customerID == existing.CustomerID || authorizeCustomer(c, "write", customerID, "order_id", existing.ID)
+}
+
+func authorizeCustomer(c *gin.Context, action, customerID string, logArgs ...interface{}) bool {
+    subject := jwtauth.Subject(c)
+    if subject == "" || subject == customerID || jwtauth.HasScope(c, jwtAdminScope) {
+        return true
+    }
+    logger.InfoContext(c.Request.Context(), "Denied order "+action+" by non-owner", append(logArgs, "customer_id", customerID, "subject", subject)...)
+    respond.Error(c, http.StatusForbidden, respond.CodeForbidden, "order belongs to another customer")
+    return false
+}
+
+// 모든 고객의 주문을 다루는 경로(S3 내보내기, 비교)용. JWT로 들어온 요청은 관리자 scope가 있어야 함
+func requireAdminScope(c *gin.Context) {
+    if subject := jwtauth.Subject(c); subject != "" && !jwtauth.HasScope(c, jwtAdminScope) {
+        logger.InfoContext(c.Request.Context(), "Denied admin route", "path", c.FullPath(), "subject", subject)
+        respond.Error(c, http.StatusForbidden, respond.CodeForbidden, "admin scope required")
+        return
+    }
+    c.Next()
+}
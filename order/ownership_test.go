@@ -0,0 +1,154 @@
+package main
+
+import (
+    "net/http"
+    "testing"
+    "time"
+)
+
+func TestOrderReadOwnership(t *testing.T) {
+    useJWTSecret(t)
+    newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        switch op {
+        case "GetItem":
+            return http.StatusOK, map[string]interface{}{"Item": orderItem(attrS(body, "Key", "id"), "alice", "p1", 1)}
+        case "Query":
+            return http.StatusOK, map[string]interface{}{"Items": []interface{}{orderItem("o1", "alice", "p1", 1)}}
+        }
+        return http.StatusOK, map[string]interface{}{}
+    })
+    router := newRouter()
+
+    paths := []string{
+        "/v1/order?id=o1",
+        "/v1/order/o1",
+        "/v1/order/o1/history",
+        "/v1/orders?customerid=alice",
+        "/v1/order/exists?customerid=alice&productid=p1",
+    }
+    tests := []struct {
+        name   string
+        token  string
+        status int
+    }{
+        {"owner", signToken(t, "alice", "", time.Hour), http.StatusOK},
+        {"non-owner", signToken(t, "bob", "", time.Hour), http.StatusForbidden},
+        {"admin", signToken(t, "bob", "orders:read admin", time.Hour), http.StatusOK},
+    }
+    for _, tt := range tests {
+        for _, path := range paths {
+            w := doRequest(router, http.MethodGet, path, nil, bearer(tt.token))
+            if w.Code != tt.status {
+                t.Errorf("%s GET %s: status %d, want %d (%s)", tt.name, path, w.Code, tt.status, w.Body)
+            }
+        }
+    }
+}
+
+// 목록과 존재 여부 조회는 다른 고객의 customerid로 DynamoDB를 조회하기 전에 거부함
+func TestCustomerScopedReadsDeniedBeforeQuery(t *testing.T) {
+    useJWTSecret(t)
+    fake := newFakeDynamo(t, nil)
+    router := newRouter()
+    token := signToken(t, "bob", "", time.Hour)
+
+    for _, path := range []string{"/v1/orders?customerid=alice", "/v1/order/exists?customerid=alice&productid=p1"} {
+        if w := doRequest(router, http.MethodGet, path, nil, bearer(token)); w.Code != http.StatusForbidden {
+            t.Errorf("GET %s: status %d, want 403", path, w.Code)
+        }
+    }
+    if calls := fake.callsTo("Query"); len(calls) != 0 {
+        t.Errorf("Query called %d times for denied requests", len(calls))
+    }
+}
+
+// 변경과 삭제도 소유자나 관리자만 할 수 있고, 거부되면 DynamoDB에 쓰지 않음
+func TestOrderWriteOwnership(t *testing.T) {
+    useJWTSecret(t)
+    fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        switch op {
+        case "GetItem":
+            return http.StatusOK, map[string]interface{}{"Item": orderItem("o1", "alice", "p1", 1)}
+        case "UpdateItem":
+            return http.StatusOK, map[string]interface{}{"Attributes": orderItem("o1", "alice", "p1", 2)}
+        case "DeleteItem":
+            return http.StatusOK, map[string]interface{}{"Attributes": orderItem("o1", "alice", "p1", 1)}
+        }
+        return http.StatusOK, map[string]interface{}{}
+    })
+    router := newRouter()
+    update := map[string]interface{}{"customerid": "alice", "productid": "p1", "quantity": 2}
+
+    tests := []struct {
+        name   string
+        token  string
+        status int
+    }{
+        {"non-owner", signToken(t, "bob", "", time.Hour), http.StatusForbidden},
+        {"owner", signToken(t, "alice", "", time.Hour), http.StatusOK},
+        {"admin", signToken(t, "bob", "admin", time.Hour), http.StatusOK},
+    }
+    for _, tt := range tests {
+        writes := len(fake.callsTo("UpdateItem"))
+        w := doRequest(router, http.MethodPut, "/v1/order/o1", update, bearer(tt.token))
+        if w.Code != tt.status {
+            t.Errorf("%s PUT: status %d, want %d (%s)", tt.name, w.Code, tt.status, w.Body)
+        }
+        if tt.status == http.StatusForbidden && len(fake.callsTo("UpdateItem")) != writes {
+            t.Errorf("%s PUT: UpdateItem called for a denied request", tt.name)
+        }
+    }
+
+    for _, tt := range tests {
+        status := tt.status
+        if status == http.StatusOK {
+            status = http.StatusNoContent
+        }
+        writes := len(fake.callsTo("DeleteItem"))
+        w := doRequest(router, http.MethodDelete, "/v1/order?id=o1", nil, bearer(tt.token))
+        if w.Code != status {
+            t.Errorf("%s DELETE: status %d, want %d (%s)", tt.name, w.Code, status, w.Body)
+        }
+        if status == http.StatusForbidden && len(fake.callsTo("DeleteItem")) != writes {
+            t.Errorf("%s DELETE: DeleteItem called for a denied request", tt.name)
+        }
+    }
+}
+
+// 소유자라도 주문을 다른 고객 앞으로 바꿀 수는 없음
+func TestOrderUpdateCannotReassignCustomer(t *testing.T) {
+    useJWTSecret(t)
+    fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        if op == "GetItem" {
+            return http.StatusOK, map[string]interface{}{"Item": orderItem("o1", "alice", "p1", 1)}
+        }
+        return http.StatusOK, map[string]interface{}{}
+    })
+
+    update := map[string]interface{}{"customerid": "bob", "productid": "p1", "quantity": 1}
+    w := doRequest(newRouter(), http.MethodPut, "/v1/order/o1", update, bearer(signToken(t, "alice", "", time.Hour)))
+    if w.Code != http.StatusForbidden {
+        t.Errorf("status %d, want 403 (%s)", w.Code, w.Body)
+    }
+    if calls := fake.callsTo("UpdateItem"); len(calls) != 0 {
+        t.Errorf("UpdateItem called %d times, want 0", len(calls))
+    }
+}
+
+// 전체 주문을 내보내거나 비교하는 경로는 관리자 scope가 필요함
+func TestExportRequiresAdmin(t *testing.T) {
+    useJWTSecret(t)
+    fake := newFakeDynamo(t, nil)
+    router := newRouter()
+    token := signToken(t, "alice", "", time.Hour)
+
+    if w := doRequest(router, http.MethodPost, "/v1/s3/order", nil, bearer(token)); w.Code != http.StatusForbidden {
+        t.Errorf("POST /v1/s3/order: status %d, want 403 (%s)", w.Code, w.Body)
+    }
+    if w := doRequest(router, http.MethodGet, "/v1/s3/order/diff?key=orders.json", nil, bearer(token)); w.Code != http.StatusForbidden {
+        t.Errorf("GET /v1/s3/order/diff: status %d, want 403 (%s)", w.Code, w.Body)
+    }
+    if calls := fake.callsTo(""); len(calls) != 0 {
+        t.Errorf("DynamoDB called %d times for denied requests", len(calls))
+    }
+}
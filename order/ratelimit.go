@@ -0,0 +1,91 @@
+package main
+
+import (
+    "net/http"
+    "strconv"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/apikey"
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+    "github.com/gmstcl/eCommerce-System/internal/jwtauth"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+    "github.com/go-redis/redis/v8"
+)
+
+// 복제본 간에 한도를 공유하도록 토큰 버킷을 Redis에 둠.
+// RATE_LIMIT_REQUESTS가 0이거나 캐시(Redis)가 꺼져 있으면 제한하지 않음
+var (
+    rateLimitRequests = envInt("RATE_LIMIT_REQUESTS", 0)
+    rateLimitWindow   = time.Duration(envInt("RATE_LIMIT_WINDOW_SECONDS", 60)) * time.Second
+)
+
+// 창(window) 동안 rateLimitRequests개가 고르게 채워지는 버킷. {허용 여부, 재시도까지 남은 ms}를 반환함
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local window_ms = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+    tokens = capacity
+    ts = now
+end
+local rate = capacity / window_ms
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+local retry_ms = 0
+if tokens >= 1 then
+    tokens = tokens - 1
+    allowed = 1
+else
+    retry_ms = math.ceil((1 - tokens) / rate)
+end
+redis.call("HSET", KEYS[1], "tokens", tokens, "ts", now)
+redis.call("PEXPIRE", KEYS[1], window_ms)
+return {allowed, retry_ms}
+`)
+
+// 인증 미들웨어 뒤에 등록해야 토큰의 sub나 키별로 제한됨. 둘 다 없으면 클라이언트 IP로 구분함
+func rateLimitMiddleware() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if rateLimitRequests <= 0 || redisClient == nil || c.Request.URL.Path == "/healthz" {
+            c.Next()
+            return
+        }
+
+        ctx, cancel := backend.WithTimeout(c.Request.Context())
+        defer cancel()
+
+        result, err := tokenBucketScript.Run(ctx, redisClient,
+            []string{rateLimitKey(c)},
+            rateLimitRequests, rateLimitWindow.Milliseconds(), clock.Now().UnixMilli(),
+        ).Int64Slice()
+        if err != nil || len(result) != 2 {
+            // Redis 장애로 전체 요청을 막지 않도록 제한 없이 통과시킴
+            logger.ErrorContext(ctx, "Rate limit check failed", "error", err)
+            c.Next()
+            return
+        }
+
+        if result[0] == 0 {
+            retryAfter := (result[1] + 999) / 1000
+            c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+            respond.Error(c, http.StatusTooManyRequests, respond.CodeRateLimited, "rate limit exceeded")
+            return
+        }
+        c.Next()
+    }
+}
+
+func rateLimitKey(c *gin.Context) string {
+    if subject := jwtauth.Subject(c); subject != "" {
+        return "ratelimit:sub:" + subject
+    }
+    if id := apikey.ID(c); id != "" {
+        return "ratelimit:key:" + id
+    }
+    return "ratelimit:ip:" + c.ClientIP()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+    "net/http"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/gmstcl/eCommerce-System/internal/apikey"
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+)
+
+// 버킷을 다 쓰면 429와 다음 토큰이 찰 때까지의 Retry-After를 주고, 다른 키의 버킷에는 영향이 없음
+func TestRateLimitPerAPIKey(t *testing.T) {
+    useMiniredis(t)
+    newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        return http.StatusOK, map[string]interface{}{"Item": orderItem("o1", "alice", "p1", 1)}
+    })
+    prevKeys, prevRequests, prevWindow := apiKeys, rateLimitRequests, rateLimitWindow
+    apiKeys, rateLimitRequests, rateLimitWindow = apikey.Parse("k1,k2"), 2, 60*time.Second
+    t.Cleanup(func() { apiKeys, rateLimitRequests, rateLimitWindow = prevKeys, prevRequests, prevWindow })
+    fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+    t.Cleanup(clock.Set(fake))
+    router := newRouter()
+    get := func(key string) (int, string) {
+        w := doRequest(router, http.MethodGet, "/v1/order?id=o1", nil, map[string]string{apikey.Header: key})
+        return w.Code, w.Header().Get("Retry-After")
+    }
+
+    for i := 0; i < 2; i++ {
+        if status, _ := get("k1"); status != http.StatusOK {
+            t.Fatalf("request %d: status %d, want 200", i+1, status)
+        }
+    }
+    if status, retryAfter := get("k1"); status != http.StatusTooManyRequests || retryAfter != "30" {
+        t.Errorf("exhausted: status %d Retry-After %q, want 429 and 30", status, retryAfter)
+    }
+    if status, _ := get("k2"); status != http.StatusOK {
+        t.Errorf("other key: status %d, want 200", status)
+    }
+
+    fake.Advance(30 * time.Second)
+    if status, _ := get("k1"); status != http.StatusOK {
+        t.Errorf("after refill: status %d, want 200", status)
+    }
+    if status, _ := get("k1"); status != http.StatusTooManyRequests {
+        t.Errorf("after refill used: status %d, want 429", status)
+    }
+}
+
+// 제한이 꺼져 있으면 Redis를 부르지 않고 모두 통과시킴
+func TestRateLimitDisabled(t *testing.T) {
+    mr := useMiniredis(t)
+    newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        return http.StatusOK, map[string]interface{}{"Item": orderItem("o1", "alice", "p1", 1)}
+    })
+    prev := rateLimitRequests
+    rateLimitRequests = 0
+    t.Cleanup(func() { rateLimitRequests = prev })
+    router := newRouter()
+
+    for i := 0; i < 5; i++ {
+        if w := doRequest(router, http.MethodGet, "/v1/order?id=o1", nil, nil); w.Code != http.StatusOK {
+            t.Fatalf("request %d: status %d, want 200 (%s)", i+1, w.Code, w.Body)
+        }
+    }
+    for _, key := range mr.Keys() {
+        if strings.HasPrefix(key, "ratelimit:") {
+            t.Errorf("bucket %s written while disabled", key)
+        }
+    }
+}
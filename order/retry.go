@@ -0,0 +1,63 @@
+package main
+
+import (
+    "log"
+    "math/rand"
+    "os"
+    "strconv"
+    "time"
+
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+    "github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+// 기본 재시도기는 토큰 버킷이 비면 스로틀링을 바로 실패로 돌려주므로
+// 버스트 중에도 재시도하도록 DynamoDB 클라이언트 전용 재시도기를 씀.
+// 스로틀링과 일시적 오류만 재시도하고 조건식 실패 같은 오류는 그대로 반환함
+var (
+    dynamoMaxRetries     = envInt("DYNAMODB_MAX_RETRIES", 3)
+    dynamoRetryBaseDelay = time.Duration(envInt("DYNAMODB_RETRY_BASE_MS", 50)) * time.Millisecond
+)
+
+const dynamoRetryMaxDelay = 2 * time.Second
+
+func envInt(name string, def int) int {
+    v := os.Getenv(name)
+    if v == "" {
+        return def
+    }
+    n, err := strconv.Atoi(v)
+    if err != nil || n < 0 {
+        log.Fatalf("invalid %s %q", name, v)
+    }
+    return n
+}
+
+func newDynamoRetryer() aws.Retryer {
+    return retry.NewStandard(func(o *retry.StandardOptions) {
+        o.MaxAttempts = dynamoMaxRetries + 1
+        o.MaxBackoff = dynamoRetryMaxDelay
+        o.Backoff = jitterBackoff{base: dynamoRetryBaseDelay, max: dynamoRetryMaxDelay}
+        o.RateLimiter = ratelimit.None
+    })
+}
+
+// full jitter: 0부터 base*2^(attempt-1) 사이에서 무작위로 기다림
+type jitterBackoff struct {
+    base time.Duration
+    max  time.Duration
+}
+
+func (b jitterBackoff) BackoffDelay(attempt int, err error) (time.Duration, error) {
+    if b.base <= 0 {
+        return 0, nil
+    }
+    ceiling := b.max
+    if attempt < 32 {
+        if d := b.base << (attempt - 1); d > 0 && d < ceiling {
+            ceiling = d
+        }
+    }
+    return time.Duration(rand.Int63n(int64(ceiling))), nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "testing"
+    "time"
+
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// 가짜 DynamoDB 클라이언트에 서비스와 같은 재시도기를 붙임
+func useDynamoRetryer(t *testing.T) {
+    t.Helper()
+    prev := dynamoRetryBaseDelay
+    dynamoRetryBaseDelay = time.Millisecond
+    t.Cleanup(func() { dynamoRetryBaseDelay = prev })
+
+    opts := dynamoClient.Options()
+    opts.Retryer = newDynamoRetryer()
+    dynamoClient = dynamodb.New(opts)
+}
+
+// 스로틀링 두 번 뒤 성공하면 호출하는 쪽에는 성공만 보임
+func TestThrottlingIsRetried(t *testing.T) {
+    throttles := 2
+    fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        if throttles > 0 {
+            throttles--
+            return dynamoError("ProvisionedThroughputExceededException", "Rate of requests exceeds the allowed throughput")
+        }
+        return http.StatusOK, map[string]interface{}{"Item": orderItem("o1", "alice", "p1", 1)}
+    })
+    useDynamoRetryer(t)
+
+    order, err := getOrderFromDynamoDB(context.Background(), "o1")
+    if err != nil {
+        t.Fatalf("getOrderFromDynamoDB: %v", err)
+    }
+    if order == nil || order.ID != "o1" {
+        t.Errorf("got %+v, want order o1", order)
+    }
+    if n := len(fake.callsTo("GetItem")); n != 3 {
+        t.Errorf("GetItem called %d times, want 3", n)
+    }
+}
+
+// 조건식 실패는 일시적 오류가 아니므로 다시 보내지 않음
+func TestConditionalCheckFailureIsNotRetried(t *testing.T) {
+    fake := newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        return dynamoError("ConditionalCheckFailedException", "The conditional request failed")
+    })
+    useDynamoRetryer(t)
+
+    err := saveOrderToDynamoDB(context.Background(), &Order{ID: "o1", CustomerID: "alice", ProductID: "p1", Quantity: 1})
+    if !errors.Is(err, errOrderExists) {
+        t.Fatalf("saveOrderToDynamoDB: %v, want errOrderExists", err)
+    }
+    if n := len(fake.callsTo("PutItem")); n != 1 {
+        t.Errorf("PutItem called %d times, want 1", n)
+    }
+}
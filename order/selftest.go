@@ -0,0 +1,117 @@
+package main
+
+import (
+    "context"
+    "bytes"
+    "flag"
+    "fmt"
+    "io"
+    "log"
+    "os"
+
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "github.com/aws/aws-sdk-go-v2/service/s3"
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+)
+
+var selfTestFlag = flag.Bool("selftest", false, "run a round-trip against each dependency and exit")
+
+// 배포 파이프라인에서 새 이미지의 연결 상태를 확인하기 위한 모드. HTTP 서버는 띄우지 않음
+func selfTestRequested() bool {
+    return *selfTestFlag || os.Getenv("SELFTEST") == "true"
+}
+
+func runSelfTest() int {
+    ctx := context.Background()
+    id := fmt.Sprintf("selftest-%d", clock.Now().UnixNano())
+    checks := []struct {
+        name string
+        run  func(context.Context, string) error
+    }{
+        {"dynamodb", selfTestDynamoDB},
+        {"s3", selfTestS3},
+    }
+
+    status := 0
+    for _, check := range checks {
+        if err := check.run(ctx, id); err != nil {
+            log.Printf("selftest %s: FAIL: %v", check.name, err)
+            status = 1
+        } else {
+            log.Printf("selftest %s: OK", check.name)
+        }
+    }
+    return status
+}
+
+func selfTestDynamoDB(ctx context.Context, id string) error {
+    key := map[string]types.AttributeValue{
+        "id": &types.AttributeValueMemberS{Value: id},
+    }
+
+    _, err := dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+        TableName: aws.String(orderTable),
+        Item:      key,
+    })
+    if err != nil {
+        return fmt.Errorf("put: %w", err)
+    }
+
+    result, err := dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+        TableName:      aws.String(orderTable),
+        Key:            key,
+        ConsistentRead: aws.Bool(true),
+    })
+    if err != nil {
+        return fmt.Errorf("get: %w", err)
+    }
+    if result.Item == nil {
+        return fmt.Errorf("get: item %s not found after put", id)
+    }
+
+    _, err = dynamoClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+        TableName: aws.String(orderTable),
+        Key:       key,
+    })
+    if err != nil {
+        return fmt.Errorf("delete: %w", err)
+    }
+    return nil
+}
+
+func selfTestS3(ctx context.Context, id string) error {
+    objectKey := "selftest/" + id + ".json"
+
+    _, err := s3Client.PutObject(ctx, &s3.PutObjectInput{
+        Bucket: aws.String(s3AccessPointARN),
+        Key:    aws.String(objectKey),
+        Body:   bytes.NewReader([]byte(`{"selftest":true}`)),
+    })
+    if err != nil {
+        return fmt.Errorf("put: %w", err)
+    }
+
+    result, err := s3Client.GetObject(ctx, &s3.GetObjectInput{
+        Bucket: aws.String(s3AccessPointARN),
+        Key:    aws.String(objectKey),
+    })
+    if err != nil {
+        return fmt.Errorf("get: %w", err)
+    }
+    _, err = io.Copy(io.Discard, result.Body)
+    result.Body.Close()
+    if err != nil {
+        return fmt.Errorf("get: %w", err)
+    }
+
+    _, err = s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+        Bucket: aws.String(s3AccessPointARN),
+        Key:    aws.String(objectKey),
+    })
+    if err != nil {
+        return fmt.Errorf("delete: %w", err)
+    }
+    return nil
+}
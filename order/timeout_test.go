@@ -0,0 +1,29 @@
+package main
+
+import (
+    "net/http"
+    "testing"
+    "time"
+
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+)
+
+// DynamoDB가 BACKEND_TIMEOUT_MS 안에 응답하지 않으면 기다리지 않고 504
+func TestSlowDynamoDBReturnsGatewayTimeout(t *testing.T) {
+    prev := backend.Timeout
+    backend.Timeout = 50 * time.Millisecond
+    t.Cleanup(func() { backend.Timeout = prev })
+    newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        time.Sleep(300 * time.Millisecond)
+        return http.StatusOK, map[string]interface{}{"Item": orderItem("o1", "alice", "p1", 1)}
+    })
+
+    start := time.Now()
+    w := doRequest(newRouter(), http.MethodGet, "/v1/order?id=o1", nil, nil)
+    if w.Code != http.StatusGatewayTimeout {
+        t.Errorf("status %d, want 504 (%s)", w.Code, w.Body)
+    }
+    if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+        t.Errorf("handler took %v, want it to give up after the backend timeout", elapsed)
+    }
+}
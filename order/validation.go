@@ -0,0 +1,125 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+    "net/http"
+    "reflect"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gin-gonic/gin/binding"
+    "github.com/gmstcl/eCommerce-System/internal/bodylimit"
+    "github.com/gmstcl/eCommerce-System/internal/ids"
+    "github.com/gmstcl/eCommerce-System/internal/metrics"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+    "github.com/go-playground/validator/v10"
+
+    "github.com/gmstcl/eCommerce-System/order/clients"
+)
+
+// 주문 요청 검증 규칙
+//   - customerid, productid는 필수이며 빠진 필드는 400 응답의 details.fields에 모두 나열함.
+//   - id는 ORDER_ID_STRATEGY=client(기본값)일 때만 필수. 서버가 id를 생성하는 전략에서는 요청의 id를 무시함.
+//   - id, customerid, productid는 영숫자와 대시만, 최대 maxIDLength자까지 허용하며 어기면 400.
+//   - quantity는 1 이상(MAX_ORDER_QUANTITY가 있으면 그 이하), unitprice는 0 이상이어야 하며 어기면 422.
+//   - CUSTOMER_SERVICE_URL / PRODUCT_SERVICE_URL이 설정된 경우 해당 서비스에 고객과 상품이 실제로 있는지 확인하고,
+//     없으면 422, 확인 자체가 실패하면 503을 반환함. URL이 없으면 참조 검사는 건너뜀.
+func init() {
+    // 검증 오류에 구조체 필드 이름 대신 JSON 키를 사용함
+    if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+        v.RegisterTagNameFunc(func(field reflect.StructField) string {
+            name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+            if name == "-" {
+                return ""
+            }
+            return name
+        })
+    }
+}
+
+func bindOrder(c *gin.Context, order *Order, requireID bool) bool {
+    var missing []string
+    if err := c.ShouldBindJSON(order); err != nil {
+        var validationErrs validator.ValidationErrors
+        if !errors.As(err, &validationErrs) {
+            if bodylimit.TooLarge(c, err) {
+                return false
+            }
+            metrics.RecordValidationFailure(c, err)
+            respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, err.Error())
+            return false
+        }
+        for _, fe := range validationErrs {
+            missing = append(missing, fe.Field())
+        }
+    }
+
+    if requireID && order.ID == "" {
+        missing = append([]string{"id"}, missing...)
+    }
+
+    if len(missing) > 0 {
+        for _, field := range missing {
+            metrics.RecordValidationField(c, field)
+        }
+        respond.ErrorDetails(c, http.StatusBadRequest, respond.CodeInvalidRequest, "missing required fields: "+strings.Join(missing, ", "), gin.H{"fields": missing})
+        return false
+    }
+
+    if field, err := checkOrderIDs(order, requireID); err != nil {
+        metrics.RecordValidationField(c, field)
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, err.Error())
+        return false
+    }
+    return true
+}
+
+// 필수 여부는 호출하는 쪽에서 먼저 확인하고 여기서는 형식만 검사함
+func checkOrderIDs(order *Order, checkOrderID bool) (string, error) {
+    if checkOrderID {
+        if err := ids.Check("id", order.ID); err != nil {
+            return "id", err
+        }
+    }
+    if err := ids.Check("customerid", order.CustomerID); err != nil {
+        return "customerid", err
+    }
+    if err := ids.Check("productid", order.ProductID); err != nil {
+        return "productid", err
+    }
+    return "", nil
+}
+
+func validateOrderReferences(c *gin.Context, order *Order) bool {
+    if customerClient.Enabled() {
+        _, err := customerClient.GetCustomer(c.Request.Context(), order.CustomerID)
+        if !checkReference(c, "customer", order.CustomerID, err) {
+            return false
+        }
+    }
+
+    if productClient.Enabled() {
+        _, err := productClient.GetProduct(c.Request.Context(), order.ProductID)
+        if !checkReference(c, "product", order.ProductID, err) {
+            return false
+        }
+    }
+    return true
+}
+
+func checkReference(c *gin.Context, kind, id string, err error) bool {
+    if err == nil {
+        return true
+    }
+
+    if errors.Is(err, clients.ErrNotFound) {
+        metrics.RecordValidationField(c, kind+"id")
+        respond.Error(c, http.StatusUnprocessableEntity, respond.CodeUnprocessable, fmt.Sprintf("%s %s does not exist", kind, id))
+        return false
+    }
+
+    logger.ErrorContext(c.Request.Context(), "Failed to validate reference", "kind", kind, "id", id, "error", err)
+    respond.Error(c, http.StatusServiceUnavailable, respond.CodeUnavailable, "failed to validate " + kind)
+    return false
+}
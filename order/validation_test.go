@@ -0,0 +1,132 @@
+package main
+
+import (
+    "encoding/json"
+    "net/http"
+    "reflect"
+    "strings"
+    "testing"
+
+    "github.com/gmstcl/eCommerce-System/internal/ids"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+)
+
+// 빠진 필수 필드를 모두 details.fields에 나열함. id는 ORDER_ID_STRATEGY=client일 때만 필수
+func TestCreateOrderMissingFields(t *testing.T) {
+    newFakeDynamo(t, nil)
+    router := newRouter()
+
+    full := map[string]interface{}{"id": "o1", "customerid": "alice", "productid": "p1", "quantity": 1}
+    tests := []struct {
+        name    string
+        omit    []string
+        missing []string
+    }{
+        {"none", nil, nil},
+        {"id", []string{"id"}, []string{"id"}},
+        {"customerid", []string{"customerid"}, []string{"customerid"}},
+        {"productid", []string{"productid"}, []string{"productid"}},
+        {"customerid and productid", []string{"customerid", "productid"}, []string{"customerid", "productid"}},
+        {"id and productid", []string{"id", "productid"}, []string{"id", "productid"}},
+        {"all", []string{"id", "customerid", "productid"}, []string{"id", "customerid", "productid"}},
+    }
+    for _, tc := range tests {
+        t.Run(tc.name, func(t *testing.T) {
+            body := make(map[string]interface{}, len(full))
+            for k, v := range full {
+                body[k] = v
+            }
+            for _, field := range tc.omit {
+                delete(body, field)
+            }
+
+            w := doRequest(router, http.MethodPost, "/v1/order", body, nil)
+            if tc.missing == nil {
+                if w.Code != http.StatusCreated {
+                    t.Errorf("status %d, want 201 (%s)", w.Code, w.Body)
+                }
+                return
+            }
+            if w.Code != http.StatusBadRequest {
+                t.Fatalf("status %d, want 400 (%s)", w.Code, w.Body)
+            }
+            var resp struct {
+                Code    string `json:"code"`
+                Details struct {
+                    Fields []string `json:"fields"`
+                } `json:"details"`
+            }
+            if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+                t.Fatal(err)
+            }
+            if resp.Code != respond.CodeInvalidRequest || !reflect.DeepEqual(resp.Details.Fields, tc.missing) {
+                t.Errorf("got %s %v, want %s %v", resp.Code, resp.Details.Fields, respond.CodeInvalidRequest, tc.missing)
+            }
+        })
+    }
+}
+
+// 서버가 id를 만드는 전략에서는 id가 없어도 됨
+func TestCreateOrderIDOptionalWhenServerGenerates(t *testing.T) {
+    prev := orderIDStrategy
+    orderIDStrategy = orderIDUUID
+    t.Cleanup(func() { orderIDStrategy = prev })
+    newFakeDynamo(t, nil)
+
+    w := doRequest(newRouter(), http.MethodPost, "/v1/order", map[string]interface{}{"customerid": "alice", "productid": "p1", "quantity": 1}, nil)
+    if w.Code != http.StatusCreated {
+        t.Errorf("status %d, want 201 (%s)", w.Code, w.Body)
+    }
+}
+
+// 수량과 단가가 범위를 벗어나면 422, 숫자가 아니거나 수량이 정수가 아니면 바인딩 단계에서 400이고 저장하지 않음
+func TestCreateOrderRejectsInvalidAmounts(t *testing.T) {
+    fake := newFakeDynamo(t, nil)
+    prev := maxOrderQuantity
+    maxOrderQuantity = 10
+    t.Cleanup(func() { maxOrderQuantity = prev })
+    router := newRouter()
+
+    tests := []struct {
+        name   string
+        body   string
+        status int
+    }{
+        {"zero quantity", `{"id":"o1","customerid":"alice","productid":"p1","quantity":0}`, http.StatusUnprocessableEntity},
+        {"negative quantity", `{"id":"o1","customerid":"alice","productid":"p1","quantity":-1}`, http.StatusUnprocessableEntity},
+        {"over maximum", `{"id":"o1","customerid":"alice","productid":"p1","quantity":11}`, http.StatusUnprocessableEntity},
+        {"negative price", `{"id":"o1","customerid":"alice","productid":"p1","quantity":1,"unitprice":-0.5}`, http.StatusUnprocessableEntity},
+        {"string quantity", `{"id":"o1","customerid":"alice","productid":"p1","quantity":"2"}`, http.StatusBadRequest},
+        {"fractional quantity", `{"id":"o1","customerid":"alice","productid":"p1","quantity":1.5}`, http.StatusBadRequest},
+        {"string price", `{"id":"o1","customerid":"alice","productid":"p1","quantity":1,"unitprice":"9.99"}`, http.StatusBadRequest},
+    }
+    for _, tt := range tests {
+        if w := doRequest(router, http.MethodPost, "/v1/order", tt.body, nil); w.Code != tt.status {
+            t.Errorf("%s: status %d, want %d (%s)", tt.name, w.Code, tt.status, w.Body)
+        }
+    }
+    if calls := fake.callsTo("PutItem"); len(calls) != 0 {
+        t.Errorf("PutItem called %d times for invalid orders", len(calls))
+    }
+}
+
+// 너무 길거나 허용하지 않는 문자가 든 id는 저장하기 전에 400
+func TestCreateOrderRejectsOversizedIDs(t *testing.T) {
+    fake := newFakeDynamo(t, nil)
+    router := newRouter()
+    long := strings.Repeat("a", ids.MaxLength+1)
+
+    for _, field := range []string{"id", "customerid", "productid"} {
+        body := map[string]interface{}{"id": "o1", "customerid": "alice", "productid": "p1", "quantity": 1}
+        for _, value := range []string{long, "a:b"} {
+            body[field] = value
+            w := doRequest(router, http.MethodPost, "/v1/order", body, nil)
+            if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"message":"`+field+" ") {
+                t.Errorf("%s=%.10q: status %d, want 400 naming %s (%s)", field, value, w.Code, field, w.Body)
+            }
+        }
+    }
+    if calls := fake.callsTo("PutItem"); len(calls) != 0 {
+        t.Errorf("PutItem called %d times for invalid ids", len(calls))
+    }
+}
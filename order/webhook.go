@@ -0,0 +1,154 @@
+package main
+
+import (
+    "bytes"
+    "context"
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "os"
+    "sync"
+    "time"
+
+    "github.com/gmstcl/eCommerce-System/internal/requestid"
+)
+
+// ORDER_WEBHOOK_URL이 있으면 새 주문을 JSON으로 POST함. 응답을 기다리지 않도록 큐에 넣고
+// 워커 하나가 순서대로 보내며, 큐가 가득 차면 버리고 로그만 남김
+var (
+    orderWebhookURL   = os.Getenv("ORDER_WEBHOOK_URL")
+    webhookRetries    = envInt("ORDER_WEBHOOK_RETRIES", 3)
+    webhookHTTPClient = &http.Client{Timeout: time.Duration(envInt("ORDER_WEBHOOK_TIMEOUT_MS", 2000)) * time.Millisecond}
+    webhookQueue      chan webhookDelivery
+)
+
+// ORDER_WEBHOOK_SECRET이 있으면 본문의 HMAC-SHA256을 서명 헤더에 "sha256=<hex>"로 넣어
+// 받는 쪽이 보낸 곳과 본문을 확인할 수 있게 함
+var webhookSecret = os.Getenv("ORDER_WEBHOOK_SECRET")
+
+const webhookSignatureHeader = "X-Webhook-Signature"
+
+// 종료 중에 끝나는 요청이 닫힌 큐에 보내지 않도록 보내기는 읽기 잠금, 닫기는 쓰기 잠금 안에서 함.
+// 닫힌 뒤에 들어온 전송은 버리고 로그만 남김
+var (
+    webhookMu     sync.RWMutex
+    webhookClosed bool
+)
+
+const (
+    webhookQueueSize = 1000
+    webhookBackoff   = 200 * time.Millisecond
+)
+
+type webhookDelivery struct {
+    orderID   string
+    requestID string
+    payload   []byte
+}
+
+// 종료 시 반환된 함수를 호출하면 큐에 남은 전송을 마친 뒤 돌아옴
+func startWebhookWorker() func() {
+    if orderWebhookURL == "" {
+        return func() {}
+    }
+    logger.Info("Order webhook enabled", "url", orderWebhookURL)
+
+    webhookMu.Lock()
+    webhookQueue = make(chan webhookDelivery, webhookQueueSize)
+    webhookClosed = false
+    webhookMu.Unlock()
+    done := make(chan struct{})
+    go func() {
+        defer close(done)
+        for delivery := range webhookQueue {
+            deliverWebhook(delivery)
+        }
+    }()
+
+    return func() {
+        webhookMu.Lock()
+        webhookClosed = true
+        close(webhookQueue)
+        webhookMu.Unlock()
+        <-done
+    }
+}
+
+func enqueueOrderWebhook(ctx context.Context, order *Order) {
+    if webhookQueue == nil {
+        return
+    }
+    payload, err := json.Marshal(order)
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to marshal order for webhook", "order_id", order.ID, "error", err)
+        return
+    }
+
+    webhookMu.RLock()
+    defer webhookMu.RUnlock()
+    if webhookClosed {
+        logger.WarnContext(ctx, "Webhook worker stopped, dropping notification", "order_id", order.ID)
+        return
+    }
+    select {
+    case webhookQueue <- webhookDelivery{orderID: order.ID, requestID: requestid.From(ctx), payload: payload}:
+    default:
+        logger.ErrorContext(ctx, "Webhook queue full, dropping notification", "order_id", order.ID)
+    }
+}
+
+// 네트워크 오류, 429, 5xx만 재시도함
+func deliverWebhook(delivery webhookDelivery) {
+    var lastErr error
+    for attempt := 0; attempt <= webhookRetries; attempt++ {
+        if attempt > 0 {
+            time.Sleep(webhookBackoff << (attempt - 1))
+        }
+
+        retry, err := postWebhook(delivery)
+        if err == nil {
+            logger.Info("Delivered order webhook", "order_id", delivery.orderID, "request_id", delivery.requestID)
+            return
+        }
+        lastErr = err
+        if !retry {
+            break
+        }
+    }
+    logger.Error("Failed to deliver order webhook", "order_id", delivery.orderID, "request_id", delivery.requestID, "error", lastErr)
+}
+
+func postWebhook(delivery webhookDelivery) (bool, error) {
+    req, err := http.NewRequest(http.MethodPost, orderWebhookURL, bytes.NewReader(delivery.payload))
+    if err != nil {
+        return false, err
+    }
+    req.Header.Set("Content-Type", "application/json")
+    if delivery.requestID != "" {
+        req.Header.Set(requestid.Header, delivery.requestID)
+    }
+    if webhookSecret != "" {
+        req.Header.Set(webhookSignatureHeader, signWebhook(delivery.payload))
+    }
+
+    resp, err := webhookHTTPClient.Do(req)
+    if err != nil {
+        return true, err
+    }
+    resp.Body.Close()
+
+    if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+        return false, nil
+    }
+    retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+    return retry, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+}
+
+func signWebhook(payload []byte) string {
+    mac := hmac.New(sha256.New, []byte(webhookSecret))
+    mac.Write(payload)
+    return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
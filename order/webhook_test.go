@@ -0,0 +1,123 @@
+package main
+
+import (
+    "context"
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "sync"
+    "sync/atomic"
+    "testing"
+
+    "github.com/gmstcl/eCommerce-System/internal/requestid"
+)
+
+// 워커를 멈추는 동안과 멈춘 뒤에 들어온 주문도 패닉 없이 버려지고, 멈추기 전 주문은 모두 전송됨
+func TestWebhookEnqueueDuringShutdown(t *testing.T) {
+    var delivered atomic.Int32
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        delivered.Add(1)
+    }))
+    t.Cleanup(srv.Close)
+
+    prevURL, prevQueue := orderWebhookURL, webhookQueue
+    orderWebhookURL = srv.URL
+    t.Cleanup(func() { orderWebhookURL, webhookQueue = prevURL, prevQueue })
+
+    stop := startWebhookWorker()
+    ctx := context.Background()
+    for i := 0; i < 5; i++ {
+        enqueueOrderWebhook(ctx, &Order{ID: "before"})
+    }
+
+    var wg sync.WaitGroup
+    for i := 0; i < 50; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            enqueueOrderWebhook(ctx, &Order{ID: "racing"})
+        }()
+    }
+    stop()
+    wg.Wait()
+
+    enqueueOrderWebhook(ctx, &Order{ID: "late"})
+
+    if n := delivered.Load(); n < 5 {
+        t.Errorf("delivered %d webhooks, want at least the 5 queued before shutdown", n)
+    }
+}
+
+type receivedWebhook struct {
+    body      []byte
+    signature string
+    requestID string
+}
+
+// 새 주문을 JSON 본문과 HMAC 서명 헤더와 함께 보냄
+func TestWebhookDeliversSignedPayload(t *testing.T) {
+    received := make(chan receivedWebhook, 1)
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        body, _ := io.ReadAll(r.Body)
+        received <- receivedWebhook{body, r.Header.Get(webhookSignatureHeader), r.Header.Get(requestid.Header)}
+    }))
+    t.Cleanup(srv.Close)
+
+    prevURL, prevQueue, prevSecret := orderWebhookURL, webhookQueue, webhookSecret
+    orderWebhookURL, webhookSecret = srv.URL, "s3cret"
+    t.Cleanup(func() { orderWebhookURL, webhookQueue, webhookSecret = prevURL, prevQueue, prevSecret })
+
+    stop := startWebhookWorker()
+    ctx := requestid.NewContext(context.Background(), "req-1")
+    enqueueOrderWebhook(ctx, &Order{ID: "o1", CustomerID: "alice", ProductID: "p1", Quantity: 2, UnitPrice: 9.5})
+    stop()
+
+    var got receivedWebhook
+    select {
+    case got = <-received:
+    default:
+        t.Fatal("webhook not delivered")
+    }
+
+    var order Order
+    if err := json.Unmarshal(got.body, &order); err != nil {
+        t.Fatalf("payload %s: %v", got.body, err)
+    }
+    if order.ID != "o1" || order.CustomerID != "alice" || order.ProductID != "p1" || order.Quantity != 2 || order.UnitPrice != 9.5 {
+        t.Errorf("payload %+v, want order o1", order)
+    }
+
+    mac := hmac.New(sha256.New, []byte("s3cret"))
+    mac.Write(got.body)
+    if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); got.signature != want {
+        t.Errorf("signature %q, want %q", got.signature, want)
+    }
+    if got.requestID != "req-1" {
+        t.Errorf("request id %q, want req-1", got.requestID)
+    }
+}
+
+// 비밀 값이 없으면 서명 헤더를 보내지 않음
+func TestWebhookUnsignedWithoutSecret(t *testing.T) {
+    received := make(chan receivedWebhook, 1)
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        received <- receivedWebhook{signature: r.Header.Get(webhookSignatureHeader)}
+    }))
+    t.Cleanup(srv.Close)
+
+    prevURL, prevQueue, prevSecret := orderWebhookURL, webhookQueue, webhookSecret
+    orderWebhookURL, webhookSecret = srv.URL, ""
+    t.Cleanup(func() { orderWebhookURL, webhookQueue, webhookSecret = prevURL, prevQueue, prevSecret })
+
+    stop := startWebhookWorker()
+    enqueueOrderWebhook(context.Background(), &Order{ID: "o1"})
+    stop()
+
+    if got := <-received; got.signature != "" {
+        t.Errorf("signature %q, want none", got.signature)
+    }
+}
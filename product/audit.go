@@ -0,0 +1,146 @@
+package main
+
+import (
+    "context"
+    "net/http"
+    "os"
+    "strconv"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+    "github.com/gmstcl/eCommerce-System/internal/sqlq"
+)
+
+const (
+    auditEmptyName       = "empty_name"
+    auditUnknownCategory = "unknown_category"
+    auditDuplicateID     = "duplicate_id"
+
+    auditDefaultLimit = 100
+    auditMaxLimit     = 1000
+)
+
+type auditIssue struct {
+    ID     string `json:"id"`
+    Check  string `json:"check"`
+    Detail string `json:"detail"`
+}
+
+// PRODUCT_CATEGORIES가 없으면 unknown_category 검사는 건너뜀
+func knownCategories() map[string]bool {
+    v := os.Getenv("PRODUCT_CATEGORIES")
+    if v == "" {
+        return nil
+    }
+    categories := make(map[string]bool)
+    for _, category := range strings.Split(v, ",") {
+        if category = strings.TrimSpace(category); category != "" {
+            categories[category] = true
+        }
+    }
+    return categories
+}
+
+func auditProducts(c *gin.Context) {
+    ctx, cancel := backend.WithTimeout(c.Request.Context())
+    defer cancel()
+
+    limit := auditDefaultLimit
+    if v := c.Query("limit"); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil || n <= 0 || n > auditMaxLimit {
+            respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "limit must be between 1 and 1000")
+            return
+        }
+        limit = n
+    }
+
+    checks := map[string]bool{auditEmptyName: true, auditUnknownCategory: true, auditDuplicateID: true}
+    if v := c.Query("checks"); v != "" {
+        checks = make(map[string]bool)
+        for _, check := range strings.Split(v, ",") {
+            switch check {
+            case auditEmptyName, auditUnknownCategory, auditDuplicateID:
+                checks[check] = true
+            default:
+                respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "unknown check " + check)
+                return
+            }
+        }
+    }
+
+    var products []Product
+    err := sqlq.New("SELECT id, name, category FROM product WHERE id > ? ORDER BY id LIMIT ?", c.Query("cursor"), limit).
+        SelectContext(ctx, db, &products)
+    if err != nil {
+        logger.ErrorContext(ctx, "Error scanning products for audit", "error", err)
+        respond.BackendError(c, err, "failed to scan products")
+        return
+    }
+
+    issues := []auditIssue{}
+    categories := knownCategories()
+    for _, product := range products {
+        if checks[auditEmptyName] && strings.TrimSpace(product.Name) == "" {
+            issues = append(issues, auditIssue{product.ID, auditEmptyName, "name is empty"})
+        }
+        if checks[auditUnknownCategory] && categories != nil && !categories[product.Category] {
+            issues = append(issues, auditIssue{product.ID, auditUnknownCategory, "category " + strconv.Quote(product.Category) + " is not in PRODUCT_CATEGORIES"})
+        }
+    }
+
+    if checks[auditDuplicateID] && len(products) > 0 {
+        duplicates, err := findDuplicateLookingIDs(ctx, products)
+        if err != nil {
+            logger.ErrorContext(ctx, "Error checking duplicate product ids", "error", err)
+            respond.BackendError(c, err, "failed to check duplicate ids")
+            return
+        }
+        issues = append(issues, duplicates...)
+    }
+
+    nextCursor := ""
+    if len(products) == limit {
+        nextCursor = products[len(products)-1].ID
+    }
+
+    respond.JSON(c, http.StatusOK, gin.H{
+        "scanned":     len(products),
+        "issues":      issues,
+        "next_cursor": nextCursor,
+    })
+}
+
+// 대소문자와 앞뒤 공백만 다른 id를 중복으로 봄. 페이지 밖의 행과도 비교함
+func findDuplicateLookingIDs(ctx context.Context, products []Product) ([]auditIssue, error) {
+    keys := make([]string, 0, len(products))
+    for _, product := range products {
+        keys = append(keys, normalizeProductID(product.ID))
+    }
+
+    var ids []string
+    if err := sqlq.New("SELECT id FROM product WHERE ").AddIn("LOWER(TRIM(id))", keys).SelectContext(ctx, db, &ids); err != nil {
+        return nil, err
+    }
+
+    byKey := make(map[string][]string)
+    for _, id := range ids {
+        key := normalizeProductID(id)
+        byKey[key] = append(byKey[key], id)
+    }
+
+    var issues []auditIssue
+    for _, product := range products {
+        matches := byKey[normalizeProductID(product.ID)]
+        if len(matches) > 1 {
+            issues = append(issues, auditIssue{product.ID, auditDuplicateID, "looks like " + strings.Join(matches, ", ")})
+        }
+    }
+    return issues, nil
+}
+
+func normalizeProductID(id string) string {
+    return strings.ToLower(strings.TrimSpace(id))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+    "database/sql"
+    "errors"
+    "net/http"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/cacheaside"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+)
+
+// 재고 조회는 DynamoDB 강한 일관 읽기라 목록 화면처럼 자주 부르는 곳을 위해 짧게 캐시함.
+// 주문이 재고를 바꾼 것은 이 서비스가 알 수 없으므로 응답이 최대 availabilityTTL만큼 늦을 수 있음
+var availabilityTTL = 5 * time.Second
+
+type availability struct {
+    Available bool `json:"available"`
+    Stock     int  `json:"stock"`
+}
+
+// 상품 캐시와 같은 Redis를 쓰므로 키에 접두사를 붙임. 히트율은 상품 캐시만 셈
+func availabilityCache() *cacheaside.Cache {
+    cache := productCache()
+    cache.TTL = availabilityTTL
+    cache.Prefix = "availability:"
+    cache.Record = nil
+    return cache
+}
+
+func getAvailability(c *gin.Context) {
+    ctx := c.Request.Context()
+    productID := c.Query("id")
+    if productID == "" {
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "id is required")
+        return
+    }
+    if inventoryTable == "" {
+        respond.Error(c, http.StatusNotImplemented, respond.CodeNotImplemented, "stock tracking is disabled, set INVENTORY_TABLE")
+        return
+    }
+
+    cached, err := cacheaside.Get[availability](ctx, availabilityCache(), productID)
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to fetch from cache", "product_id", productID, "error", err)
+        respond.BackendError(c, err, "failed to fetch from cache")
+        return
+    }
+    if cached != nil {
+        respond.JSON(c, http.StatusOK, cached)
+        return
+    }
+
+    // 재고 항목이 없는 상품도 재고 0으로 읽히므로 상품이 있는지 먼저 확인함
+    if product, err := getFromCache(ctx, productID); err != nil || product == nil {
+        _, err = loadProduct(ctx, productID)
+        if errors.Is(err, sql.ErrNoRows) {
+            respond.Error(c, http.StatusNotFound, respond.CodeNotFound, "product not found")
+            return
+        }
+        if err != nil {
+            logger.ErrorContext(ctx, "Failed to fetch from DB", "product_id", productID, "error", err)
+            respond.BackendError(c, err, "failed to fetch from DB")
+            return
+        }
+    }
+
+    stock, err := getStock(ctx, productID)
+    if err != nil {
+        respond.BackendError(c, err, "failed to fetch stock")
+        return
+    }
+
+    result := availability{Available: stock > 0, Stock: stock}
+    availabilityCache().Set(ctx, productID, result)
+    respond.JSON(c, http.StatusOK, result)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+    "encoding/json"
+    "net/http"
+    "testing"
+)
+
+func getAvailabilityOf(t *testing.T, router http.Handler, productID string) availability {
+    t.Helper()
+    w := doRequest(router, http.MethodGet, "/v1/product/availability?id="+productID, nil, nil)
+    if w.Code != http.StatusOK {
+        t.Fatalf("%s: status %d, want 200 (%s)", productID, w.Code, w.Body)
+    }
+    var got availability
+    if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+        t.Fatalf("%s: decode %s: %v", productID, w.Body, err)
+    }
+    return got
+}
+
+func TestAvailability(t *testing.T) {
+    useTestDB(t)
+    useMiniredis(t)
+    insertProduct(t, "p1", "pen", "office")
+    insertProduct(t, "p2", "ink", "office")
+    useInventory(t, map[string]int{"p1": 3})
+    router := newRouter()
+
+    if got := getAvailabilityOf(t, router, "p1"); got != (availability{Available: true, Stock: 3}) {
+        t.Errorf("p1: %+v, want available with 3", got)
+    }
+    // 재고 항목이 없는 상품은 재고 0
+    if got := getAvailabilityOf(t, router, "p2"); got != (availability{Available: false, Stock: 0}) {
+        t.Errorf("p2: %+v, want unavailable with 0", got)
+    }
+
+    if w := doRequest(router, http.MethodGet, "/v1/product/availability?id=missing", nil, nil); w.Code != http.StatusNotFound {
+        t.Errorf("missing: status %d, want 404 (%s)", w.Code, w.Body)
+    }
+    if w := doRequest(router, http.MethodGet, "/v1/product/availability", nil, nil); w.Code != http.StatusBadRequest {
+        t.Errorf("no id: status %d, want 400 (%s)", w.Code, w.Body)
+    }
+}
+
+// TTL 동안은 재고 테이블을 다시 읽지 않고, 재고 조정은 캐시를 바로 지움
+func TestAvailabilityCache(t *testing.T) {
+    useTestDB(t)
+    mr := useMiniredis(t)
+    insertProduct(t, "p1", "pen", "office")
+    fake := useInventory(t, map[string]int{"p1": 1})
+    router := newRouter()
+
+    getAvailabilityOf(t, router, "p1")
+    getAvailabilityOf(t, router, "p1")
+    if n := len(fake.callsTo("GetItem")); n != 1 {
+        t.Errorf("GetItem called %d times, want 1", n)
+    }
+    if ttl := mr.TTL("availability:p1"); ttl <= 0 || ttl > availabilityTTL {
+        t.Errorf("cache TTL %v, want at most %v", ttl, availabilityTTL)
+    }
+
+    mr.FastForward(availabilityTTL)
+    getAvailabilityOf(t, router, "p1")
+    if n := len(fake.callsTo("GetItem")); n != 2 {
+        t.Errorf("GetItem called %d times after expiry, want 2", n)
+    }
+
+    if w := doRequest(router, http.MethodPost, "/v1/product/p1/stock", map[string]int{"delta": -1}, nil); w.Code != http.StatusOK {
+        t.Fatalf("adjust: status %d, want 200 (%s)", w.Code, w.Body)
+    }
+    if got := getAvailabilityOf(t, router, "p1"); got.Available || got.Stock != 0 {
+        t.Errorf("after adjust: %+v, want unavailable with 0", got)
+    }
+}
+
+func TestAvailabilityDisabledWithoutInventoryTable(t *testing.T) {
+    w := doRequest(newRouter(), http.MethodGet, "/v1/product/availability?id=p1", nil, nil)
+    if w.Code != http.StatusNotImplemented {
+        t.Errorf("status %d, want 501 (%s)", w.Code, w.Body)
+    }
+}
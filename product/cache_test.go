@@ -0,0 +1,55 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "testing"
+    "time"
+)
+
+// 캐시에 쓸 때 CACHE_TTL_SECONDS로 정한 만료 시간을 함께 넘김
+func TestSaveToCacheAppliesTTL(t *testing.T) {
+    mr := useMiniredis(t)
+    prev := cacheTTL
+    cacheTTL = 42 * time.Second
+    t.Cleanup(func() { cacheTTL = prev })
+
+    saveToCache(context.Background(), &Product{ID: "p1", Name: "lamp", Category: "home"})
+
+    if !mr.Exists("p1") {
+        t.Fatal("entry was not cached")
+    }
+    if ttl := mr.TTL("p1"); ttl != 42*time.Second {
+        t.Errorf("TTL %v, want 42s", ttl)
+    }
+}
+
+// 수정 전에 캐시된 항목이 수정 후 조회에 남아 있지 않음
+func TestUpdateProductDoesNotServeStaleCache(t *testing.T) {
+    mr := useMiniredis(t)
+    useTestDB(t)
+    insertProduct(t, "p1", "lamp", "home")
+    router := newRouter()
+
+    if w := doRequest(router, http.MethodGet, "/v1/product?id=p1", nil, nil); w.Code != http.StatusOK || !mr.Exists("p1") {
+        t.Fatalf("first get: status %d, cached %v, want 200 and cached", w.Code, mr.Exists("p1"))
+    }
+
+    body := map[string]interface{}{"id": "p1", "name": "desk lamp", "category": "home", "version": 1}
+    if w := doRequest(router, http.MethodPut, "/v1/product", body, nil); w.Code != http.StatusOK {
+        t.Fatalf("update: status %d, want 200 (%s)", w.Code, w.Body)
+    }
+    if mr.Exists("p1") {
+        t.Error("cache entry survived update")
+    }
+
+    w := doRequest(router, http.MethodGet, "/v1/product?id=p1", nil, nil)
+    var got Product
+    if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+        t.Fatal(err)
+    }
+    if w.Code != http.StatusOK || got.Name != "desk lamp" || got.Version != 2 {
+        t.Errorf("get after update: status %d, got %+v, want desk lamp at version 2", w.Code, got)
+    }
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+    "github.com/gmstcl/eCommerce-System/internal/sqlq"
+)
+
+const maxCategoryPageSize = 1000
+
+// limit이 없으면 일치하는 상품을 모두 반환함. 카테고리는 placeholder로만 전달함
+func listProductsByCategory(c *gin.Context) {
+    category := c.Query("category")
+    if category == "" {
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "category is required")
+        return
+    }
+
+    limit := 0
+    if v := c.Query("limit"); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil || n <= 0 || n > maxCategoryPageSize {
+            respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "limit must be between 1 and 1000")
+            return
+        }
+        limit = n
+    }
+
+    offset := 0
+    if v := c.Query("offset"); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil || n < 0 {
+            respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "offset must be a non-negative integer")
+            return
+        }
+        if limit == 0 {
+            respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "offset requires limit")
+            return
+        }
+        offset = n
+    }
+
+    ctx, cancel := backend.WithTimeout(c.Request.Context())
+    defer cancel()
+
+    query := sqlq.New("SELECT id, name, category, version, created_at, updated_at FROM product WHERE category = ? AND deleted_at IS NULL ORDER BY id", category)
+    if limit > 0 {
+        query.Add(" LIMIT ? OFFSET ?", limit, offset)
+    }
+
+    products := []Product{}
+    if err := query.SelectContext(ctx, db, &products); err != nil {
+        logger.ErrorContext(ctx, "Error listing products by category", "category", category, "error", err)
+        respond.BackendError(c, err, "failed to list products")
+        return
+    }
+
+    respond.JSON(c, http.StatusOK, products)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/url"
+    "testing"
+)
+
+func listCategory(t *testing.T, router http.Handler, query string) []Product {
+    t.Helper()
+    w := doRequest(router, http.MethodGet, "/v1/products?"+query, nil, nil)
+    if w.Code != http.StatusOK {
+        t.Fatalf("GET /v1/products?%s: status %d, want 200 (%s)", query, w.Code, w.Body)
+    }
+    var products []Product
+    if err := json.Unmarshal(w.Body.Bytes(), &products); err != nil {
+        t.Fatal(err)
+    }
+    return products
+}
+
+// 같은 카테고리만 id 순으로 반환하고, limit/offset으로 나눠 읽음
+func TestListProductsByCategory(t *testing.T) {
+    useTestDB(t)
+    insertProduct(t, "p2", "mug", "home")
+    insertProduct(t, "p1", "lamp", "home")
+    insertProduct(t, "p3", "ball", "toys")
+    router := newRouter()
+
+    products := listCategory(t, router, "category=home")
+    if len(products) != 2 || products[0].ID != "p1" || products[1].ID != "p2" {
+        t.Errorf("home = %+v, want p1, p2", products)
+    }
+    products = listCategory(t, router, "category=home&limit=1&offset=1")
+    if len(products) != 1 || products[0].ID != "p2" {
+        t.Errorf("home page 2 = %+v, want p2", products)
+    }
+}
+
+// 일치하는 상품이 없으면 null이 아니라 빈 배열임
+func TestListProductsByCategoryEmpty(t *testing.T) {
+    useTestDB(t)
+    insertProduct(t, "p1", "lamp", "home")
+
+    w := doRequest(newRouter(), http.MethodGet, "/v1/products?category=garden", nil, nil)
+    if w.Code != http.StatusOK || w.Body.String() != "[]" {
+        t.Errorf("status %d body %s, want 200 []", w.Code, w.Body)
+    }
+}
+
+// SQL 조각이 든 카테고리는 문자 그대로 비교하므로 다른 행을 돌려주거나 테이블을 바꾸지 못함
+func TestListProductsByCategoryInjection(t *testing.T) {
+    useTestDB(t)
+    insertProduct(t, "p1", "lamp", "home")
+    insertProduct(t, "p2", "odd", "x' OR '1'='1")
+    router := newRouter()
+
+    for _, category := range []string{"home' OR '1'='1", "home'; DROP TABLE product; --"} {
+        if products := listCategory(t, router, "category="+url.QueryEscape(category)); len(products) != 0 {
+            t.Errorf("category %q returned %+v, want none", category, products)
+        }
+    }
+    products := listCategory(t, router, "category="+url.QueryEscape("x' OR '1'='1"))
+    if len(products) != 1 || products[0].ID != "p2" {
+        t.Errorf("literal match = %+v, want p2", products)
+    }
+    if products := listCategory(t, router, "category=home"); len(products) != 1 {
+        t.Errorf("home after injection attempts = %+v, want p1", products)
+    }
+}
+
+func TestListProductsByCategoryBadParams(t *testing.T) {
+    router := newRouter()
+    for _, query := range []string{"", "category=home&limit=0", "category=home&limit=1001", "category=home&offset=5"} {
+        if w := doRequest(router, http.MethodGet, "/v1/products?"+query, nil, nil); w.Code != http.StatusBadRequest {
+            t.Errorf("GET /v1/products?%s: status %d, want 400 (%s)", query, w.Code, w.Body)
+        }
+    }
+}
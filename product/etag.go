@@ -0,0 +1,58 @@
+package main
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "net/http"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+)
+
+// 캐시에 저장하는 형태. 상품 필드 옆에 etag를 함께 두어 캐시 히트 때 해시를 다시 계산하지 않음.
+// 필드가 펼쳐져 저장되므로 etag가 없는 예전 캐시 항목도 그대로 읽힘
+type cachedProduct struct {
+    Product
+    ETag string `json:"etag,omitempty"`
+}
+
+// 응답 본문과 같은 JSON의 해시라 내용이 바뀌면 ETag도 바뀜
+func productETag(product *Product) string {
+    data, err := json.Marshal(product)
+    if err != nil {
+        return ""
+    }
+    sum := sha256.Sum256(data)
+    return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// If-None-Match가 현재 ETag와 같으면 본문 없이 304를 반환함
+func respondProduct(c *gin.Context, product *Product, etag string) {
+    if etag == "" {
+        etag = productETag(product)
+    }
+    if etag != "" {
+        c.Header("ETag", etag)
+        if etagMatches(c.GetHeader("If-None-Match"), etag) {
+            c.Status(http.StatusNotModified)
+            return
+        }
+    }
+    respond.JSON(c, http.StatusOK, product)
+}
+
+// GET의 If-None-Match는 약한 비교를 쓰므로 W/ 접두사는 무시함
+func etagMatches(header, etag string) bool {
+    if header == "" {
+        return false
+    }
+    for _, candidate := range strings.Split(header, ",") {
+        candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+        if candidate == "*" || candidate == etag {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+    "net/http"
+    "testing"
+)
+
+// 같은 ETag를 If-None-Match로 보내면 캐시 히트든 아니든 본문 없는 304이고, 내용이 바뀌면 200과 새 ETag임
+func TestGetProductConditional(t *testing.T) {
+    mr := useMiniredis(t)
+    useTestDB(t)
+    insertProduct(t, "p1", "lamp", "home")
+    router := newRouter()
+
+    w := doRequest(router, http.MethodGet, "/v1/product?id=p1", nil, nil)
+    etag := w.Header().Get("ETag")
+    if w.Code != http.StatusOK || etag == "" {
+        t.Fatalf("first get: status %d ETag %q, want 200 with an ETag", w.Code, etag)
+    }
+
+    for _, header := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+        w = doRequest(router, http.MethodGet, "/v1/product?id=p1", nil, map[string]string{"If-None-Match": header})
+        if w.Code != http.StatusNotModified || w.Body.Len() != 0 || w.Header().Get("ETag") != etag {
+            t.Errorf("If-None-Match %s: status %d body %q ETag %q, want empty 304 with %s", header, w.Code, w.Body, w.Header().Get("ETag"), etag)
+        }
+    }
+    // 캐시에 저장된 ETag와 DB에서 읽어 계산한 ETag가 같음
+    mr.FlushAll()
+    if w = doRequest(router, http.MethodGet, "/v1/product?id=p1", nil, map[string]string{"If-None-Match": etag}); w.Code != http.StatusNotModified {
+        t.Errorf("cache miss: status %d, want 304", w.Code)
+    }
+    w = doRequest(router, http.MethodGet, "/v1/product?id=p1", nil, map[string]string{"If-None-Match": `"other"`})
+    if w.Code != http.StatusOK {
+        t.Errorf("other ETag: status %d, want 200", w.Code)
+    }
+
+    body := map[string]interface{}{"id": "p1", "name": "desk lamp", "category": "home", "version": 1}
+    if w := doRequest(router, http.MethodPut, "/v1/product", body, nil); w.Code != http.StatusOK {
+        t.Fatalf("update: status %d, want 200 (%s)", w.Code, w.Body)
+    }
+    w = doRequest(router, http.MethodGet, "/v1/product?id=p1", nil, map[string]string{"If-None-Match": etag})
+    if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+        t.Errorf("after update: status %d ETag %q, want 200 with a new ETag", w.Code, w.Header().Get("ETag"))
+    }
+}
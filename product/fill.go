@@ -0,0 +1,86 @@
+package main
+
+import (
+    "context"
+    "time"
+
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+)
+
+// 인기 상품의 캐시가 만료되면 동시에 들어온 요청이 모두 DB를 읽게 되므로
+// 복제본 전체에서 한 요청만 DB를 읽어 캐시를 채우고 나머지는 잠금이 풀리길 기다림.
+// 잠금 TTL 안에 풀리지 않으면 기다리던 요청도 직접 DB를 읽음
+const fillPollInterval = 20 * time.Millisecond
+
+func fillLockKey(productID string) string {
+    return "fill:" + productID
+}
+
+func loadProduct(ctx context.Context, productID string) (*Product, error) {
+    if acquireFillLock(ctx, productID) {
+        defer releaseFillLock(ctx, productID)
+        return loadProductFromDB(ctx, productID)
+    }
+
+    if waitForFillLock(ctx, productID) {
+        cached, err := getFromCache(ctx, productID)
+        if err == nil && cached != nil {
+            return &cached.Product, nil
+        }
+    }
+    return loadProductFromDB(ctx, productID)
+}
+
+func loadProductFromDB(ctx context.Context, productID string) (*Product, error) {
+    product, err := getFromDB(ctx, productID)
+    if err != nil {
+        return nil, err
+    }
+    saveToCache(ctx, product)
+    return product, nil
+}
+
+// Redis 오류 시에는 잠금 없이 DB를 읽도록 true를 반환함
+func acquireFillLock(ctx context.Context, productID string) bool {
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    ok, err := redisConn.Client().SetNX(ctx, fillLockKey(productID), 1, fillLockTTL).Result()
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to acquire fill lock", "product_id", productID, "error", err)
+        return true
+    }
+    return ok
+}
+
+func releaseFillLock(ctx context.Context, productID string) {
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := redisConn.Client().Del(ctx, fillLockKey(productID)).Err(); err != nil {
+        logger.ErrorContext(ctx, "Failed to release fill lock", "product_id", productID, "error", err)
+    }
+}
+
+// 잠금이 fillLockTTL 안에 풀리면 true
+func waitForFillLock(ctx context.Context, productID string) bool {
+    deadline := clock.Now().Add(fillLockTTL)
+    for clock.Now().Before(deadline) {
+        select {
+        case <-ctx.Done():
+            return false
+        case <-time.After(fillPollInterval):
+        }
+
+        n, err := redisConn.Client().Exists(ctx, fillLockKey(productID)).Result()
+        if err != nil {
+            logger.ErrorContext(ctx, "Failed to check fill lock", "product_id", productID, "error", err)
+            return false
+        }
+        if n == 0 {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+    "context"
+    "database/sql"
+    "database/sql/driver"
+    "errors"
+    "io"
+    "net/http"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/jmoiron/sqlx"
+)
+
+// Connector로만 여는 테스트 드라이버가 Driver()로 돌려주는 값
+type connectorDriver struct{}
+
+func (connectorDriver) Open(string) (driver.Conn, error) {
+    return nil, errors.New("use the connector")
+}
+
+// 준비된 SELECT마다 delay만큼 걸려 상품 한 행을 돌려주고 실행 횟수를 세는 드라이버
+type countingConnector struct {
+    queries atomic.Int32
+    delay   time.Duration
+}
+
+func (c *countingConnector) Connect(context.Context) (driver.Conn, error) {
+    return &countingConn{c}, nil
+}
+
+func (c *countingConnector) Driver() driver.Driver {
+    return connectorDriver{}
+}
+
+type countingConn struct {
+    c *countingConnector
+}
+
+func (c *countingConn) Prepare(string) (driver.Stmt, error) {
+    return &countingStmt{c.c}, nil
+}
+
+func (c *countingConn) Close() error {
+    return nil
+}
+
+func (c *countingConn) Begin() (driver.Tx, error) {
+    return nil, errors.New("transactions not supported")
+}
+
+type countingStmt struct {
+    c *countingConnector
+}
+
+func (s *countingStmt) Close() error {
+    return nil
+}
+
+func (s *countingStmt) NumInput() int {
+    return -1
+}
+
+func (s *countingStmt) Exec([]driver.Value) (driver.Result, error) {
+    return nil, errors.New("exec not supported")
+}
+
+func (s *countingStmt) Query(args []driver.Value) (driver.Rows, error) {
+    s.c.queries.Add(1)
+    time.Sleep(s.c.delay)
+    return &productRows{id: args[0].(string)}, nil
+}
+
+type productRows struct {
+    id   string
+    done bool
+}
+
+func (r *productRows) Columns() []string {
+    return []string{"id", "name", "category", "version", "created_at", "updated_at"}
+}
+
+func (r *productRows) Close() error {
+    return nil
+}
+
+func (r *productRows) Next(dest []driver.Value) error {
+    if r.done {
+        return io.EOF
+    }
+    r.done = true
+    now := time.Now().UTC()
+    dest[0], dest[1], dest[2], dest[3], dest[4], dest[5] = r.id, "lamp", "home", int64(1), now, now
+    return nil
+}
+
+// 조회 문장을 세는 드라이버로 준비해 끼움
+func useCountingDB(t *testing.T, delay time.Duration) *countingConnector {
+    t.Helper()
+    counter := &countingConnector{delay: delay}
+    conn := sqlx.NewDb(sql.OpenDB(counter), "mysql")
+    stmt, err := conn.Preparex("SELECT id, name, category, version, created_at, updated_at FROM product WHERE id = ? AND deleted_at IS NULL")
+    if err != nil {
+        t.Fatal(err)
+    }
+    prevDB, prevStmt := db, selectProductStmt
+    db, selectProductStmt = conn, stmt
+    t.Cleanup(func() {
+        db, selectProductStmt = prevDB, prevStmt
+        conn.Close()
+    })
+    return counter
+}
+
+// 캐시가 빈 인기 상품에 동시에 50개 요청이 와도 DB는 한 번만 읽음
+func TestConcurrentMissesReadDBOnce(t *testing.T) {
+    useMiniredis(t)
+    counter := useCountingDB(t, 50*time.Millisecond)
+    router := newRouter()
+
+    var wg sync.WaitGroup
+    codes := make([]int, 50)
+    for i := range codes {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            codes[i] = doRequest(router, http.MethodGet, "/v1/product?id=p1", nil, nil).Code
+        }(i)
+    }
+    wg.Wait()
+
+    for i, code := range codes {
+        if code != http.StatusOK {
+            t.Fatalf("request %d: status %d, want 200", i, code)
+        }
+    }
+    if n := counter.queries.Load(); n != 1 {
+        t.Errorf("DB read %d times, want 1", n)
+    }
+}
+
+// 다른 복제본이 채우기 잠금을 쥐고 있으면 DB를 읽지 않고 기다렸다가 그 복제본이 채운 캐시를 씀
+func TestFillLockWaitersUseCache(t *testing.T) {
+    mr := useMiniredis(t)
+    counter := useCountingDB(t, 0)
+    ctx := context.Background()
+    mr.Set(fillLockKey("p1"), "1")
+
+    go func() {
+        time.Sleep(100 * time.Millisecond)
+        saveToCache(ctx, &Product{ID: "p1", Name: "from other replica", Category: "home", Version: 1})
+        mr.Del(fillLockKey("p1"))
+    }()
+
+    product, err := loadProduct(ctx, "p1")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if product.Name != "from other replica" {
+        t.Errorf("got %q, want the product cached by the lock holder", product.Name)
+    }
+    if n := counter.queries.Load(); n != 0 {
+        t.Errorf("DB read %d times, want 0", n)
+    }
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "log"
+    "net/url"
+    "os"
+    "strconv"
+
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/chaos"
+    "github.com/gmstcl/eCommerce-System/internal/tracing"
+)
+
+// 재고의 원본은 order 서비스와 같은 INVENTORY_TABLE(DynamoDB)이며 MySQL에는 재고를 두지 않음.
+// 주문 생성이 주문 저장과 재고 차감을 한 트랜잭션으로 처리해야 하므로 DynamoDB 쪽이 재고를 가짐.
+// 파티션 키는 productid(S), 재고 수량은 inventory(N). 이 서비스는 조회, 초기값 기록, 조정만 함.
+// INVENTORY_TABLE이 없으면 재고를 다루지 않으며 상품 응답에 stock이 빠지고 재고 조정은 501을 반환함
+var (
+    inventoryTable = os.Getenv("INVENTORY_TABLE")
+    dynamoEndpoint = os.Getenv("DYNAMODB_ENDPOINT")
+    dynamoClient   *dynamodb.Client
+)
+
+var errStockUnderflow = errors.New("stock would become negative")
+
+func initInventory(cfg aws.Config) {
+    if inventoryTable == "" {
+        return
+    }
+    if dynamoEndpoint != "" {
+        // 로컬 개발용. DynamoDB Local이나 LocalStack 주소
+        if u, err := url.Parse(dynamoEndpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+            log.Fatalf("invalid DYNAMODB_ENDPOINT %q (want an http or https URL)", dynamoEndpoint)
+        }
+    }
+    dynamoClient = dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+        if dynamoEndpoint != "" {
+            o.BaseEndpoint = aws.String(dynamoEndpoint)
+        }
+    })
+}
+
+func inventoryKey(productID string) map[string]types.AttributeValue {
+    return map[string]types.AttributeValue{
+        "productid": &types.AttributeValueMemberS{Value: productID},
+    }
+}
+
+func inventoryValue(item map[string]types.AttributeValue) (int, error) {
+    n, ok := item["inventory"].(*types.AttributeValueMemberN)
+    if !ok {
+        return 0, nil
+    }
+    return strconv.Atoi(n.Value)
+}
+
+// 재고 항목이 없는 상품은 재고 0으로 봄. order 서비스도 항목이 없으면 재고 부족으로 처리함
+func getStock(ctx context.Context, productID string) (int, error) {
+    ctx, span := tracing.Start(ctx, "getStock", "product_id", productID)
+    defer span.End()
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    result, err := dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+        TableName:      aws.String(inventoryTable),
+        Key:            inventoryKey(productID),
+        ConsistentRead: aws.Bool(true),
+    })
+    if err != nil {
+        logger.ErrorContext(ctx, "Error fetching stock from DynamoDB", "product_id", productID, "error", err)
+        return 0, err
+    }
+    return inventoryValue(result.Item)
+}
+
+// 상품 생성 시 초기 재고를 기록함. 이미 재고 항목이 있으면 주문이 차감한 값을 덮어쓰지 않도록 그대로 둠
+func seedStock(ctx context.Context, productID string, stock int) error {
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    _, err := dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+        TableName: aws.String(inventoryTable),
+        Item: map[string]types.AttributeValue{
+            "productid": &types.AttributeValueMemberS{Value: productID},
+            "inventory": &types.AttributeValueMemberN{Value: strconv.Itoa(stock)},
+        },
+        ConditionExpression: aws.String("attribute_not_exists(productid)"),
+    })
+    var conditionErr *types.ConditionalCheckFailedException
+    if errors.As(err, &conditionErr) {
+        logger.WarnContext(ctx, "Inventory item already exists, keeping current stock", "product_id", productID)
+        return nil
+    }
+    if err != nil {
+        logger.ErrorContext(ctx, "Error seeding stock in DynamoDB", "product_id", productID, "error", err)
+    }
+    return err
+}
+
+// ADD로 조정해 동시에 들어온 주문의 차감과 섞여도 값을 잃지 않음. 줄이는 경우 남은 재고가 부족하면
+// errStockUnderflow와 함께 현재 재고를 반환함
+func adjustStockInInventory(ctx context.Context, productID string, delta int) (int, error) {
+    ctx, span := tracing.Start(ctx, "adjustStockInInventory", "product_id", productID)
+    defer span.End()
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosDBFailRate); err != nil {
+        return 0, err
+    }
+
+    input := &dynamodb.UpdateItemInput{
+        TableName:        aws.String(inventoryTable),
+        Key:              inventoryKey(productID),
+        UpdateExpression: aws.String("ADD inventory :delta"),
+        ExpressionAttributeValues: map[string]types.AttributeValue{
+            ":delta": &types.AttributeValueMemberN{Value: strconv.Itoa(delta)},
+        },
+        ReturnValues: types.ReturnValueUpdatedNew,
+    }
+    if delta < 0 {
+        input.ConditionExpression = aws.String("inventory >= :required")
+        input.ExpressionAttributeValues[":required"] = &types.AttributeValueMemberN{Value: strconv.Itoa(-delta)}
+    }
+
+    result, err := dynamoClient.UpdateItem(ctx, input)
+    var conditionErr *types.ConditionalCheckFailedException
+    if errors.As(err, &conditionErr) {
+        stock, err := getStock(ctx, productID)
+        if err != nil {
+            return 0, err
+        }
+        return stock, errStockUnderflow
+    }
+    if err != nil {
+        logger.ErrorContext(ctx, "Error adjusting stock in DynamoDB", "product_id", productID, "error", err)
+        return 0, err
+    }
+
+    stock, err := inventoryValue(result.Attributes)
+    if err != nil {
+        return 0, err
+    }
+    logger.InfoContext(ctx, "Adjusted stock", "product_id", productID, "delta", delta, "stock", stock)
+    return stock, nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "io"
+    "log/slog"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "strconv"
+    "strings"
+    "sync"
+    "testing"
+
+    "github.com/alicebob/miniredis/v2"
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/credentials"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/migrate"
+    "github.com/gmstcl/eCommerce-System/internal/mysqltest"
+    "github.com/go-redis/redis/v8"
+    "github.com/jmoiron/sqlx"
+)
+
+func TestMain(m *testing.M) {
+    gin.SetMode(gin.TestMode)
+    gin.DefaultWriter = io.Discard
+    logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+    slog.SetDefault(logger)
+    os.Exit(m.Run())
+}
+
+// 빈 인메모리 MySQL에 연결하고 db를 바꿔 끼움
+func connectTestDB(t testing.TB) *sqlx.DB {
+    t.Helper()
+    conn, err := sqlx.Connect("mysql", mysqltest.Start(t))
+    if err != nil {
+        t.Fatal(err)
+    }
+    prev := db
+    db = conn
+    t.Cleanup(func() {
+        conn.Close()
+        db = prev
+    })
+    return conn
+}
+
+// 마이그레이션을 적용하고 준비된 문장까지 만든 DB
+func useTestDB(t testing.TB) *sqlx.DB {
+    t.Helper()
+    conn := connectTestDB(t)
+    if err := migrate.Run(context.Background(), db, migrationFiles); err != nil {
+        t.Fatal(err)
+    }
+    prepareStatements()
+    return conn
+}
+
+func useMiniredis(t *testing.T) *miniredis.Miniredis {
+    t.Helper()
+    mr := miniredis.RunT(t)
+    client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+    prev := redisConn.Replace(client)
+    t.Cleanup(func() {
+        client.Close()
+        redisConn.Replace(prev)
+    })
+    return mr
+}
+
+func insertProduct(t *testing.T, id, name, category string) {
+    t.Helper()
+    if _, err := db.Exec("INSERT INTO product (id, name, category) VALUES (?, ?, ?)", id, name, category); err != nil {
+        t.Fatal(err)
+    }
+}
+
+// 가짜 DynamoDB로 받은 요청 하나. Body는 요청 JSON을 그대로 디코딩한 값
+type dynamoCall struct {
+    Op   string
+    Body map[string]interface{}
+}
+
+// DYNAMODB_ENDPOINT와 같은 방식으로 SDK 클라이언트를 httptest 서버에 연결함.
+// handle은 작업 이름(GetItem 등)과 요청 본문을 받아 상태 코드와 응답 본문을 돌려줌
+type fakeDynamo struct {
+    mu     sync.Mutex
+    calls  []dynamoCall
+    handle func(op string, body map[string]interface{}) (int, interface{})
+}
+
+func newFakeDynamo(t *testing.T, handle func(op string, body map[string]interface{}) (int, interface{})) *fakeDynamo {
+    t.Helper()
+    f := &fakeDynamo{handle: handle}
+    srv := httptest.NewServer(http.HandlerFunc(f.serve))
+    t.Cleanup(srv.Close)
+
+    prev := dynamoClient
+    dynamoClient = dynamodb.New(dynamodb.Options{
+        Region:       "us-east-1",
+        BaseEndpoint: aws.String(srv.URL),
+        Credentials:  credentials.NewStaticCredentialsProvider("test", "test", ""),
+        Retryer:      aws.NopRetryer{},
+    })
+    t.Cleanup(func() { dynamoClient = prev })
+    return f
+}
+
+func (f *fakeDynamo) serve(w http.ResponseWriter, r *http.Request) {
+    _, op, _ := strings.Cut(r.Header.Get("X-Amz-Target"), ".")
+    var body map[string]interface{}
+    json.NewDecoder(r.Body).Decode(&body)
+
+    f.mu.Lock()
+    f.calls = append(f.calls, dynamoCall{Op: op, Body: body})
+    f.mu.Unlock()
+
+    status, resp := http.StatusOK, interface{}(map[string]interface{}{})
+    if f.handle != nil {
+        status, resp = f.handle(op, body)
+    }
+    w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+    w.WriteHeader(status)
+    json.NewEncoder(w).Encode(resp)
+}
+
+// op 작업으로 받은 요청들. op가 빈 문자열이면 전부
+func (f *fakeDynamo) callsTo(op string) []dynamoCall {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    var calls []dynamoCall
+    for _, call := range f.calls {
+        if op == "" || call.Op == op {
+            calls = append(calls, call)
+        }
+    }
+    return calls
+}
+
+func dynamoError(errType, message string) (int, interface{}) {
+    return http.StatusBadRequest, map[string]interface{}{
+        "__type":  "com.amazonaws.dynamodb.v20120810#" + errType,
+        "message": message,
+    }
+}
+
+func inventoryAttrs(stock int) map[string]interface{} {
+    return map[string]interface{}{"inventory": map[string]string{"N": strconv.Itoa(stock)}}
+}
+
+func doRequest(router http.Handler, method, path string, body interface{}, headers map[string]string) *httptest.ResponseRecorder {
+    var reader io.Reader
+    switch b := body.(type) {
+    case nil:
+    case string:
+        reader = strings.NewReader(b)
+    default:
+        data, _ := json.Marshal(b)
+        reader = bytes.NewReader(data)
+    }
+    req := httptest.NewRequest(method, path, reader)
+    if body != nil {
+        req.Header.Set("Content-Type", "application/json")
+    }
+    for k, v := range headers {
+        req.Header.Set(k, v)
+    }
+    w := httptest.NewRecorder()
+    router.ServeHTTP(w, req)
+    return w
+}
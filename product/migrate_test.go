@@ -0,0 +1,103 @@
+package main
+
+import (
+    "context"
+    "reflect"
+    "testing"
+    "time"
+
+    "github.com/gmstcl/eCommerce-System/internal/migrate"
+)
+
+var migratedProductColumns = []string{"id", "name", "category", "created_at", "updated_at", "deleted_at", "version"}
+
+func tableColumns(t *testing.T, table string) []string {
+    t.Helper()
+    var columns []string
+    err := db.Select(&columns, "SELECT column_name FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position", table)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return columns
+}
+
+func hasIndex(t *testing.T, table, index string) bool {
+    t.Helper()
+    var n int
+    err := db.Get(&n, "SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = ? AND index_name = ?", table, index)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return n > 0
+}
+
+func TestMigrationsOnEmptyDB(t *testing.T) {
+    connectTestDB(t)
+    ctx := context.Background()
+
+    if err := migrate.Run(ctx, db, migrationFiles); err != nil {
+        t.Fatal(err)
+    }
+    if got := tableColumns(t, "product"); !reflect.DeepEqual(got, migratedProductColumns) {
+        t.Errorf("columns %v, want %v", got, migratedProductColumns)
+    }
+    if !hasIndex(t, "product", "idx_product_category") {
+        t.Error("idx_product_category missing")
+    }
+
+    // 두 번째 실행은 적용된 파일을 건너뜀
+    if err := migrate.Run(ctx, db, migrationFiles); err != nil {
+        t.Fatalf("second run: %v", err)
+    }
+    var applied int
+    if err := db.Get(&applied, "SELECT COUNT(*) FROM schema_migrations"); err != nil {
+        t.Fatal(err)
+    }
+    if migrations, _ := migrate.Load(migrationFiles); applied != len(migrations) {
+        t.Errorf("schema_migrations has %d rows, want %d", applied, len(migrations))
+    }
+}
+
+// 러너가 생기기 전의 배포 환경. 처음 스키마의 테이블에 데이터가 있고 schema_migrations는 없음
+func TestMigrationsOnBaselineDB(t *testing.T) {
+    connectTestDB(t)
+    ctx := context.Background()
+
+    db.MustExec("CREATE TABLE product (id VARCHAR(64) NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, category VARCHAR(255) NOT NULL)")
+    db.MustExec("INSERT INTO product (id, name, category) VALUES ('p1', 'pen', 'office')")
+
+    if err := migrate.Run(ctx, db, migrationFiles); err != nil {
+        t.Fatal(err)
+    }
+    if got := tableColumns(t, "product"); !reflect.DeepEqual(got, migratedProductColumns) {
+        t.Errorf("columns %v, want %v", got, migratedProductColumns)
+    }
+    if !hasIndex(t, "product", "idx_product_category") {
+        t.Error("idx_product_category missing")
+    }
+
+    var row struct {
+        Name      string
+        Version   int
+        CreatedAt time.Time  `db:"created_at"`
+        DeletedAt *time.Time `db:"deleted_at"`
+    }
+    if err := db.Get(&row, "SELECT name, version, created_at, deleted_at FROM product WHERE id = 'p1'"); err != nil {
+        t.Fatal(err)
+    }
+    if row.Name != "pen" || row.Version != 1 || row.CreatedAt.IsZero() || row.DeletedAt != nil {
+        t.Errorf("existing row after migration %+v, want name pen, version 1, created_at set, not deleted", row)
+    }
+}
+
+func TestBaselineMigrationMatchesFirstSchema(t *testing.T) {
+    connectTestDB(t)
+    migrations, err := migrate.Load(migrationFiles)
+    if err != nil {
+        t.Fatal(err)
+    }
+    db.MustExec(migrations[0].SQL)
+    if got, want := tableColumns(t, "product"), []string{"id", "name", "category"}; !reflect.DeepEqual(got, want) {
+        t.Errorf("0001 creates %v, want the first schema %v", got, want)
+    }
+}
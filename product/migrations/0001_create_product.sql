@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS product (
+    id       VARCHAR(64)  NOT NULL PRIMARY KEY,
+    name     VARCHAR(255) NOT NULL,
+    category VARCHAR(255) NOT NULL
+);
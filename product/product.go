@@ -2,25 +2,98 @@ package main
 
 import (
     "context"
-    "crypto/tls"
-    "encoding/json"
+    "embed"
+    "database/sql"
+    "errors"
+    "flag"
     "fmt"
     "log"
     "net/http"
     "os"
+    "strconv"
+    "time"
 
+    "github.com/aws/aws-sdk-go-v2/aws"
     "github.com/aws/aws-sdk-go-v2/config"
     "github.com/aws/aws-sdk-go-v2/service/rdsdata"
     "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/apikey"
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/bodylimit"
+    "github.com/gmstcl/eCommerce-System/internal/cacheaside"
+    "github.com/gmstcl/eCommerce-System/internal/cachestats"
+    "github.com/gmstcl/eCommerce-System/internal/chaos"
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+    "github.com/gmstcl/eCommerce-System/internal/cors"
+    "github.com/gmstcl/eCommerce-System/internal/dbreconnect"
+    "github.com/gmstcl/eCommerce-System/internal/env"
+    "github.com/gmstcl/eCommerce-System/internal/healthz"
+    "github.com/gmstcl/eCommerce-System/internal/ids"
+    "github.com/gmstcl/eCommerce-System/internal/jwtauth"
+    "github.com/gmstcl/eCommerce-System/internal/logging"
+    "github.com/gmstcl/eCommerce-System/internal/metrics"
+    "github.com/gmstcl/eCommerce-System/internal/migrate"
+    "github.com/gmstcl/eCommerce-System/internal/redisconn"
+    "github.com/gmstcl/eCommerce-System/internal/requestid"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+    "github.com/gmstcl/eCommerce-System/internal/server"
+    "github.com/gmstcl/eCommerce-System/internal/sqlq"
+    "github.com/gmstcl/eCommerce-System/internal/tracing"
+    "go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+    "github.com/prometheus/client_golang/prometheus/promhttp"
     "github.com/go-redis/redis/v8"
     "github.com/jmoiron/sqlx"
     _ "github.com/go-sql-driver/mysql"
+    "golang.org/x/sync/singleflight"
 )
 
 var db *sqlx.DB
-var redisClient *redis.Client
 var rdsClient *rdsdata.Client
-var ctx = context.Background()
+var cacheTTL = 300 * time.Second
+var dbReads singleflight.Group
+var redisConn redisconn.Conn
+
+// 스키마 변경은 migrations/에 새 번호의 파일로 추가함. 실행 규칙은 internal/migrate에 있음
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var errVersionConflict = errors.New("product version mismatch")
+
+// 요청마다 SQL을 다시 파싱하지 않도록 시작 시 한 번 준비해 재사용함
+var (
+    selectProductStmt *sqlx.Stmt
+    insertProductStmt *sqlx.Stmt
+    updateProductStmt *sqlx.Stmt
+    deleteProductStmt *sqlx.Stmt
+    // include_deleted 조회용. 나머지 문장은 삭제된 행을 제외함
+    selectProductWithDeletedStmt *sqlx.Stmt
+)
+
+const serviceName = "product"
+
+var logger = logging.New(serviceName)
+
+// 게임데이용 장애 주입. 환경 변수가 없거나 0이면 아무 동작도 하지 않음
+var (
+    chaosDBFailRate    = chaos.Rate(logger, "CHAOS_DB_FAIL_RATE")
+    chaosCacheFailRate = chaos.Rate(logger, "CHAOS_CACHE_FAIL_RATE")
+)
+
+// 이 중 하나라도 비어 있으면 시작하지 않음
+var requiredEnv = []string{"MYSQL_USER", "MYSQL_HOST", "MYSQL_PORT", "MYSQL_DBNAME", "REDIS_HOST", "REDIS_PORT"}
+
+// API_KEYS가 비어 있으면 인증을 하지 않음 (로컬 개발용)
+var apiKeys = apikey.Parse(os.Getenv("API_KEYS"))
+
+// /healthz가 확인하는 의존성. Critical이 아닌 의존성은 실패해도 degraded로만 보고함
+var healthChecks = map[string]healthz.Check{
+    "mysql": {Run: func(ctx context.Context) error {
+        return db.PingContext(ctx)
+    }, Critical: true},
+    "redis": {Run: func(ctx context.Context) error {
+        return redisConn.Check(ctx)
+    }},
+}
 
 var (
     mysqlUser     = os.Getenv("MYSQL_USER")
@@ -30,154 +103,642 @@ var (
     mysqlDbName   = os.Getenv("MYSQL_DBNAME")
     redisAddr     = os.Getenv("REDIS_HOST")
     redisPort     = os.Getenv("REDIS_PORT")
-    region        = os.Getenv("REGION")
+    region        string
+    dedupeTTL     = 3 * time.Second
+    fillLockTTL   = 2 * time.Second
 )
 
+// created_at/updated_at은 서버가 쓰기 시점에 채우며 요청 본문의 값은 무시함.
+// 컬럼은 migrations/에서 추가함.
+// deleted_at이 있는 행은 소프트 삭제된 상품으로 보고 일반 조회에서 제외함.
+// stock은 MySQL 컬럼이 아니라 INVENTORY_TABLE에서 읽어 단건 조회와 재고 조정 응답에만 채움(inventory.go).
+// 생성 시 본문 값을 초기 재고로 기록하고 이후에는 POST /v1/product/:id/stock과 주문 생성으로만 바뀜.
+// version은 PUT마다 1씩 올라가며, PUT 본문에는 마지막으로 읽은 version을 넣어야 함
 type Product struct {
-    ID       string `json:"id"`
-    Name     string `json:"name"`
-    Category string `json:"category"`
+    ID        string     `json:"id"`
+    Name      string     `json:"name"`
+    Category  string     `json:"category"`
+    Stock     *int       `json:"stock,omitempty" db:"-"`
+    Version   int        `json:"version"`
+    CreatedAt time.Time  `json:"createdat" db:"created_at"`
+    UpdatedAt time.Time  `json:"updatedat" db:"updated_at"`
+    DeletedAt *time.Time `json:"deletedat,omitempty" db:"deleted_at"`
 }
 
-func init() {
+// MySQL DATETIME(6)에 저장되는 정밀도에 맞춰 응답과 DB 값이 같도록 함
+func recordTimestamp() time.Time {
+    return clock.Now().UTC().Truncate(time.Microsecond)
+}
+
+// 테스트가 환경 변수와 AWS 자격 증명 없이 패키지를 불러올 수 있도록 init 대신 main에서 호출함
+func initService() {
+    env.Require(requiredEnv)
+    region = env.Region(logger)
+
     cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
     if err != nil {
         log.Fatalf("unable to load SDK config, %v", err)
     }
+    checkAWSCredentials(cfg)
     rdsClient = rdsdata.NewFromConfig(cfg)
+    initInventory(cfg)
+
+    // 0이나 잘못된 값이면 만료 없는 캐시 대신 기본 TTL을 사용함
+    if v := os.Getenv("CACHE_TTL_SECONDS"); v != "" {
+        if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+            cacheTTL = time.Duration(seconds) * time.Second
+        } else {
+            logger.Warn("Ignoring invalid CACHE_TTL_SECONDS", "value", v, "cache_ttl", cacheTTL.String())
+        }
+    }
+    if v := os.Getenv("AVAILABILITY_CACHE_TTL_SECONDS"); v != "" {
+        if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+            availabilityTTL = time.Duration(seconds) * time.Second
+        } else {
+            logger.Warn("Ignoring invalid AVAILABILITY_CACHE_TTL_SECONDS", "value", v, "availability_cache_ttl", availabilityTTL.String())
+        }
+    }
+
+    redisDB := 0
+    if v := os.Getenv("REDIS_DB"); v != "" {
+        redisDB, err = strconv.Atoi(v)
+        if err != nil || redisDB < 0 {
+            log.Fatalf("invalid REDIS_DB %q (want a non-negative integer)", v)
+        }
+    }
 
-    redisClient = redis.NewClient(&redis.Options{
+    redisOptions := &redis.Options{
         Addr:     fmt.Sprintf("%s:%s", redisAddr, redisPort),
-        TLSConfig: &tls.Config{},  
-    })
+        DB:       redisDB,
+        TLSConfig: redisconn.TLSConfig(),
+    }
+    redisconn.ApplyPoolOptions(redisOptions)
+
+    // 같은 id의 생성 요청이 짧은 시간 안에 중복으로 들어오는 경우를 막기 위한 윈도우
+    if v := os.Getenv("PRODUCT_DEDUPE_TTL_SECONDS"); v != "" {
+        seconds, err := strconv.Atoi(v)
+        if err != nil || seconds <= 0 {
+            log.Fatalf("invalid PRODUCT_DEDUPE_TTL_SECONDS %q", v)
+        }
+        dedupeTTL = time.Duration(seconds) * time.Second
+    }
+
+    // 캐시 미스 시 DB를 읽는 요청 하나가 잠금을 쥐는 최대 시간
+    if v := os.Getenv("PRODUCT_FILL_LOCK_TTL_MS"); v != "" {
+        ms, err := strconv.Atoi(v)
+        if err != nil || ms <= 0 {
+            log.Fatalf("invalid PRODUCT_FILL_LOCK_TTL_MS %q", v)
+        }
+        fillLockTTL = time.Duration(ms) * time.Millisecond
+    }
 
-    checkRedisConnection()
+    respond.InitKeyStyle()
+    redisConn.Connect(redisOptions)
+    logEffectiveConfig()
 }
 
-func checkRedisConnection() {
-    _, err := redisClient.Ping(ctx).Result()
-    if err != nil {
-        log.Printf("Redis connection error: %v", err)
-    } else {
-        log.Println("Connected to Redis successfully")
+func logEffectiveConfig() {
+    logger.Info("effective config",
+        "mysql", fmt.Sprintf("%s@%s:%s/%s", mysqlUser, mysqlHost, mysqlPort, mysqlDbName),
+        "mysql_password", maskSecret(mysqlPassword),
+        "redis", fmt.Sprintf("%s:%s/%d", redisAddr, redisPort, redisConn.Options().DB),
+        "redis_tls", redisConn.Options().TLSConfig != nil,
+        "redis_pool_size", redisConn.Options().PoolSize,
+        "redis_dial_timeout", redisConn.Options().DialTimeout.String(),
+        "redis_read_timeout", redisConn.Options().ReadTimeout.String(),
+        "cache_ttl", cacheTTL.String(),
+        "availability_cache_ttl", availabilityTTL.String(),
+        "backend_timeout", backend.Timeout.String(),
+        "json_key_style", respond.KeyStyle,
+        "db_reconnect_retries", dbreconnect.Retries,
+        "api_key_auth", len(apiKeys) > 0,
+        "cors_origins", cors.AllowedOrigins,
+        "max_body_bytes", bodylimit.Max,
+        "max_batch_body_bytes", bodylimit.MaxBatch,
+        "jwt_auth", jwtauth.Enabled(),
+        "aws_region", region,
+        "inventory_table", inventoryTable,
+        "dynamodb_endpoint", dynamoEndpoint,
+        "dedupe_ttl", dedupeTTL.String(),
+        "fill_lock_ttl", fillLockTTL.String(),
+    )
+}
+
+func maskSecret(v string) string {
+    if v == "" {
+        return "(unset)"
+    }
+    return "****"
+}
+
+// LoadDefaultConfig는 자격 증명이 없어도 성공하므로 시작 시점에 한 번 확인함.
+// AWS_CREDENTIALS_REQUIRED=true이면 경고 대신 종료함
+func checkAWSCredentials(cfg aws.Config) {
+    credCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+
+    if _, err := cfg.Credentials.Retrieve(credCtx); err != nil {
+        if os.Getenv("AWS_CREDENTIALS_REQUIRED") == "true" {
+            log.Fatalf("no AWS credentials could be resolved: %v", err)
+        }
+        logger.Warn("no AWS credentials could be resolved, AWS calls will fail", "error", err)
     }
 }
 
 func main() {
+    initService()
+
     var err error
-    dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", mysqlUser, mysqlPassword, mysqlHost, mysqlPort, mysqlDbName)
+    // clientFoundRows: 값이 같아 바뀌지 않은 행도 UPDATE 결과에 포함시켜 404 판단에 씀
+    // parseTime: created_at/updated_at을 time.Time으로 읽음
+    dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?clientFoundRows=true&parseTime=true", mysqlUser, mysqlPassword, mysqlHost, mysqlPort, mysqlDbName)
 
     db, err = sqlx.Connect("mysql", dsn)
     if err != nil {
         log.Fatalf("failed to connect to RDS: %v", err)
     }
+    // 준비된 문장이 참조하는 테이블이 있어야 하므로 prepareStatements보다 먼저 실행함
+    if migrate.OnStart() {
+        if err := migrate.Run(context.Background(), db, migrationFiles); err != nil {
+            log.Fatalf("failed to run migrations: %v", err)
+        }
+    }
+    prepareStatements()
 
+    flag.Parse()
+    if selfTestRequested() {
+        os.Exit(runSelfTest())
+    }
+
+    stopRedisMonitor := redisConn.StartMonitor()
+
+    stopTracing := tracing.Init(serviceName, logger)
+
+    server.Run(newRouter(), func() {
+        selectProductStmt.Close()
+        insertProductStmt.Close()
+        updateProductStmt.Close()
+        deleteProductStmt.Close()
+        selectProductWithDeletedStmt.Close()
+        if err := db.Close(); err != nil {
+            logger.Error("Failed to close DB", "error", err)
+        }
+        stopRedisMonitor()
+        if err := redisConn.Client().Close(); err != nil {
+            logger.Error("Failed to close Redis client", "error", err)
+        }
+        stopTracing()
+    })
+}
+
+func newRouter() *gin.Engine {
     router := gin.Default()
+    router.Use(otelgin.Middleware(serviceName))
+    router.Use(requestid.Middleware())
+    router.Use(cors.Middleware())
+    router.Use(bodylimit.Middleware())
+    // gin은 등록 시점까지의 미들웨어만 붙이므로 스크레이퍼가 키나 토큰 없이 읽도록 인증보다 먼저 등록함
+    router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+    router.Use(metrics.Middleware())
+    router.Use(jwtauth.Middleware(len(apiKeys) > 0))
+    router.Use(apikey.Middleware(apiKeys, func(c *gin.Context) bool { return jwtauth.Subject(c) != "" }))
 
     router.GET("/v1/product", getProduct)
     router.POST("/v1/product", createProduct)
+    router.PUT("/v1/product", updateProduct)
+    router.DELETE("/v1/product", deleteProduct)
+    router.POST("/v1/product/:id/stock", adjustStock)
+    router.GET("/v1/product/availability", getAvailability)
+    router.GET("/v1/product/audit", auditProducts)
+    router.GET("/v1/products", listProductsByCategory)
+    router.GET("/v1/products/search", searchProducts)
+    router.GET("/healthz", healthz.Handler(healthChecks))
+    router.GET("/v1/cache/report", cachestats.Report)
+    return router
+}
 
-    router.Run(":8080")
+func prepareStatements() {
+    selectProductStmt = mustPrepare(sqlq.Statement("SELECT id, name, category, version, created_at, updated_at FROM product WHERE id = ? AND deleted_at IS NULL"))
+    insertProductStmt = mustPrepare(sqlq.Statement("INSERT INTO product (id, name, category, version, created_at, updated_at) VALUES (?, ?, ?, 1, ?, ?)"))
+    updateProductStmt = mustPrepare(sqlq.Statement("UPDATE product SET name = ?, category = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL"))
+    deleteProductStmt = mustPrepare(sqlq.Statement("UPDATE product SET deleted_at = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL"))
+    selectProductWithDeletedStmt = mustPrepare(sqlq.Statement("SELECT id, name, category, version, created_at, updated_at, deleted_at FROM product WHERE id = ?"))
+}
+
+// sqlq.Statement의 결과를 그대로 받아 준비함. 시작할 때만 호출하므로 실패하면 종료함
+func mustPrepare(query string, err error) *sqlx.Stmt {
+    if err == nil {
+        var stmt *sqlx.Stmt
+        if stmt, err = db.Preparex(query); err == nil {
+            return stmt
+        }
+    }
+    log.Fatalf("failed to prepare statement %q: %v", query, err)
+    return nil
 }
 
 func getProduct(c *gin.Context) {
+    ctx := c.Request.Context()
     productID := c.DefaultQuery("id", "")
 
-    productData, err := getFromCache(productID)
+    if c.Query("include_deleted") == "true" {
+        getProductWithDeleted(c, productID)
+        return
+    }
+
+    cached, err := getFromCache(ctx, productID)
     if err != nil {
-        log.Printf("Failed to fetch from cache for productID %s: %v", productID, err)
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch from cache"})
+        logger.ErrorContext(ctx, "Failed to fetch from cache", "product_id", productID, "error", err)
+        respond.BackendError(c, err, "failed to fetch from cache")
+        return
+    }
+
+    var productData *Product
+    etag := ""
+    if cached != nil {
+        productData, etag = &cached.Product, cached.ETag
+    } else {
+        productData, err = loadProduct(ctx, productID)
+        if errors.Is(err, sql.ErrNoRows) {
+            respond.Error(c, http.StatusNotFound, respond.CodeNotFound, "product not found")
+            return
+        }
+        if err != nil {
+            logger.ErrorContext(ctx, "Failed to fetch from DB", "product_id", productID, "error", err)
+            respond.BackendError(c, err, "failed to fetch from DB")
+            return
+        }
+    }
+
+    // 재고는 주문이 수시로 바꾸므로 캐시하지 않고 매번 읽음. 재고가 ETag에 반영되도록 다시 계산함
+    if inventoryTable != "" {
+        stock, err := getStock(ctx, productID)
+        if err != nil {
+            respond.BackendError(c, err, "failed to fetch stock")
+            return
+        }
+        productData.Stock = &stock
+        etag = ""
+    }
+
+    respondProduct(c, productData, etag)
+}
+
+func createProduct(c *gin.Context) {
+    ctx := c.Request.Context()
+    var product Product
+    if err := c.ShouldBindJSON(&product); err != nil {
+        if bodylimit.TooLarge(c, err) {
+            return
+        }
+        metrics.RecordValidationFailure(c, err)
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, err.Error())
+        return
+    }
+    if err := ids.Check("id", product.ID); err != nil {
+        metrics.RecordValidationField(c, "id")
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, err.Error())
+        return
+    }
+    if product.Stock != nil && inventoryTable == "" {
+        metrics.RecordValidationField(c, "stock")
+        respond.Error(c, http.StatusUnprocessableEntity, respond.CodeUnprocessable, "stock tracking is disabled, set INVENTORY_TABLE")
+        return
+    }
+    if product.Stock != nil && *product.Stock < 0 {
+        metrics.RecordValidationField(c, "stock")
+        respond.Error(c, http.StatusUnprocessableEntity, respond.CodeUnprocessable, "stock must not be negative")
         return
     }
 
-    if productData != nil {
-        c.JSON(http.StatusOK, productData)
+    if !acquireCreateLock(ctx, product.ID) {
+        respond.Error(c, http.StatusConflict, respond.CodeConflict, "product is already being created")
         return
     }
 
-    productData, err = getFromDB(productID)
-    if err != nil {
-        log.Printf("Failed to fetch from DB for productID %s: %v", productID, err)
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch from DB"})
+    if err := saveToDB(ctx, &product); err != nil {
+        logger.ErrorContext(ctx, "Failed to save to DB", "product_id", product.ID, "error", err)
+        releaseCreateLock(ctx, product.ID)
+        respond.BackendError(c, err, "failed to save to DB")
         return
     }
 
-    saveToCache(productData)
+    // 재고 항목은 행을 넣은 뒤에 만들어 INSERT가 실패해도 재고 테이블에 고아 항목이 남지 않게 함.
+    // 그 사이 들어온 주문은 재고 항목이 없어 재고 부족(409)이 됨
+    if inventoryTable != "" {
+        stock := 0
+        if product.Stock != nil {
+            stock = *product.Stock
+        }
+        if err := seedStock(ctx, product.ID, stock); err != nil {
+            removeProductRow(ctx, &product)
+            releaseCreateLock(ctx, product.ID)
+            respond.BackendError(c, err, "failed to save stock")
+            return
+        }
+    }
+
+    saveToCache(ctx, &product)
 
-    c.JSON(http.StatusOK, productData)
+    respond.JSON(c, http.StatusCreated, gin.H{"message": "Product created successfully"})
 }
 
-func createProduct(c *gin.Context) {
+func updateProduct(c *gin.Context) {
+    ctx := c.Request.Context()
     var product Product
     if err := c.ShouldBindJSON(&product); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        if bodylimit.TooLarge(c, err) {
+            return
+        }
+        metrics.RecordValidationFailure(c, err)
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, err.Error())
+        return
+    }
+    // id 형식은 생성할 때만 검사함. 규칙이 생기기 전에 만든 id도 수정할 수 있어야 함
+    if product.ID == "" {
+        metrics.RecordValidationField(c, "id")
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "id is required")
         return
     }
 
-    if err := saveToDB(&product); err != nil {
-        log.Printf("Failed to save to DB for productID %s: %v", product.ID, err)
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save to DB"})
+    if product.Version <= 0 {
+        metrics.RecordValidationField(c, "version")
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "version is required")
         return
     }
 
-    saveToCache(&product)
+    expected := product.Version
+    err := updateInDB(ctx, &product)
+    if errors.Is(err, sql.ErrNoRows) {
+        respond.Error(c, http.StatusNotFound, respond.CodeNotFound, "product not found")
+        return
+    }
+    if errors.Is(err, errVersionConflict) {
+        respond.ErrorDetails(c, http.StatusConflict, respond.CodeConflict, fmt.Sprintf("product was modified, expected version %d but current version is %d", expected, product.Version), gin.H{"version": product.Version})
+        return
+    }
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to update DB", "product_id", product.ID, "error", err)
+        respond.BackendError(c, err, "failed to update DB")
+        return
+    }
 
-    c.JSON(http.StatusCreated, gin.H{"message": "Product created successfully"})
+    // 갱신 대신 삭제하여 다음 getProduct가 DB에서 다시 읽어 캐시를 채우게 함
+    deleteFromCache(ctx, product.ID)
+
+    respond.JSON(c, http.StatusOK, product)
 }
 
-func getFromCache(productID string) (*Product, error) {
-    val, err := redisClient.Get(ctx, productID).Result()
-    if err == redis.Nil {
-        log.Printf("No cache found for productID: %s", productID)
-        return nil, nil
-    } else if err != nil {
-        log.Printf("Error fetching from Redis for productID %s: %v", productID, err)
-        return nil, err
+// Redis 오류 시에는 생성을 막지 않고 DB 제약 조건에 맡김
+func acquireCreateLock(ctx context.Context, productID string) bool {
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    ok, err := redisConn.Client().SetNX(ctx, "create:"+productID, 1, dedupeTTL).Result()
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to acquire create lock", "product_id", productID, "error", err)
+        return true
+    }
+    if !ok {
+        logger.InfoContext(ctx, "Duplicate create within dedupe window", "product_id", productID)
     }
+    return ok
+}
+
+func releaseCreateLock(ctx context.Context, productID string) {
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := redisConn.Client().Del(ctx, "create:"+productID).Err(); err != nil {
+        logger.ErrorContext(ctx, "Failed to release create lock", "product_id", productID, "error", err)
+    }
+}
+
+// 호출할 때마다 만들어 재연결로 바뀐 Redis 클라이언트와 CACHE_TTL_SECONDS 값을 그대로 씀
+func productCache() *cacheaside.Cache {
+    return &cacheaside.Cache{
+        Store:  cacheaside.RedisStore(redisConn.Client),
+        TTL:    cacheTTL,
+        IDKey:  "product_id",
+        Logger: logger,
+        Begin: func(ctx context.Context, op, key string) (context.Context, func()) {
+            ctx, span := tracing.Start(ctx, op, "product_id", key)
+            ctx, cancel := backend.WithTimeout(ctx)
+            return ctx, func() {
+                cancel()
+                span.End()
+            }
+        },
+        Fail:   func() error { return chaos.Inject(chaosCacheFailRate) },
+        Record: cachestats.Record,
+    }
+}
+
+func getFromCache(ctx context.Context, productID string) (*cachedProduct, error) {
+    return cacheaside.Get[cachedProduct](ctx, productCache(), productID)
+}
+
+// 재고는 캐시에 넣지 않음
+func saveToCache(ctx context.Context, product *Product) {
+    cached := *product
+    cached.Stock = nil
+    productCache().Set(ctx, cached.ID, cachedProduct{Product: cached, ETag: productETag(&cached)})
+}
+
+// 관리용 조회. 삭제된 상품도 deletedat과 함께 반환하며 캐시는 거치지 않음
+func getProductWithDeleted(c *gin.Context, productID string) {
+    ctx, cancel := backend.WithTimeout(c.Request.Context())
+    defer cancel()
 
     var product Product
-    err = json.Unmarshal([]byte(val), &product)
+    err := selectProductWithDeletedStmt.GetContext(ctx, &product, productID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respond.Error(c, http.StatusNotFound, respond.CodeNotFound, "product not found")
+        return
+    }
     if err != nil {
-        log.Printf("Error unmarshalling data for productID %s: %v", productID, err)
-        return nil, err
+        logger.ErrorContext(ctx, "Failed to fetch from DB", "product_id", productID, "error", err)
+        respond.BackendError(c, err, "failed to fetch from DB")
+        return
     }
 
-    return &product, nil
+    respondProduct(c, &product, "")
 }
 
-func saveToCache(product *Product) {
-    data, err := json.Marshal(product)
-    if err != nil {
-        log.Printf("Failed to marshal product: %v", err)
+// 행을 지우지 않고 deleted_at만 채움. 이미 삭제된 상품이면 404
+func deleteProduct(c *gin.Context) {
+    ctx := c.Request.Context()
+    productID := c.Query("id")
+    if productID == "" {
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "id is required")
         return
     }
 
-    err = redisClient.Set(ctx, product.ID, data, 0).Err()
+    err := softDeleteInDB(ctx, productID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respond.Error(c, http.StatusNotFound, respond.CodeNotFound, "product not found")
+        return
+    }
     if err != nil {
-        log.Printf("Failed to save to cache for productID %s: %v", product.ID, err)
-    } else {
-        log.Printf("Successfully saved to cache for productID %s", product.ID)
+        logger.ErrorContext(ctx, "Failed to delete from DB", "product_id", productID, "error", err)
+        respond.BackendError(c, err, "failed to delete from DB")
+        return
     }
+
+    deleteFromCache(ctx, productID)
+
+    c.Status(http.StatusNoContent)
+}
+
+func deleteFromCache(ctx context.Context, productID string) {
+    productCache().Delete(ctx, productID)
 }
 
-func getFromDB(productID string) (*Product, error) {
-    sqlQuery := "SELECT id, name, category FROM product WHERE id = ?"
+// 같은 id에 대한 동시 조회는 쿼리 한 번의 결과를 나눠 씀. 먼저 온 요청이 취소돼도
+// 나머지가 실패하지 않도록 공유 쿼리는 취소를 상속하지 않고 백엔드 타임아웃만 적용받음
+func getFromDB(ctx context.Context, productID string) (*Product, error) {
+    ctx, span := tracing.Start(ctx, "getFromDB", "product_id", productID)
+    defer span.End()
+    ch := dbReads.DoChan(productID, func() (interface{}, error) {
+        return queryProduct(context.WithoutCancel(ctx), productID)
+    })
+
+    select {
+    case <-ctx.Done():
+        return nil, ctx.Err()
+    case res := <-ch:
+        if res.Err != nil {
+            return nil, res.Err
+        }
+        // 호출자끼리 같은 값을 공유하지 않도록 복사해서 돌려줌
+        product := *res.Val.(*Product)
+        return &product, nil
+    }
+}
+
+func queryProduct(ctx context.Context, productID string) (*Product, error) {
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosDBFailRate); err != nil {
+        return nil, err
+    }
+
     var product Product
-    err := db.Get(&product, sqlQuery, productID)
+    err := dbreconnect.Do(ctx, db, func() error {
+        return selectProductStmt.GetContext(ctx, &product, productID)
+    })
     if err != nil {
-        log.Printf("Error fetching from DB for productID %s: %v", productID, err)
+        logger.ErrorContext(ctx, "Error fetching from DB", "product_id", productID, "error", err)
         return nil, err
     }
     return &product, nil
 }
 
-func saveToDB(product *Product) error {
-    sqlQuery := `INSERT INTO product (id, name, category) VALUES (?, ?, ?)`
-    _, err := db.Exec(sqlQuery, product.ID, product.Name, product.Category)
+func saveToDB(ctx context.Context, product *Product) error {
+    ctx, span := tracing.Start(ctx, "saveToDB", "product_id", product.ID)
+    defer span.End()
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosDBFailRate); err != nil {
+        return err
+    }
+
+    product.CreatedAt = recordTimestamp()
+    product.UpdatedAt = product.CreatedAt
+    product.Version = 1
+    err := dbreconnect.DoInsert(ctx, db, func() error {
+        _, err := insertProductStmt.ExecContext(ctx, product.ID, product.Name, product.Category, product.CreatedAt, product.UpdatedAt)
+        return err
+    }, func() (bool, error) {
+        return productStored(ctx, product)
+    })
+    if err != nil {
+        logger.ErrorContext(ctx, "Error saving to DB", "product_id", product.ID, "error", err)
+        return err
+    }
+    logger.InfoContext(ctx, "Successfully saved to DB", "product_id", product.ID)
+    return nil
+}
+
+// 재고를 기록하지 못한 생성을 되돌림. created_at까지 맞춰 이번 요청이 넣은 행만 지움
+func removeProductRow(ctx context.Context, product *Product) {
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    _, err := sqlq.New("DELETE FROM product WHERE id = ? AND created_at = ?", product.ID, product.CreatedAt).ExecContext(ctx, db)
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to remove product after stock seeding failed", "product_id", product.ID, "error", err)
+    }
+}
+
+// 저장된 행이 이 상품과 같은지 확인함. created_at은 요청마다 달라 다른 요청이 넣은 행과 구분됨
+func productStored(ctx context.Context, product *Product) (bool, error) {
+    var stored Product
+    if err := selectProductWithDeletedStmt.GetContext(ctx, &stored, product.ID); err != nil {
+        return false, err
+    }
+    return stored.Name == product.Name && stored.Category == product.Category && stored.CreatedAt.Equal(product.CreatedAt), nil
+}
+
+// 대상 행이 없으면 sql.ErrNoRows, version이 다르면 errVersionConflict를 반환함
+func updateInDB(ctx context.Context, product *Product) error {
+    ctx, span := tracing.Start(ctx, "updateInDB", "product_id", product.ID)
+    defer span.End()
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosDBFailRate); err != nil {
+        return err
+    }
+
+    product.UpdatedAt = recordTimestamp()
+    result, err := updateProductStmt.ExecContext(ctx, product.Name, product.Category, product.UpdatedAt, product.ID, product.Version)
+    if err != nil {
+        logger.ErrorContext(ctx, "Error updating DB", "product_id", product.ID, "error", err)
+        return err
+    }
+    rows, err := result.RowsAffected()
     if err != nil {
-        log.Printf("Error saving to DB for productID %s: %v", product.ID, err)
         return err
     }
-    log.Printf("Successfully saved to DB for productID %s", product.ID)
+    if rows == 0 {
+        // 행이 없는 경우와 version이 달라 갱신하지 못한 경우를 구분함. 충돌이면 현재 version을 product에 채움
+        if err := selectProductStmt.GetContext(ctx, product, product.ID); err != nil {
+            return err
+        }
+        return errVersionConflict
+    }
+    logger.InfoContext(ctx, "Successfully updated DB", "product_id", product.ID)
+
+    // 응답에 created_at을 채우기 위해 갱신된 행을 다시 읽음
+    return selectProductStmt.GetContext(ctx, product, product.ID)
+}
+
+func softDeleteInDB(ctx context.Context, productID string) error {
+    ctx, span := tracing.Start(ctx, "softDeleteInDB", "product_id", productID)
+    defer span.End()
+    ctx, cancel := backend.WithTimeout(ctx)
+    defer cancel()
+
+    if err := chaos.Inject(chaosDBFailRate); err != nil {
+        return err
+    }
+
+    now := recordTimestamp()
+    result, err := deleteProductStmt.ExecContext(ctx, now, now, productID)
+    if err != nil {
+        logger.ErrorContext(ctx, "Error deleting from DB", "product_id", productID, "error", err)
+        return err
+    }
+    rows, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if rows == 0 {
+        return sql.ErrNoRows
+    }
+    logger.InfoContext(ctx, "Successfully soft-deleted product", "product_id", productID)
     return nil
 }
@@ -0,0 +1,149 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+)
+
+// 없는 product는 404, DB에 닿지 못한 경우는 500으로 구분함
+func TestGetProductNotFoundVsDBError(t *testing.T) {
+    useMiniredis(t)
+    conn := useTestDB(t)
+    router := newRouter()
+
+    w := doRequest(router, http.MethodGet, "/v1/product?id=missing", nil, nil)
+    if w.Code != http.StatusNotFound {
+        t.Errorf("no rows: status %d, want 404 (%s)", w.Code, w.Body)
+    }
+
+    conn.Close()
+    w = doRequest(router, http.MethodGet, "/v1/product?id=other", nil, nil)
+    if w.Code != http.StatusInternalServerError {
+        t.Errorf("connection error: status %d, want 500 (%s)", w.Code, w.Body)
+    }
+}
+
+// 생성 시 두 시각을 같게 채우고, 수정은 updatedat만 바꿈
+func TestProductTimestamps(t *testing.T) {
+    useMiniredis(t)
+    useTestDB(t)
+    created := time.Date(2024, 3, 5, 14, 7, 9, 123456789, time.UTC)
+    fake := clock.NewFake(created)
+    t.Cleanup(clock.Set(fake))
+    router := newRouter()
+
+    body := map[string]interface{}{"id": "p1", "name": "lamp", "category": "home"}
+    if w := doRequest(router, http.MethodPost, "/v1/product", body, nil); w.Code != http.StatusCreated {
+        t.Fatalf("create: status %d, want 201 (%s)", w.Code, w.Body)
+    }
+    fake.Advance(time.Hour)
+    body["name"], body["version"] = "desk lamp", 1
+    if w := doRequest(router, http.MethodPut, "/v1/product", body, nil); w.Code != http.StatusOK {
+        t.Fatalf("update: status %d, want 200 (%s)", w.Code, w.Body)
+    }
+
+    stored, err := queryProduct(context.Background(), "p1")
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := created.Truncate(time.Microsecond)
+    if !stored.CreatedAt.Equal(want) || !stored.UpdatedAt.Equal(want.Add(time.Hour)) {
+        t.Errorf("stored createdat %v updatedat %v, want %v and %v", stored.CreatedAt, stored.UpdatedAt, want, want.Add(time.Hour))
+    }
+}
+
+// 삭제해도 행은 남고 일반 조회와 목록에서는 빠지며, include_deleted=true로는 deletedat과 함께 읽힘
+func TestSoftDeleteProduct(t *testing.T) {
+    mr := useMiniredis(t)
+    conn := useTestDB(t)
+    insertProduct(t, "p1", "lamp", "home")
+    insertProduct(t, "p2", "mug", "home")
+    now := time.Date(2024, 3, 5, 14, 7, 9, 0, time.UTC)
+    t.Cleanup(clock.Set(clock.NewFake(now)))
+    router := newRouter()
+
+    if w := doRequest(router, http.MethodGet, "/v1/product?id=p1", nil, nil); w.Code != http.StatusOK {
+        t.Fatalf("get before delete: status %d, want 200", w.Code)
+    }
+    if w := doRequest(router, http.MethodDelete, "/v1/product?id=p1", nil, nil); w.Code != http.StatusNoContent {
+        t.Fatalf("delete: status %d, want 204 (%s)", w.Code, w.Body)
+    }
+    if mr.Exists("p1") {
+        t.Error("cache entry survived delete")
+    }
+
+    var rows int
+    if err := conn.Get(&rows, "SELECT COUNT(*) FROM product WHERE id = 'p1' AND deleted_at IS NOT NULL"); err != nil || rows != 1 {
+        t.Errorf("soft-deleted rows = %d, %v, want 1", rows, err)
+    }
+    if w := doRequest(router, http.MethodGet, "/v1/product?id=p1", nil, nil); w.Code != http.StatusNotFound {
+        t.Errorf("get after delete: status %d, want 404", w.Code)
+    }
+    if w := doRequest(router, http.MethodGet, "/v1/products?category=home", nil, nil); strings.Contains(w.Body.String(), `"p1"`) {
+        t.Errorf("category list %s still has p1", w.Body)
+    }
+
+    w := doRequest(router, http.MethodGet, "/v1/product?id=p1&include_deleted=true", nil, nil)
+    var got Product
+    if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+        t.Fatal(err)
+    }
+    if w.Code != http.StatusOK || got.DeletedAt == nil || !got.DeletedAt.Equal(now) {
+        t.Errorf("include_deleted: status %d, got %+v, want deletedat %v", w.Code, got, now)
+    }
+    w = doRequest(router, http.MethodGet, "/v1/product?id=p2&include_deleted=true", nil, nil)
+    if w.Code != http.StatusOK || strings.Contains(w.Body.String(), "deletedat") {
+        t.Errorf("include_deleted on live product: status %d body %s, want 200 without deletedat", w.Code, w.Body)
+    }
+
+    if w := doRequest(router, http.MethodDelete, "/v1/product?id=p1", nil, nil); w.Code != http.StatusNotFound {
+        t.Errorf("second delete: status %d, want 404", w.Code)
+    }
+}
+
+// 오래된 version으로 수정하면 409와 현재 version을 돌려주고 먼저 저장된 값은 그대로임
+func TestUpdateProductVersionConflict(t *testing.T) {
+    useMiniredis(t)
+    useTestDB(t)
+    insertProduct(t, "p1", "lamp", "home")
+    router := newRouter()
+
+    first := map[string]interface{}{"id": "p1", "name": "desk lamp", "category": "home", "version": 1}
+    if w := doRequest(router, http.MethodPut, "/v1/product", first, nil); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"version":2`) {
+        t.Fatalf("first update: status %d body %s, want 200 at version 2", w.Code, w.Body)
+    }
+
+    stale := map[string]interface{}{"id": "p1", "name": "floor lamp", "category": "home", "version": 1}
+    w := doRequest(router, http.MethodPut, "/v1/product", stale, nil)
+    var resp struct {
+        Code    string `json:"code"`
+        Details struct {
+            Version int `json:"version"`
+        } `json:"details"`
+    }
+    if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+        t.Fatal(err)
+    }
+    if w.Code != http.StatusConflict || resp.Code != respond.CodeConflict || resp.Details.Version != 2 {
+        t.Errorf("stale update: status %d body %s, want 409 with current version 2", w.Code, w.Body)
+    }
+    if stored, err := queryProduct(context.Background(), "p1"); err != nil || stored.Name != "desk lamp" || stored.Version != 2 {
+        t.Errorf("stored = %+v, %v, want desk lamp at version 2", stored, err)
+    }
+
+    delete(stale, "version")
+    if w := doRequest(router, http.MethodPut, "/v1/product", stale, nil); w.Code != http.StatusBadRequest {
+        t.Errorf("missing version: status %d, want 400 (%s)", w.Code, w.Body)
+    }
+    missing := map[string]interface{}{"id": "nope", "name": "x", "category": "home", "version": 1}
+    if w := doRequest(router, http.MethodPut, "/v1/product", missing, nil); w.Code != http.StatusNotFound {
+        t.Errorf("unknown product: status %d, want 404 (%s)", w.Code, w.Body)
+    }
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+    "net/http"
+    "strconv"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/backend"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+    "github.com/gmstcl/eCommerce-System/internal/sqlq"
+)
+
+const (
+    searchDefaultLimit = 20
+    searchMaxLimit     = 100
+    maxSearchQuery     = 100
+)
+
+// 이름에 q가 포함된 상품을 id 순으로 최대 limit개 반환함. q의 %와 _는 와일드카드가 아닌 문자로 취급함
+func searchProducts(c *gin.Context) {
+    q := strings.TrimSpace(c.Query("q"))
+    if q == "" {
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "q is required")
+        return
+    }
+    if len(q) > maxSearchQuery {
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "q must be at most 100 characters")
+        return
+    }
+
+    limit := searchDefaultLimit
+    if v := c.Query("limit"); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil || n <= 0 || n > searchMaxLimit {
+            respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, "limit must be between 1 and 100")
+            return
+        }
+        limit = n
+    }
+
+    ctx, cancel := backend.WithTimeout(c.Request.Context())
+    defer cancel()
+
+    products := []Product{}
+    err := sqlq.New("SELECT id, name, category, version, created_at, updated_at FROM product WHERE name LIKE ? ESCAPE '!'", sqlq.LikeContains(q)).
+        Add(" AND deleted_at IS NULL ORDER BY id LIMIT ?", limit).
+        SelectContext(ctx, db, &products)
+    if err != nil {
+        logger.ErrorContext(ctx, "Error searching products", "query", q, "error", err)
+        respond.BackendError(c, err, "failed to search products")
+        return
+    }
+
+    respond.JSON(c, http.StatusOK, products)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/url"
+    "strings"
+    "testing"
+)
+
+// 비어 있거나 공백뿐이거나 너무 긴 q는 DB를 읽지 않고 400
+func TestSearchProductsRejectsEmptyQuery(t *testing.T) {
+    router := newRouter()
+    for _, query := range []string{"", "q=", "q=" + url.QueryEscape("   "), "q=" + strings.Repeat("a", maxSearchQuery+1), "q=lamp&limit=0", "q=lamp&limit=101"} {
+        if w := doRequest(router, http.MethodGet, "/v1/products/search?"+query, nil, nil); w.Code != http.StatusBadRequest {
+            t.Errorf("GET /v1/products/search?%.30s: status %d, want 400 (%s)", query, w.Code, w.Body)
+        }
+    }
+}
+
+// 이름의 일부로 찾고, %와 _는 문자 그대로 비교함
+func TestSearchProducts(t *testing.T) {
+    useTestDB(t)
+    insertProduct(t, "p1", "desk lamp", "home")
+    insertProduct(t, "p2", "lamp shade", "home")
+    insertProduct(t, "p3", "100% cotton", "clothes")
+    insertProduct(t, "p4", "mug", "home")
+    router := newRouter()
+
+    tests := []struct {
+        q    string
+        want string
+    }{
+        {"lamp", "p1 p2"},
+        {" lamp ", "p1 p2"},
+        {"%", "p3"},
+        {"_", ""},
+        {"chair", ""},
+    }
+    for _, tt := range tests {
+        w := doRequest(router, http.MethodGet, "/v1/products/search?q="+url.QueryEscape(tt.q), nil, nil)
+        var products []Product
+        if err := json.Unmarshal(w.Body.Bytes(), &products); w.Code != http.StatusOK || err != nil || products == nil {
+            t.Fatalf("q=%q: status %d body %s, want 200 with an array", tt.q, w.Code, w.Body)
+        }
+        var ids []string
+        for _, product := range products {
+            ids = append(ids, product.ID)
+        }
+        if got := strings.Join(ids, " "); got != tt.want {
+            t.Errorf("q=%q: got %q, want %q", tt.q, got, tt.want)
+        }
+    }
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+    "context"
+    "flag"
+    "fmt"
+    "log"
+    "os"
+    "time"
+
+    "github.com/gmstcl/eCommerce-System/internal/clock"
+    "github.com/gmstcl/eCommerce-System/internal/sqlq"
+)
+
+var selfTestFlag = flag.Bool("selftest", false, "run a round-trip against each dependency and exit")
+
+// 배포 파이프라인에서 새 이미지의 연결 상태를 확인하기 위한 모드. HTTP 서버는 띄우지 않음
+func selfTestRequested() bool {
+    return *selfTestFlag || os.Getenv("SELFTEST") == "true"
+}
+
+func runSelfTest() int {
+    ctx := context.Background()
+    id := fmt.Sprintf("selftest-%d", clock.Now().UnixNano())
+    checks := []struct {
+        name string
+        run  func(context.Context, string) error
+    }{
+        {"mysql", selfTestDB},
+        {"redis", selfTestCache},
+    }
+
+    status := 0
+    for _, check := range checks {
+        if err := check.run(ctx, id); err != nil {
+            log.Printf("selftest %s: FAIL: %v", check.name, err)
+            status = 1
+        } else {
+            log.Printf("selftest %s: OK", check.name)
+        }
+    }
+    return status
+}
+
+func selfTestDB(ctx context.Context, id string) error {
+    if _, err := sqlq.New("INSERT INTO product (id, name, category) VALUES (?, ?, ?)", id, "selftest", "selftest").ExecContext(ctx, db); err != nil {
+        return fmt.Errorf("insert: %w", err)
+    }
+    deleteRow := sqlq.New("DELETE FROM product WHERE id = ?", id)
+    defer deleteRow.ExecContext(ctx, db)
+
+    var got string
+    if err := sqlq.New("SELECT id FROM product WHERE id = ?", id).GetContext(ctx, db, &got); err != nil {
+        return fmt.Errorf("select: %w", err)
+    }
+
+    if _, err := deleteRow.ExecContext(ctx, db); err != nil {
+        return fmt.Errorf("delete: %w", err)
+    }
+    return nil
+}
+
+func selfTestCache(ctx context.Context, id string) error {
+    if err := redisConn.Client().Set(ctx, id, "ok", 30*time.Second).Err(); err != nil {
+        return fmt.Errorf("set: %w", err)
+    }
+
+    val, err := redisConn.Client().Get(ctx, id).Result()
+    if err != nil {
+        return fmt.Errorf("get: %w", err)
+    }
+    if val != "ok" {
+        return fmt.Errorf("get: unexpected value %q", val)
+    }
+
+    if err := redisConn.Client().Del(ctx, id).Err(); err != nil {
+        return fmt.Errorf("del: %w", err)
+    }
+    return nil
+}
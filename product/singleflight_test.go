@@ -0,0 +1,36 @@
+package main
+
+import (
+    "context"
+    "sync"
+    "testing"
+    "time"
+)
+
+// 같은 id를 동시에 조회하면 쿼리 한 번의 결과를 나눠 받고, 각자 다른 복사본을 받음
+func TestConcurrentGetFromDBSharesOneQuery(t *testing.T) {
+    counter := useCountingDB(t, 50*time.Millisecond)
+
+    var wg sync.WaitGroup
+    products := make([]*Product, 20)
+    for i := range products {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            product, err := getFromDB(context.Background(), "p1")
+            if err != nil {
+                t.Error(err)
+                return
+            }
+            products[i] = product
+        }(i)
+    }
+    wg.Wait()
+
+    if n := counter.queries.Load(); n != 1 {
+        t.Errorf("query ran %d times, want 1", n)
+    }
+    if products[0] == products[1] {
+        t.Error("callers share the same *Product")
+    }
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+    "database/sql"
+    "errors"
+    "fmt"
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+    "github.com/gmstcl/eCommerce-System/internal/bodylimit"
+    "github.com/gmstcl/eCommerce-System/internal/metrics"
+    "github.com/gmstcl/eCommerce-System/internal/respond"
+)
+
+// delta가 0이면 바뀌는 것이 없으므로 빠진 값과 구분하기 위해 포인터로 받음
+type stockAdjustment struct {
+    Delta *int `json:"delta" binding:"required"`
+}
+
+// 재고를 delta만큼 더하거나 빼고 바뀐 상품을 반환함. 결과가 음수가 되면 422.
+// 재고는 INVENTORY_TABLE에 있고 상품 캐시에 넣지 않으므로 재고 여부 캐시만 지움
+func adjustStock(c *gin.Context) {
+    ctx := c.Request.Context()
+    productID := c.Param("id")
+
+    if inventoryTable == "" {
+        respond.Error(c, http.StatusNotImplemented, respond.CodeNotImplemented, "stock tracking is disabled, set INVENTORY_TABLE")
+        return
+    }
+
+    var adjustment stockAdjustment
+    if err := c.ShouldBindJSON(&adjustment); err != nil {
+        if bodylimit.TooLarge(c, err) {
+            return
+        }
+        metrics.RecordValidationFailure(c, err)
+        respond.Error(c, http.StatusBadRequest, respond.CodeInvalidRequest, err.Error())
+        return
+    }
+
+    // 없는 상품에 재고 항목이 생기지 않도록 MySQL에서 먼저 확인함
+    product, err := getFromDB(ctx, productID)
+    if errors.Is(err, sql.ErrNoRows) {
+        respond.Error(c, http.StatusNotFound, respond.CodeNotFound, "product not found")
+        return
+    }
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to fetch from DB", "product_id", productID, "error", err)
+        respond.BackendError(c, err, "failed to fetch from DB")
+        return
+    }
+
+    stock, err := adjustStockInInventory(ctx, productID, *adjustment.Delta)
+    if errors.Is(err, errStockUnderflow) {
+        respond.ErrorDetails(c, http.StatusUnprocessableEntity, respond.CodeUnprocessable, fmt.Sprintf("stock is %d, cannot apply delta %d", stock, *adjustment.Delta), gin.H{"stock": stock})
+        return
+    }
+    if err != nil {
+        logger.ErrorContext(ctx, "Failed to adjust stock", "product_id", productID, "error", err)
+        respond.BackendError(c, err, "failed to adjust stock")
+        return
+    }
+
+    // 짧게 캐시한 재고 여부도 바로 맞춤
+    availabilityCache().Delete(ctx, productID)
+    product.Stock = &stock
+    respondProduct(c, product, "")
+}
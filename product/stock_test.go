@@ -0,0 +1,180 @@
+package main
+
+import (
+    "context"
+    "database/sql"
+    "encoding/json"
+    "errors"
+    "net/http"
+    "strconv"
+    "sync"
+    "testing"
+)
+
+// 재고 테이블 하나를 흉내 냄. UpdateItem은 ADD와 inventory >= :required 조건만 처리함
+type fakeInventory struct {
+    mu    sync.Mutex
+    stock map[string]int
+}
+
+func (f *fakeInventory) handle(op string, body map[string]interface{}) (int, interface{}) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    key, ok := body["Key"].(map[string]interface{})
+    if !ok {
+        key, _ = body["Item"].(map[string]interface{})
+    }
+    id, _ := key["productid"].(map[string]interface{})["S"].(string)
+    values, _ := body["ExpressionAttributeValues"].(map[string]interface{})
+    number := func(name string) int {
+        n, _ := strconv.Atoi(values[name].(map[string]interface{})["N"].(string))
+        return n
+    }
+
+    switch op {
+    case "GetItem":
+        if stock, ok := f.stock[id]; ok {
+            return http.StatusOK, map[string]interface{}{"Item": inventoryAttrs(stock)}
+        }
+        return http.StatusOK, map[string]interface{}{}
+    case "UpdateItem":
+        if _, ok := values[":required"]; ok && f.stock[id] < number(":required") {
+            return dynamoError("ConditionalCheckFailedException", "The conditional request failed")
+        }
+        f.stock[id] += number(":delta")
+        return http.StatusOK, map[string]interface{}{"Attributes": inventoryAttrs(f.stock[id])}
+    }
+    return http.StatusOK, map[string]interface{}{}
+}
+
+func useInventory(t *testing.T, stock map[string]int) *fakeDynamo {
+    t.Helper()
+    prev := inventoryTable
+    inventoryTable = "inventory"
+    t.Cleanup(func() { inventoryTable = prev })
+    inventory := &fakeInventory{stock: stock}
+    return newFakeDynamo(t, inventory.handle)
+}
+
+func decodeProduct(t *testing.T, body []byte) Product {
+    t.Helper()
+    var product Product
+    if err := json.Unmarshal(body, &product); err != nil {
+        t.Fatalf("decode product: %v (%s)", err, body)
+    }
+    return product
+}
+
+func TestAdjustStock(t *testing.T) {
+    useTestDB(t)
+    useMiniredis(t)
+    insertProduct(t, "p1", "pen", "office")
+    useInventory(t, map[string]int{"p1": 5})
+    router := newRouter()
+
+    tests := []struct {
+        name   string
+        delta  int
+        status int
+        stock  int
+    }{
+        {"increment", 3, http.StatusOK, 8},
+        {"decrement", -6, http.StatusOK, 2},
+        {"underflow", -3, http.StatusUnprocessableEntity, 2},
+    }
+    for _, tt := range tests {
+        w := doRequest(router, http.MethodPost, "/v1/product/p1/stock", map[string]int{"delta": tt.delta}, nil)
+        if w.Code != tt.status {
+            t.Fatalf("%s: status %d, want %d (%s)", tt.name, w.Code, tt.status, w.Body)
+        }
+        if tt.status == http.StatusOK {
+            if product := decodeProduct(t, w.Body.Bytes()); product.Stock == nil || *product.Stock != tt.stock {
+                t.Errorf("%s: stock %v, want %d", tt.name, product.Stock, tt.stock)
+            }
+        }
+    }
+
+    // 조회는 MySQL이 아니라 재고 테이블의 현재 값을 돌려줌
+    w := doRequest(router, http.MethodGet, "/v1/product?id=p1", nil, nil)
+    if product := decodeProduct(t, w.Body.Bytes()); product.Stock == nil || *product.Stock != 2 {
+        t.Errorf("GET stock %v, want 2", product.Stock)
+    }
+}
+
+func TestAdjustStockUnknownProduct(t *testing.T) {
+    useTestDB(t)
+    useMiniredis(t)
+    fake := useInventory(t, map[string]int{})
+
+    w := doRequest(newRouter(), http.MethodPost, "/v1/product/missing/stock", map[string]int{"delta": 1}, nil)
+    if w.Code != http.StatusNotFound {
+        t.Fatalf("status %d, want 404 (%s)", w.Code, w.Body)
+    }
+    if calls := fake.callsTo("UpdateItem"); len(calls) != 0 {
+        t.Errorf("created inventory for a product that does not exist")
+    }
+}
+
+func TestAdjustStockDisabledWithoutInventoryTable(t *testing.T) {
+    w := doRequest(newRouter(), http.MethodPost, "/v1/product/p1/stock", map[string]int{"delta": 1}, nil)
+    if w.Code != http.StatusNotImplemented {
+        t.Fatalf("status %d, want 501 (%s)", w.Code, w.Body)
+    }
+}
+
+// 생성 시 본문의 stock은 MySQL이 아니라 재고 테이블에 초기값으로 기록됨
+func TestCreateProductSeedsInventory(t *testing.T) {
+    useTestDB(t)
+    useMiniredis(t)
+    fake := useInventory(t, map[string]int{})
+
+    w := doRequest(newRouter(), http.MethodPost, "/v1/product", map[string]interface{}{"id": "p1", "name": "pen", "category": "office", "stock": 4}, nil)
+    if w.Code != http.StatusCreated {
+        t.Fatalf("status %d, want 201 (%s)", w.Code, w.Body)
+    }
+    puts := fake.callsTo("PutItem")
+    if len(puts) != 1 {
+        t.Fatalf("PutItem called %d times, want 1", len(puts))
+    }
+    item := puts[0].Body["Item"].(map[string]interface{})
+    if n := item["inventory"].(map[string]interface{})["N"]; n != "4" {
+        t.Errorf("seeded inventory %v, want 4", n)
+    }
+}
+
+// INSERT가 실패하면 재고 항목을 만들지 않음
+func TestCreateProductInsertFailureSkipsSeeding(t *testing.T) {
+    useTestDB(t)
+    useMiniredis(t)
+    fake := useInventory(t, map[string]int{})
+    insertProduct(t, "p1", "pen", "office")
+
+    w := doRequest(newRouter(), http.MethodPost, "/v1/product", map[string]interface{}{"id": "p1", "name": "pencil", "category": "office", "stock": 4}, nil)
+    if w.Code == http.StatusCreated {
+        t.Fatalf("status 201 for a duplicate id (%s)", w.Body)
+    }
+    if puts := fake.callsTo("PutItem"); len(puts) != 0 {
+        t.Errorf("PutItem called %d times after a failed insert", len(puts))
+    }
+}
+
+// 재고를 기록하지 못하면 넣은 행을 지워 재고 없는 상품이 남지 않게 함
+func TestCreateProductSeedFailureRemovesRow(t *testing.T) {
+    useTestDB(t)
+    useMiniredis(t)
+    prev := inventoryTable
+    inventoryTable = "inventory"
+    t.Cleanup(func() { inventoryTable = prev })
+    newFakeDynamo(t, func(op string, body map[string]interface{}) (int, interface{}) {
+        return dynamoError("InternalServerError", "internal error")
+    })
+
+    w := doRequest(newRouter(), http.MethodPost, "/v1/product", map[string]interface{}{"id": "p1", "name": "pen", "category": "office", "stock": 4}, nil)
+    if w.Code == http.StatusCreated {
+        t.Fatalf("status 201 although stock was not saved (%s)", w.Body)
+    }
+    if _, err := queryProduct(context.Background(), "p1"); !errors.Is(err, sql.ErrNoRows) {
+        t.Errorf("queryProduct after failed seeding: %v, want sql.ErrNoRows", err)
+    }
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+    "net/http"
+    "testing"
+)
+
+// id 규칙이 생기기 전에 만든 상품은 id 형식과 관계없이 수정할 수 있고, 같은 형식으로 새로 만들 수는 없음
+func TestIDRuleOnlyAppliesToCreate(t *testing.T) {
+    useTestDB(t)
+    useMiniredis(t)
+    insertProduct(t, "legacy_id", "lamp", "home")
+    router := newRouter()
+
+    w := doRequest(router, http.MethodPut, "/v1/product", map[string]interface{}{"id": "legacy_id", "name": "desk lamp", "category": "home", "version": 1}, nil)
+    if w.Code != http.StatusOK {
+        t.Errorf("update: status %d, want 200 (%s)", w.Code, w.Body)
+    }
+
+    w = doRequest(router, http.MethodPost, "/v1/product", map[string]interface{}{"id": "new_id", "name": "chair", "category": "home"}, nil)
+    if w.Code != http.StatusBadRequest {
+        t.Errorf("create: status %d, want 400 (%s)", w.Code, w.Body)
+    }
+}